@@ -0,0 +1,99 @@
+// Command audit checks the integrity of the security audit log.
+//
+//	audit verify [-checkpoints file]   walks the hash chain and reports its breaks, exiting 1 if any
+//	audit checkpoint [-o file]         signs the head of the chain, appending it to file or printing it
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/fyfirman/auth-management-go/internal/repository"
+	"github.com/fyfirman/auth-management-go/internal/service"
+	"github.com/fyfirman/auth-management-go/pkg/auditchain"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	if _, err := repository.ConnectDB(); err != nil {
+		log.Fatalf("Error connecting to database: %v", err)
+	}
+	auditService := service.NewAuditService(repository.NewAuditRepository(), nil)
+
+	switch os.Args[1] {
+	case "verify":
+		verify(auditService, os.Args[2:])
+	case "checkpoint":
+		checkpoint(auditService, os.Args[2:])
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: audit verify [-checkpoints file] | audit checkpoint [-o file]")
+	os.Exit(2)
+}
+
+func verify(auditService service.AuditServiceInterface, args []string) {
+	flags := flag.NewFlagSet("verify", flag.ExitOnError)
+	checkpointsPath := flags.String("checkpoints", os.Getenv("AUDIT_CHECKPOINT_FILE"),
+		"file of checkpoints to match the chain against")
+	flags.Parse(args)
+
+	var checkpoints []auditchain.Checkpoint
+	if *checkpointsPath != "" {
+		file, err := os.Open(*checkpointsPath)
+		if err != nil {
+			log.Fatalf("Failed to open the checkpoints: %v", err)
+		}
+		checkpoints, err = auditchain.ReadCheckpoints(file)
+		file.Close()
+		if err != nil {
+			log.Fatalf("Failed to read the checkpoints: %v", err)
+		}
+	}
+
+	report, err := auditService.VerifyChain(context.Background(), checkpoints)
+	if err != nil {
+		log.Fatalf("Failed to verify the audit chain: %v", err)
+	}
+
+	fmt.Printf("%d events, %d recorded before chaining, %d checkpoints checked\n",
+		report.Entries, report.Unchained, report.Checkpoints)
+	for _, chainBreak := range report.Breaks {
+		fmt.Printf("event %d: %s\n", chainBreak.ID, chainBreak.Reason)
+	}
+	if !report.OK() {
+		os.Exit(1)
+	}
+	fmt.Println("The audit chain is intact")
+}
+
+func checkpoint(auditService service.AuditServiceInterface, args []string) {
+	flags := flag.NewFlagSet("checkpoint", flag.ExitOnError)
+	output := flags.String("o", "", "file to append the checkpoint to, instead of printing it")
+	flags.Parse(args)
+
+	created, err := auditService.CreateCheckpoint(context.Background(), time.Now())
+	if err != nil {
+		log.Fatalf("Failed to create a checkpoint: %v", err)
+	}
+
+	if *output != "" {
+		if err := auditchain.AppendCheckpoint(*output, *created); err != nil {
+			log.Fatalf("Failed to export the checkpoint: %v", err)
+		}
+		return
+	}
+	line, _ := json.Marshal(created)
+	fmt.Println(string(line))
+}
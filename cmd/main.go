@@ -1,12 +1,26 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/fyfirman/auth-management-go/internal/app"
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
 	"github.com/fyfirman/auth-management-go/internal/repository"
 	"github.com/fyfirman/auth-management-go/internal/service"
+	"github.com/fyfirman/auth-management-go/pkg/auditchain"
+	"github.com/fyfirman/auth-management-go/pkg/auditsink"
+	"github.com/fyfirman/auth-management-go/pkg/mail_server"
+	"github.com/fyfirman/auth-management-go/pkg/policy"
+	"github.com/fyfirman/auth-management-go/pkg/ratelimit"
 )
 
 func main() {
@@ -18,18 +32,457 @@ func main() {
 
 	userRepository := repository.NewUserRepository()
 	tokenRepository := repository.NewTokenRepository()
+	roleRepository := repository.NewRoleRepository()
+	authzDecisionRepository := repository.NewAuthzDecisionRepository()
+	organizationRepository := repository.NewOrganizationRepository()
+	invitationRepository := repository.NewInvitationRepository()
+	groupRepository := repository.NewGroupRepository()
+	scimTokenRepository := repository.NewScimTokenRepository()
+	sessionRepository := repository.NewSessionRepository()
+	emailChangeRepository := repository.NewEmailChangeRepository()
+	dataExportRepository := repository.NewDataExportRepository()
+	auditRepository := repository.NewAuditRepository()
+	loginDeviceRepository := repository.NewLoginDeviceRepository()
 
-	userService := service.NewUserService(userRepository, tokenRepository)
+	auditSink, err := loadAuditSink()
+	if err != nil {
+		log.Fatalf("Failed to set up audit streaming: %v", err)
+	}
+	auditService := service.NewAuditService(auditRepository, auditSink)
+	auditHandler := app.NewAuditHandler(auditService)
+
+	userService := service.NewUserService(userRepository, tokenRepository, roleRepository, organizationRepository,
+		groupRepository, sessionRepository, mail_server.New(), auditService, loginDeviceRepository)
 	userHandler := app.NewUserHandler(userService)
 
-	http.HandleFunc("/register", userHandler.Register)
-	http.HandleFunc("/login", userHandler.Login)
-	http.HandleFunc("/forgot-password", userHandler.ForgotPassword)
-	http.HandleFunc("/reset-password", userHandler.ResetPassword)
+	accountService := service.NewAccountService(userRepository, sessionRepository, emailChangeRepository,
+		mail_server.New(), auditService)
+	accountHandler := app.NewAccountHandler(accountService)
+
+	dataExportService := service.NewDataExportService(dataExportRepository, userRepository, organizationRepository,
+		groupRepository, sessionRepository, auditRepository, mail_server.New())
+	dataExportHandler := app.NewDataExportHandler(dataExportService)
+
+	adminUserService := service.NewAdminUserService(userRepository, roleRepository, organizationRepository,
+		sessionRepository, auditService)
+	adminHandler := app.NewAdminHandler(adminUserService)
+
+	roleService := service.NewRoleService(roleRepository)
+	roleHandler := app.NewRoleHandler(roleService)
+
+	organizationService := service.NewOrganizationService(organizationRepository)
+	organizationHandler := app.NewOrganizationHandler(organizationService)
+
+	invitationService := service.NewInvitationService(invitationRepository, userRepository, roleRepository,
+		organizationRepository, mail_server.New())
+	invitationHandler := app.NewInvitationHandler(invitationService)
+
+	groupService := service.NewGroupService(groupRepository, userRepository, roleRepository, organizationRepository,
+		sessionRepository, auditService)
+	groupHandler := app.NewGroupHandler(groupService)
+
+	scimService := service.NewSCIMService(scimTokenRepository, userRepository, groupRepository,
+		organizationRepository, sessionRepository, auditService)
+	scimHandler := app.NewSCIMHandler(scimService)
+
+	policyEngine, err := loadPolicyEngine(os.Getenv("POLICY_FILE"))
+	if err != nil {
+		log.Fatalf("Failed to load policies: %v", err)
+	}
+	authzService := service.NewAuthzService(policyEngine, authzDecisionRepository)
+	authzHandler := app.NewAuthzHandler(authzService)
+
+	sessionService := service.NewSessionService(sessionRepository)
+	authenticated := func(next http.HandlerFunc) http.HandlerFunc {
+		return app.Authenticate(app.RequireSession(sessionService)(next))
+	}
+	can := func(permission string, next http.HandlerFunc) http.HandlerFunc {
+		return authenticated(app.RequirePermission(permission)(next))
+	}
+
+	rateLimitStore, err := loadRateLimitStore(os.Getenv("RATE_LIMIT_STORE"))
+	if err != nil {
+		log.Fatalf("Failed to set up rate limiting: %v", err)
+	}
+	trustForwardedFor := os.Getenv("RATE_LIMIT_TRUST_FORWARDED_FOR") == "true"
+	rateLimiter := app.NewRateLimiter(rateLimitStore, trustForwardedFor)
+	throttled := func(endpoint string, envKey string, fallback string, next http.HandlerFunc) http.HandlerFunc {
+		limit, err := loadRateLimit(envKey, fallback)
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", envKey, err)
+		}
+		return rateLimiter.Limit(endpoint, limit)(next)
+	}
+	// Endpoints emailing the account named in the request limit it across client IPs.
+	throttledMail := func(endpoint string, envKey string, fallback string, next http.HandlerFunc) http.HandlerFunc {
+		limit, err := loadRateLimit(envKey, fallback)
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", envKey, err)
+		}
+		return rateLimiter.LimitMail(endpoint, limit)(next)
+	}
+
+	http.HandleFunc("/register", throttled("register", "RATE_LIMIT_REGISTER", "5/1h", userHandler.Register))
+	http.HandleFunc("/login", throttled("login", "RATE_LIMIT_LOGIN", "10/1m", userHandler.Login))
+	http.HandleFunc("POST /login/verify",
+		throttled("login-verify", "RATE_LIMIT_LOGIN_VERIFY", "10/1m", userHandler.VerifyLogin))
+	http.HandleFunc("/forgot-password",
+		throttledMail("forgot-password", "RATE_LIMIT_FORGOT_PASSWORD", "5/1h", userHandler.ForgotPassword))
+	http.HandleFunc("/reset-password",
+		throttled("reset-password", "RATE_LIMIT_RESET_PASSWORD", "10/1h", userHandler.ResetPassword))
+	http.HandleFunc("POST /invitations/accept",
+		throttled("accept-invitation", "RATE_LIMIT_ACCEPT_INVITATION", "10/1h", invitationHandler.AcceptInvitation))
+
+	http.HandleFunc("GET /me", authenticated(accountHandler.GetProfile))
+	http.HandleFunc("PATCH /me", authenticated(accountHandler.UpdateProfile))
+	http.HandleFunc("DELETE /me", authenticated(accountHandler.DeleteAccount))
+	http.HandleFunc("POST /me/password",
+		app.AuthenticatePasswordChange(app.RequireSession(sessionService)(accountHandler.ChangePassword)))
+	http.HandleFunc("POST /me/email", authenticated(accountHandler.RequestEmailChange))
+	http.HandleFunc("POST /email-change/confirm",
+		throttled("email-change-confirm", "RATE_LIMIT_EMAIL_CHANGE", "10/1h", accountHandler.ConfirmEmailChange))
+	http.HandleFunc("POST /email-change/cancel",
+		throttled("email-change-cancel", "RATE_LIMIT_EMAIL_CHANGE", "10/1h", accountHandler.CancelEmailChange))
+	http.HandleFunc("POST /restore-account",
+		throttled("restore-account", "RATE_LIMIT_RESTORE_ACCOUNT", "5/1h", accountHandler.RestoreAccount))
+	http.HandleFunc("POST /me/export", authenticated(dataExportHandler.RequestExport))
+	http.HandleFunc("GET /exports/{token}",
+		throttled("export-download", "RATE_LIMIT_EXPORT_DOWNLOAD", "10/1m", dataExportHandler.DownloadExport))
+	http.HandleFunc("GET /me/organizations", authenticated(organizationHandler.ListMemberships))
+	http.HandleFunc("POST /me/organizations/switch", authenticated(userHandler.SwitchOrganization))
+
+	http.HandleFunc("GET /admin/audit-events", can(datastruct.PermissionAuditRead, auditHandler.ListEvents))
+	http.HandleFunc("GET /admin/users", can(datastruct.PermissionUsersRead, adminHandler.ListUsers))
+	http.HandleFunc("POST /admin/users/import", can(datastruct.PermissionUsersWrite, adminHandler.ImportUsers))
+	http.HandleFunc("GET /admin/users/{id}", can(datastruct.PermissionUsersRead, adminHandler.GetUser))
+	http.HandleFunc("PATCH /admin/users/{id}", can(datastruct.PermissionUsersWrite, adminHandler.UpdateUser))
+	http.HandleFunc("POST /admin/users/{id}/disable", can(datastruct.PermissionUsersWrite, adminHandler.DisableUser))
+	http.HandleFunc("POST /admin/users/{id}/enable", can(datastruct.PermissionUsersWrite, adminHandler.EnableUser))
+	http.HandleFunc("POST /admin/users/{id}/unlock", can(datastruct.PermissionUsersWrite, adminHandler.UnlockUser))
+	http.HandleFunc("DELETE /admin/users/{id}", can(datastruct.PermissionUsersDelete, adminHandler.DeleteUser))
+	http.HandleFunc("POST /admin/users/{id}/restore", can(datastruct.PermissionUsersDelete, adminHandler.RestoreUser))
+	http.HandleFunc("PUT /admin/users/{id}/role", can(datastruct.PermissionUsersAssignRole, adminHandler.ChangeUserRole))
+	http.HandleFunc("GET /admin/users/{id}/permissions", can(datastruct.PermissionUsersRead, groupHandler.EffectivePermissions))
+
+	http.HandleFunc("GET /admin/roles", can(datastruct.PermissionRolesRead, roleHandler.ListRoles))
+	http.HandleFunc("POST /admin/roles", can(datastruct.PermissionRolesWrite, roleHandler.CreateRole))
+	http.HandleFunc("GET /admin/roles/{id}", can(datastruct.PermissionRolesRead, roleHandler.GetRole))
+	http.HandleFunc("PATCH /admin/roles/{id}", can(datastruct.PermissionRolesWrite, roleHandler.UpdateRole))
+	http.HandleFunc("DELETE /admin/roles/{id}", can(datastruct.PermissionRolesWrite, roleHandler.DeleteRole))
+	http.HandleFunc("GET /admin/permissions", can(datastruct.PermissionRolesRead, roleHandler.ListPermissions))
+	http.HandleFunc("POST /admin/permissions", can(datastruct.PermissionRolesWrite, roleHandler.CreatePermission))
+
+	http.HandleFunc("GET /admin/organizations", can(datastruct.PermissionOrgsRead, organizationHandler.ListOrganizations))
+	http.HandleFunc("POST /admin/organizations", can(datastruct.PermissionOrgsWrite, organizationHandler.CreateOrganization))
+
+	http.HandleFunc("GET /admin/groups", can(datastruct.PermissionGroupsRead, groupHandler.ListGroups))
+	http.HandleFunc("POST /admin/groups", can(datastruct.PermissionGroupsWrite, groupHandler.CreateGroup))
+	http.HandleFunc("GET /admin/groups/{id}", can(datastruct.PermissionGroupsRead, groupHandler.GetGroup))
+	http.HandleFunc("PATCH /admin/groups/{id}", can(datastruct.PermissionGroupsWrite, groupHandler.UpdateGroup))
+	http.HandleFunc("DELETE /admin/groups/{id}", can(datastruct.PermissionGroupsWrite, groupHandler.DeleteGroup))
+	http.HandleFunc("GET /admin/groups/{id}/members", can(datastruct.PermissionGroupsRead, groupHandler.ListGroupMembers))
+	http.HandleFunc("PUT /admin/groups/{id}/members/{userId}",
+		can(datastruct.PermissionGroupsWrite, groupHandler.AddGroupMember))
+	http.HandleFunc("DELETE /admin/groups/{id}/members/{userId}",
+		can(datastruct.PermissionGroupsWrite, groupHandler.RemoveGroupMember))
+
+	http.HandleFunc("GET /admin/invitations", can(datastruct.PermissionUsersRead, invitationHandler.ListInvitations))
+	http.HandleFunc("POST /admin/invitations", can(datastruct.PermissionUsersWrite, invitationHandler.CreateInvitation))
+	http.HandleFunc("POST /admin/invitations/{id}/resend",
+		can(datastruct.PermissionUsersWrite, invitationHandler.ResendInvitation))
+	http.HandleFunc("DELETE /admin/invitations/{id}", can(datastruct.PermissionUsersWrite, invitationHandler.RevokeInvitation))
+
+	http.HandleFunc("GET /admin/scim/tokens", can(datastruct.PermissionScimManage, scimHandler.ListTokens))
+	http.HandleFunc("POST /admin/scim/tokens", can(datastruct.PermissionScimManage, scimHandler.CreateToken))
+	http.HandleFunc("DELETE /admin/scim/tokens/{id}", can(datastruct.PermissionScimManage, scimHandler.DeleteToken))
+
+	http.HandleFunc("POST /authz/check", authenticated(authzHandler.Check))
+
+	// SCIM 2.0 endpoints authenticate with SCIM tokens instead of user JWTs
+	http.HandleFunc("GET /scim/v2/ServiceProviderConfig", scimHandler.Authenticate(scimHandler.ServiceProviderConfig))
+	http.HandleFunc("GET /scim/v2/Users", scimHandler.Authenticate(scimHandler.ListUsers))
+	http.HandleFunc("POST /scim/v2/Users", scimHandler.Authenticate(scimHandler.CreateUser))
+	http.HandleFunc("GET /scim/v2/Users/{id}", scimHandler.Authenticate(scimHandler.GetUser))
+	http.HandleFunc("PUT /scim/v2/Users/{id}", scimHandler.Authenticate(scimHandler.ReplaceUser))
+	http.HandleFunc("PATCH /scim/v2/Users/{id}", scimHandler.Authenticate(scimHandler.PatchUser))
+	http.HandleFunc("DELETE /scim/v2/Users/{id}", scimHandler.Authenticate(scimHandler.DeleteUser))
+	http.HandleFunc("GET /scim/v2/Groups", scimHandler.Authenticate(scimHandler.ListGroups))
+	http.HandleFunc("POST /scim/v2/Groups", scimHandler.Authenticate(scimHandler.CreateGroup))
+	http.HandleFunc("GET /scim/v2/Groups/{id}", scimHandler.Authenticate(scimHandler.GetGroup))
+	http.HandleFunc("PUT /scim/v2/Groups/{id}", scimHandler.Authenticate(scimHandler.ReplaceGroup))
+	http.HandleFunc("PATCH /scim/v2/Groups/{id}", scimHandler.Authenticate(scimHandler.PatchGroup))
+	http.HandleFunc("DELETE /scim/v2/Groups/{id}", scimHandler.Authenticate(scimHandler.DeleteGroup))
+
+	if _, err := service.PasswordPolicy(); err != nil {
+		log.Fatalf("Failed to load the password policy: %v", err)
+	}
+	if _, err := service.DeletionGracePeriod(); err != nil {
+		log.Fatalf("Failed to read ACCOUNT_DELETION_GRACE_PERIOD: %v", err)
+	}
+	if _, _, err := service.LoginRiskSettings(); err != nil {
+		log.Fatalf("Failed to read the login risk settings: %v", err)
+	}
+	if _, err := service.GeoDatabase(); err != nil {
+		log.Fatalf("Failed to load the GeoIP database: %v", err)
+	}
+	go purgeDeletedAccounts(accountService, accountPurgeInterval)
+	go processDataExports(dataExportService, dataExportInterval)
+	if path := os.Getenv("AUDIT_CHECKPOINT_FILE"); path != "" {
+		interval, err := loadDuration("AUDIT_CHECKPOINT_INTERVAL", 24*time.Hour)
+		if err != nil {
+			log.Fatalf("Failed to read AUDIT_CHECKPOINT_INTERVAL: %v", err)
+		}
+		go exportAuditCheckpoints(auditService, path, interval)
+	}
+	if rateLimitRepository, ok := rateLimitStore.(*repository.RateLimitRepository); ok {
+		go purgeRateLimitBuckets(rateLimitRepository, rateLimitPurgeInterval)
+	}
+
+	// Start the HTTP server, until SIGINT or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	server := &http.Server{Addr: ":8080", Handler: app.WithClientInfo(http.DefaultServeMux, trustForwardedFor)}
+	go func() {
+		log.Println("Starting server on :8080")
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+	<-ctx.Done()
+	// A second signal stops the process right away
+	stop()
+
+	shutdown(server, auditSink)
+}
+
+// shutdownTimeout is how long requests in flight are given to finish on shutdown.
+const shutdownTimeout = 30 * time.Second
+
+// shutdown stops accepting requests, waits for those in flight, then delivers the audit events still
+// buffered for streaming.
+func shutdown(server *http.Server, auditSink auditsink.Sink) {
+	log.Println("Shutting down")
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("Failed to finish the requests in flight: %v", err)
+	}
+
+	if auditSink == nil {
+		return
+	}
+	if err := auditSink.Close(); err != nil {
+		log.Printf("Failed to close the audit streams: %v", err)
+	}
+}
+
+// accountPurgeInterval is how often accounts past their deletion grace period are purged.
+const accountPurgeInterval = time.Hour
 
-	// Start the HTTP server
-	log.Println("Starting server on :8080")
-	if err := http.ListenAndServe(":8080", nil); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+// purgeDeletedAccounts permanently removes the accounts whose deletion grace period is over, now and then
+// every interval.
+func purgeDeletedAccounts(accountService service.AccountServiceInterface, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := accountService.PurgeDeletedAccounts(context.Background())
+		if err != nil {
+			log.Printf("Failed to purge deleted accounts: %v", err)
+		} else if purged > 0 {
+			log.Printf("Purged %d deleted accounts", purged)
+		}
+		<-ticker.C
+	}
+}
+
+// dataExportInterval is how often pending data exports are built.
+const dataExportInterval = 30 * time.Second
+
+// processDataExports builds the requested data exports, now and then every interval.
+func processDataExports(dataExportService service.DataExportServiceInterface, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		built, err := dataExportService.ProcessPendingExports(context.Background())
+		if err != nil {
+			log.Printf("Failed to process data exports: %v", err)
+		} else if built > 0 {
+			log.Printf("Built %d data exports", built)
+		}
+		<-ticker.C
+	}
+}
+
+// exportAuditCheckpoints appends a signed checkpoint of the audit chain to the file at path every interval,
+// when new events were recorded since the last one.
+func exportAuditCheckpoints(auditService service.AuditServiceInterface, path string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastID uint64
+	for {
+		<-ticker.C
+		checkpoint, err := auditService.CreateCheckpoint(context.Background(), time.Now())
+		if errors.Is(err, service.ErrAuditChainEmpty) {
+			continue
+		}
+		if err != nil {
+			log.Printf("Failed to create an audit checkpoint: %v", err)
+			continue
+		}
+		if checkpoint.LastID == lastID {
+			continue
+		}
+		if err := auditchain.AppendCheckpoint(path, *checkpoint); err != nil {
+			log.Printf("Failed to export an audit checkpoint: %v", err)
+			continue
+		}
+		lastID = checkpoint.LastID
+	}
+}
+
+// rateLimitPurgeInterval is how often idle rate limit buckets are removed from Postgres. Buckets idle that
+// long have refilled for every configured limit up to one token per interval.
+const rateLimitPurgeInterval = 24 * time.Hour
+
+// purgeRateLimitBuckets removes the rate limit buckets idle for longer than interval, now and then every
+// interval.
+func purgeRateLimitBuckets(rateLimitRepository *repository.RateLimitRepository, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := rateLimitRepository.DeleteIdleBuckets(context.Background(), time.Now().Add(-interval)); err != nil {
+			log.Printf("Failed to purge rate limit buckets: %v", err)
+		}
+		<-ticker.C
+	}
+}
+
+// loadRateLimitStore returns the store named by RATE_LIMIT_STORE: "memory" (the default) for a single
+// instance, or "postgres" to share the limits between instances.
+func loadRateLimitStore(name string) (ratelimit.Store, error) {
+	switch name {
+	case "", "memory":
+		return ratelimit.NewMemoryStore(), nil
+	case "postgres":
+		return repository.NewRateLimitRepository(), nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit store %q", name)
+	}
+}
+
+// loadRateLimit reads a limit such as "10/1m" from the environment, falling back to fallback.
+func loadRateLimit(envKey string, fallback string) (ratelimit.Limit, error) {
+	if value := os.Getenv(envKey); value != "" {
+		return ratelimit.ParseLimit(value)
+	}
+	return ratelimit.ParseLimit(fallback)
+}
+
+// loadAuditSink returns the destinations audit events are streamed to, each buffered on its own so a slow
+// one does not hold up the others: the syslog collector of AUDIT_SYSLOG_URL and the AUDIT_LOG_FILE file.
+// It is nil when neither is set.
+func loadAuditSink() (auditsink.Sink, error) {
+	options := auditsink.DefaultAsyncOptions()
+	var err error
+	if options.BufferSize, err = loadInt("AUDIT_SINK_BUFFER_SIZE", options.BufferSize); err != nil {
+		return nil, err
+	}
+	if options.BlockTimeout, err = loadTimeout("AUDIT_SINK_BLOCK_TIMEOUT", options.BlockTimeout); err != nil {
+		return nil, err
+	}
+
+	var sinks auditsink.Multi
+	if rawURL := os.Getenv("AUDIT_SYSLOG_URL"); rawURL != "" {
+		formatter, err := auditsink.ParseFormat(envOr("AUDIT_SYSLOG_FORMAT", "cef"))
+		if err != nil {
+			return nil, err
+		}
+		syslog, err := auditsink.NewSyslog(rawURL, formatter, "auth-management")
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, auditsink.NewAsync(syslog, options))
+	}
+	if path := os.Getenv("AUDIT_LOG_FILE"); path != "" {
+		formatter, err := auditsink.ParseFormat(envOr("AUDIT_LOG_FILE_FORMAT", "json"))
+		if err != nil {
+			return nil, err
+		}
+		maxSizeMB, err := loadInt("AUDIT_LOG_FILE_MAX_SIZE_MB", 100)
+		if err != nil {
+			return nil, err
+		}
+		maxBackups, err := loadInt("AUDIT_LOG_FILE_MAX_BACKUPS", 5)
+		if err != nil {
+			return nil, err
+		}
+		file := auditsink.NewFile(path, formatter, int64(maxSizeMB)<<20, maxBackups)
+		sinks = append(sinks, auditsink.NewAsync(file, options))
+	}
+
+	if len(sinks) == 0 {
+		return nil, nil
+	}
+	return sinks, nil
+}
+
+// envOr reads envKey from the environment, falling back to fallback.
+func envOr(envKey string, fallback string) string {
+	if value := os.Getenv(envKey); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// loadInt reads a number from the environment, falling back to fallback.
+func loadInt(envKey string, fallback int) (int, error) {
+	value := os.Getenv(envKey)
+	if value == "" {
+		return fallback, nil
+	}
+	return strconv.Atoi(value)
+}
+
+// loadDuration reads a duration such as "24h" from the environment, falling back to fallback.
+func loadDuration(envKey string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(envKey)
+	if value == "" {
+		return fallback, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err == nil && duration <= 0 {
+		err = fmt.Errorf("%s must be positive", envKey)
+	}
+	return duration, err
+}
+
+// loadTimeout reads a duration from the environment like loadDuration, also accepting negative values, which
+// turn the wait off.
+func loadTimeout(envKey string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(envKey)
+	if value == "" {
+		return fallback, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err == nil && duration == 0 {
+		err = fmt.Errorf("%s must not be zero", envKey)
+	}
+	return duration, err
+}
+
+// loadPolicyEngine builds the policy engine from the JSON policy file. Without a file every check is denied.
+func loadPolicyEngine(path string) (*policy.Engine, error) {
+	if path == "" {
+		log.Println("POLICY_FILE is not set, every authorization check will be denied")
+		return policy.NewEngine(nil)
+	}
+
+	policies, err := policy.LoadFile(path)
+	if err != nil {
+		return nil, err
 	}
+	return policy.NewEngine(policies)
 }
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS role_changes (
+  id SERIAL PRIMARY KEY,
+  actor_id INTEGER NOT NULL,
+  user_id INTEGER NOT NULL,
+  old_role user_role NOT NULL,
+  new_role user_role NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS role_changes_user_id_idx ON role_changes (user_id);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS role_changes;
+-- +goose StatementEnd
@@ -0,0 +1,63 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS roles (
+  id SERIAL PRIMARY KEY,
+  name VARCHAR(64) NOT NULL UNIQUE,
+  description VARCHAR(255) NOT NULL DEFAULT '',
+  built_in BOOLEAN NOT NULL DEFAULT FALSE,
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS permissions (
+  id SERIAL PRIMARY KEY,
+  name VARCHAR(128) NOT NULL UNIQUE,
+  description VARCHAR(255) NOT NULL DEFAULT '',
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+  role_id INTEGER NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+  permission_id INTEGER NOT NULL REFERENCES permissions(id) ON DELETE CASCADE,
+  PRIMARY KEY (role_id, permission_id)
+);
+
+INSERT INTO roles (name, description, built_in) VALUES
+  ('superadmin', 'Full access to every resource', TRUE),
+  ('admin', 'Manages users', TRUE),
+  ('general-user', 'Default role for registered users', TRUE);
+
+INSERT INTO permissions (name, description) VALUES
+  ('users:read', 'List and view users'),
+  ('users:write', 'Update, disable and enable users'),
+  ('users:delete', 'Delete users'),
+  ('users:assign-role', 'Change the role of users'),
+  ('roles:read', 'List and view role definitions'),
+  ('roles:write', 'Create, update and delete role definitions');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT roles.id, permissions.id FROM roles CROSS JOIN permissions
+WHERE roles.name = 'superadmin'
+   OR (roles.name = 'admin' AND permissions.name IN ('users:read', 'users:write', 'users:delete', 'users:assign-role', 'roles:read'));
+
+ALTER TABLE users ALTER COLUMN role DROP DEFAULT;
+ALTER TABLE users ALTER COLUMN role TYPE VARCHAR(64) USING role::text;
+ALTER TABLE users ALTER COLUMN role SET DEFAULT 'general-user';
+ALTER TABLE users ADD CONSTRAINT users_role_fkey FOREIGN KEY (role) REFERENCES roles(name) ON UPDATE CASCADE;
+ALTER TABLE role_changes ALTER COLUMN old_role TYPE VARCHAR(64) USING old_role::text;
+ALTER TABLE role_changes ALTER COLUMN new_role TYPE VARCHAR(64) USING new_role::text;
+DROP TYPE IF EXISTS user_role;
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+CREATE TYPE user_role as ENUM('superadmin', 'admin', 'general-user');
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_fkey;
+ALTER TABLE users ALTER COLUMN role DROP DEFAULT;
+ALTER TABLE users ALTER COLUMN role TYPE user_role USING role::user_role;
+ALTER TABLE users ALTER COLUMN role SET DEFAULT 'general-user';
+ALTER TABLE role_changes ALTER COLUMN old_role TYPE user_role USING old_role::user_role;
+ALTER TABLE role_changes ALTER COLUMN new_role TYPE user_role USING new_role::user_role;
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS permissions;
+DROP TABLE IF EXISTS roles;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS authz_decisions (
+  id BIGSERIAL PRIMARY KEY,
+  caller_id INTEGER NOT NULL,
+  action VARCHAR(128) NOT NULL,
+  resource_type VARCHAR(128) NOT NULL DEFAULT '',
+  subject TEXT NOT NULL DEFAULT '{}',
+  resource TEXT NOT NULL DEFAULT '{}',
+  context TEXT NOT NULL DEFAULT '{}',
+  allowed BOOLEAN NOT NULL,
+  policy_id VARCHAR(128) NOT NULL DEFAULT '',
+  reason VARCHAR(255) NOT NULL DEFAULT '',
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS authz_decisions_created_at_idx ON authz_decisions (created_at);
+
+INSERT INTO permissions (name, description) VALUES
+  ('authz:check', 'Ask for authorization decisions on behalf of other subjects');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT roles.id, permissions.id FROM roles CROSS JOIN permissions
+WHERE roles.name = 'superadmin' AND permissions.name = 'authz:check';
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions WHERE name = 'authz:check';
+DROP TABLE IF EXISTS authz_decisions;
+-- +goose StatementEnd
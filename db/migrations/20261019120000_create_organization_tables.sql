@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS organizations (
+  id SERIAL PRIMARY KEY,
+  name VARCHAR(255) NOT NULL,
+  slug VARCHAR(64) NOT NULL UNIQUE,
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS organization_members (
+  organization_id INTEGER NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  role VARCHAR(64) NOT NULL REFERENCES roles(name) ON UPDATE CASCADE,
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (organization_id, user_id)
+);
+CREATE INDEX IF NOT EXISTS organization_members_user_id_idx ON organization_members (user_id);
+
+INSERT INTO organizations (name, slug) VALUES ('Default', 'default');
+
+ALTER TABLE users ADD COLUMN organization_id INTEGER REFERENCES organizations(id);
+UPDATE users SET organization_id = (SELECT id FROM organizations WHERE slug = 'default');
+ALTER TABLE users ALTER COLUMN organization_id SET NOT NULL;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_username_key;
+ALTER TABLE users ADD CONSTRAINT users_organization_id_username_key UNIQUE (organization_id, username);
+
+INSERT INTO organization_members (organization_id, user_id, role)
+SELECT organization_id, id, role FROM users;
+
+ALTER TABLE role_changes ADD COLUMN organization_id INTEGER;
+
+INSERT INTO permissions (name, description) VALUES
+  ('organizations:read', 'List organizations'),
+  ('organizations:write', 'Create organizations');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT roles.id, permissions.id FROM roles CROSS JOIN permissions
+WHERE roles.name = 'superadmin' AND permissions.name IN ('organizations:read', 'organizations:write');
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions WHERE name IN ('organizations:read', 'organizations:write');
+ALTER TABLE role_changes DROP COLUMN IF EXISTS organization_id;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_organization_id_username_key;
+ALTER TABLE users ADD CONSTRAINT users_username_key UNIQUE (username);
+ALTER TABLE users DROP COLUMN IF EXISTS organization_id;
+DROP TABLE IF EXISTS organization_members;
+DROP TABLE IF EXISTS organizations;
+-- +goose StatementEnd
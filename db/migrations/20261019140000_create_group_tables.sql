@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS groups (
+  id SERIAL PRIMARY KEY,
+  organization_id INTEGER NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+  name VARCHAR(64) NOT NULL,
+  description VARCHAR(255) NOT NULL DEFAULT '',
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (organization_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS group_roles (
+  group_id INTEGER NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+  role_id INTEGER NOT NULL REFERENCES roles(id),
+  PRIMARY KEY (group_id, role_id)
+);
+
+CREATE TABLE IF NOT EXISTS group_permissions (
+  group_id INTEGER NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+  permission_id INTEGER NOT NULL REFERENCES permissions(id) ON DELETE CASCADE,
+  PRIMARY KEY (group_id, permission_id)
+);
+
+CREATE TABLE IF NOT EXISTS group_members (
+  group_id INTEGER NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (group_id, user_id)
+);
+CREATE INDEX IF NOT EXISTS group_members_user_id_idx ON group_members (user_id);
+
+INSERT INTO permissions (name, description) VALUES
+  ('groups:read', 'List groups and their members'),
+  ('groups:write', 'Manage groups and their members');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT roles.id, permissions.id FROM roles CROSS JOIN permissions
+WHERE roles.name IN ('superadmin', 'admin') AND permissions.name IN ('groups:read', 'groups:write');
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions WHERE name IN ('groups:read', 'groups:write');
+DROP TABLE IF EXISTS group_members;
+DROP TABLE IF EXISTS group_permissions;
+DROP TABLE IF EXISTS group_roles;
+DROP TABLE IF EXISTS groups;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS scim_tokens (
+  id SERIAL PRIMARY KEY,
+  organization_id INTEGER NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+  description VARCHAR(255) NOT NULL,
+  token_hash CHAR(64) NOT NULL UNIQUE,
+  created_by INTEGER NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE users ADD COLUMN external_id VARCHAR(255) NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS users_organization_id_external_id_idx ON users (organization_id, external_id);
+ALTER TABLE groups ADD COLUMN external_id VARCHAR(255) NOT NULL DEFAULT '';
+
+INSERT INTO permissions (name, description) VALUES ('scim:manage', 'Manage the SCIM tokens of the organization');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT roles.id, permissions.id FROM roles CROSS JOIN permissions
+WHERE roles.name IN ('superadmin', 'admin') AND permissions.name = 'scim:manage';
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions WHERE name = 'scim:manage';
+ALTER TABLE groups DROP COLUMN IF EXISTS external_id;
+DROP INDEX IF EXISTS users_organization_id_external_id_idx;
+ALTER TABLE users DROP COLUMN IF EXISTS external_id;
+DROP TABLE IF EXISTS scim_tokens;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Events recorded before are left unchained, the chain starts with the next event.
+ALTER TABLE audit_events ADD COLUMN prev_hash VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE audit_events ADD COLUMN hash VARCHAR(64) NOT NULL DEFAULT '';
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE audit_events DROP COLUMN IF EXISTS hash;
+ALTER TABLE audit_events DROP COLUMN IF EXISTS prev_hash;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS login_devices (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  fingerprint CHAR(64) NOT NULL,
+  name VARCHAR(128) NOT NULL,
+  ip VARCHAR(64) NOT NULL,
+  country VARCHAR(2) NOT NULL DEFAULT '',
+  region VARCHAR(255) NOT NULL DEFAULT '',
+  city VARCHAR(255) NOT NULL DEFAULT '',
+  latitude DOUBLE PRECISION NOT NULL DEFAULT 0,
+  longitude DOUBLE PRECISION NOT NULL DEFAULT 0,
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+  last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (user_id, fingerprint)
+);
+CREATE TABLE IF NOT EXISTS login_challenges (
+  id SERIAL PRIMARY KEY,
+  token_hash CHAR(64) NOT NULL UNIQUE,
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  code_hash CHAR(64) NOT NULL,
+  fingerprint CHAR(64) NOT NULL,
+  attempts INTEGER NOT NULL DEFAULT 0,
+  expired_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS login_challenges;
+DROP TABLE IF EXISTS login_devices;
+-- +goose StatementEnd
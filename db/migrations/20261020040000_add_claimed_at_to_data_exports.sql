@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE data_exports ADD COLUMN claimed_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE data_exports SET expired_at = CURRENT_TIMESTAMP WHERE status = 'failed' AND expired_at IS NULL;
+UPDATE data_exports SET status = 'failed', expired_at = CURRENT_TIMESTAMP
+WHERE status IN ('pending', 'processing')
+  AND id NOT IN (
+    SELECT MAX(id) FROM data_exports WHERE status IN ('pending', 'processing') GROUP BY user_id
+  );
+CREATE UNIQUE INDEX IF NOT EXISTS data_exports_open_user_id_idx ON data_exports (user_id)
+WHERE status IN ('pending', 'processing');
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS data_exports_open_user_id_idx;
+ALTER TABLE data_exports DROP COLUMN IF EXISTS claimed_at;
+-- +goose StatementEnd
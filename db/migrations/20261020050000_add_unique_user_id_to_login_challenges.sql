@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+DELETE FROM login_challenges
+WHERE id NOT IN (SELECT MAX(id) FROM login_challenges GROUP BY user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS login_challenges_user_id_idx ON login_challenges (user_id);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS login_challenges_user_id_idx;
+-- +goose StatementEnd
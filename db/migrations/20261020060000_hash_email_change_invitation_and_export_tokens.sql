@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+UPDATE email_changes SET token = encode(sha256(token::bytea), 'hex'),
+    cancel_token = encode(sha256(cancel_token::bytea), 'hex');
+UPDATE invitations SET token = encode(sha256(token::bytea), 'hex');
+UPDATE data_exports SET token = encode(sha256(token::bytea), 'hex');
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+-- Hashed tokens cannot be turned back into tokens, so the links already sent stop working.
+DELETE FROM email_changes WHERE confirmed_at IS NULL AND cancelled_at IS NULL;
+UPDATE invitations SET revoked_at = NOW() WHERE accepted_at IS NULL AND revoked_at IS NULL;
+DELETE FROM data_exports WHERE status = 'ready';
+-- +goose StatementEnd
@@ -0,0 +1,204 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/fyfirman/auth-management-go/internal/dto"
+	"github.com/fyfirman/auth-management-go/internal/service"
+	"github.com/fyfirman/auth-management-go/pkg"
+	"github.com/go-playground/validator/v10"
+)
+
+// AccountHandler serves the /me endpoints of the authenticated user.
+type AccountHandler struct {
+	accountService service.AccountServiceInterface
+	validator      *validator.Validate
+}
+
+func NewAccountHandler(accountService service.AccountServiceInterface) *AccountHandler {
+	return &AccountHandler{accountService: accountService, validator: validator.New()}
+}
+
+func (h *AccountHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
+	claims, ok := ClaimsFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	resp, err := h.accountService.GetProfile(r.Context(), claims)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	pkg.WriteJSON(w, http.StatusOK, resp)
+}
+
+func (h *AccountHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	claims, ok := ClaimsFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.UpdateProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		pkg.WriteJSON(w, http.StatusBadRequest, pkg.PrepareValidationErrors(err))
+		return
+	}
+
+	resp, err := h.accountService.UpdateProfile(r.Context(), claims, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	pkg.WriteJSON(w, http.StatusOK, resp)
+}
+
+func (h *AccountHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	claims, ok := ClaimsFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		pkg.WriteJSON(w, http.StatusBadRequest, pkg.PrepareValidationErrors(err))
+		return
+	}
+
+	if err := h.accountService.ChangePassword(r.Context(), claims, req); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AccountHandler) RequestEmailChange(w http.ResponseWriter, r *http.Request) {
+	claims, ok := ClaimsFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.ChangeEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		pkg.WriteJSON(w, http.StatusBadRequest, pkg.PrepareValidationErrors(err))
+		return
+	}
+
+	if err := h.accountService.RequestEmailChange(r.Context(), claims, req); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (h *AccountHandler) ConfirmEmailChange(w http.ResponseWriter, r *http.Request) {
+	var req dto.EmailChangeTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		pkg.WriteJSON(w, http.StatusBadRequest, pkg.PrepareValidationErrors(err))
+		return
+	}
+
+	resp, err := h.accountService.ConfirmEmailChange(r.Context(), req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	pkg.WriteJSON(w, http.StatusOK, resp)
+}
+
+func (h *AccountHandler) CancelEmailChange(w http.ResponseWriter, r *http.Request) {
+	var req dto.EmailChangeTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		pkg.WriteJSON(w, http.StatusBadRequest, pkg.PrepareValidationErrors(err))
+		return
+	}
+
+	if err := h.accountService.CancelEmailChange(r.Context(), req); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AccountHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	claims, ok := ClaimsFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.DeleteAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		pkg.WriteJSON(w, http.StatusBadRequest, pkg.PrepareValidationErrors(err))
+		return
+	}
+
+	if err := h.accountService.DeleteAccount(r.Context(), claims, req); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AccountHandler) RestoreAccount(w http.ResponseWriter, r *http.Request) {
+	var req dto.RestoreAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		pkg.WriteJSON(w, http.StatusBadRequest, pkg.PrepareValidationErrors(err))
+		return
+	}
+
+	resp, err := h.accountService.RestoreAccount(r.Context(), req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	pkg.WriteJSON(w, http.StatusOK, resp)
+}
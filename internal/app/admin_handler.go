@@ -0,0 +1,352 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/fyfirman/auth-management-go/internal/dto"
+	"github.com/fyfirman/auth-management-go/internal/service"
+	"github.com/fyfirman/auth-management-go/pkg"
+	"github.com/go-playground/validator/v10"
+)
+
+const defaultPageSize = 20
+
+type AdminHandler struct {
+	adminUserService service.AdminUserServiceInterface
+	validator        *validator.Validate
+}
+
+func NewAdminHandler(adminUserService service.AdminUserServiceInterface) *AdminHandler {
+	return &AdminHandler{adminUserService: adminUserService, validator: validator.New()}
+}
+
+func (h *AdminHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	req, err := parseListUsersRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		pkg.WriteJSON(w, http.StatusBadRequest, pkg.PrepareValidationErrors(err))
+		return
+	}
+
+	resp, err := h.adminUserService.ListUsers(r.Context(), req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	pkg.WriteJSON(w, http.StatusOK, resp)
+}
+
+func (h *AdminHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.adminUserService.GetUser(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	pkg.WriteJSON(w, http.StatusOK, resp)
+}
+
+func (h *AdminHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	claims, ok := ClaimsFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := pathID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req dto.UpdateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		pkg.WriteJSON(w, http.StatusBadRequest, pkg.PrepareValidationErrors(err))
+		return
+	}
+
+	resp, err := h.adminUserService.UpdateUser(r.Context(), claims, id, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	pkg.WriteJSON(w, http.StatusOK, resp)
+}
+
+func (h *AdminHandler) DisableUser(w http.ResponseWriter, r *http.Request) {
+	h.setUserDisabled(w, r, true)
+}
+
+func (h *AdminHandler) EnableUser(w http.ResponseWriter, r *http.Request) {
+	h.setUserDisabled(w, r, false)
+}
+
+func (h *AdminHandler) setUserDisabled(w http.ResponseWriter, r *http.Request, disabled bool) {
+	claims, ok := ClaimsFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := pathID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.adminUserService.SetUserDisabled(r.Context(), claims, id, disabled)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	pkg.WriteJSON(w, http.StatusOK, resp)
+}
+
+func (h *AdminHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	claims, ok := ClaimsFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := pathID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.adminUserService.DeleteUser(r.Context(), claims, id); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AdminHandler) RestoreUser(w http.ResponseWriter, r *http.Request) {
+	claims, ok := ClaimsFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := pathID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.adminUserService.RestoreUser(r.Context(), claims, id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	pkg.WriteJSON(w, http.StatusOK, resp)
+}
+
+func (h *AdminHandler) UnlockUser(w http.ResponseWriter, r *http.Request) {
+	claims, ok := ClaimsFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := pathID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.adminUserService.UnlockUser(r.Context(), claims, id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	pkg.WriteJSON(w, http.StatusOK, resp)
+}
+
+func (h *AdminHandler) ImportUsers(w http.ResponseWriter, r *http.Request) {
+	var req dto.ImportUsersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		pkg.WriteJSON(w, http.StatusBadRequest, pkg.PrepareValidationErrors(err))
+		return
+	}
+
+	resp, err := h.adminUserService.ImportUsers(r.Context(), req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	pkg.WriteJSON(w, http.StatusOK, resp)
+}
+
+func (h *AdminHandler) ChangeUserRole(w http.ResponseWriter, r *http.Request) {
+	claims, ok := ClaimsFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := pathID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req dto.ChangeRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		pkg.WriteJSON(w, http.StatusBadRequest, pkg.PrepareValidationErrors(err))
+		return
+	}
+
+	resp, err := h.adminUserService.ChangeUserRole(r.Context(), claims, id, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	pkg.WriteJSON(w, http.StatusOK, resp)
+}
+
+func parseListUsersRequest(r *http.Request) (dto.ListUsersRequest, error) {
+	query := r.URL.Query()
+	req := dto.ListUsersRequest{
+		Page:     1,
+		PageSize: defaultPageSize,
+		Role:     query.Get("role"),
+		Email:    query.Get("email"),
+		Sort:     query.Get("sort"),
+		Order:    query.Get("order"),
+	}
+
+	var err error
+	if value := query.Get("page"); value != "" {
+		if req.Page, err = strconv.Atoi(value); err != nil {
+			return req, errors.New("page must be a number")
+		}
+	}
+	if value := query.Get("page_size"); value != "" {
+		if req.PageSize, err = strconv.Atoi(value); err != nil {
+			return req, errors.New("page_size must be a number")
+		}
+	}
+	if req.CreatedAfter, err = parseTimeQuery(query.Get("created_after")); err != nil {
+		return req, errors.New("created_after must be an RFC 3339 timestamp")
+	}
+	if req.CreatedBefore, err = parseTimeQuery(query.Get("created_before")); err != nil {
+		return req, errors.New("created_before must be an RFC 3339 timestamp")
+	}
+
+	return req, nil
+}
+
+func parseTimeQuery(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+func pathID(r *http.Request) (uint, error) {
+	return pathUint(r, "id")
+}
+
+func pathUint(r *http.Request, name string) (uint, error) {
+	value, err := strconv.ParseUint(r.PathValue(name), 10, 64)
+	if err != nil {
+		return 0, errors.New("invalid " + name)
+	}
+	return uint(value), nil
+}
+
+// writeServiceError maps the service sentinel errors to their HTTP status codes. Other errors answer a bare
+// 500 and are logged.
+func writeServiceError(w http.ResponseWriter, err error) {
+	var fieldErrors pkg.FieldErrors
+	switch {
+	case errors.As(err, &fieldErrors):
+		pkg.WriteJSON(w, http.StatusBadRequest, pkg.PrepareValidationErrors(fieldErrors))
+	case errors.Is(err, service.ErrUserNotFound),
+		errors.Is(err, service.ErrRoleNotFound),
+		errors.Is(err, service.ErrOrganizationNotFound),
+		errors.Is(err, service.ErrInvitationNotFound),
+		errors.Is(err, service.ErrGroupNotFound),
+		errors.Is(err, service.ErrSCIMTokenNotFound),
+		errors.Is(err, service.ErrDataExportNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrUserConflict),
+		errors.Is(err, service.ErrRoleConflict),
+		errors.Is(err, service.ErrRoleInUse),
+		errors.Is(err, service.ErrPermissionConflict),
+		errors.Is(err, service.ErrOrganizationConflict),
+		errors.Is(err, service.ErrAlreadyMember),
+		errors.Is(err, service.ErrGroupConflict),
+		errors.Is(err, service.ErrProfileModified),
+		errors.Is(err, service.ErrDataExportInProgress),
+		errors.Is(err, service.ErrInvitationClosed):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, service.ErrRoleForbidden),
+		errors.Is(err, service.ErrRoleBuiltIn),
+		errors.Is(err, service.ErrNoOrganization),
+		errors.Is(err, service.ErrNotOrganizationMember),
+		errors.Is(err, service.ErrNotHomeOrganization),
+		errors.Is(err, service.ErrAccountNotRestorable):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, service.ErrPermissionNotFound),
+		errors.Is(err, service.ErrInvitationInvalid),
+		errors.Is(err, service.ErrAccountDetailsRequired),
+		errors.Is(err, service.ErrInvalidCurrentPassword),
+		errors.Is(err, service.ErrPasswordReused),
+		errors.Is(err, service.ErrEmailUnchanged),
+		errors.Is(err, service.ErrEmailChangeInvalid),
+		errors.Is(err, service.ErrInvalidCursor):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrInvalidCredentials),
+		errors.Is(err, service.ErrLoginChallengeInvalid):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	default:
+		// Unexpected errors may come from the database, only the log gets their details
+		log.Printf("Request failed: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,139 @@
+package app_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fyfirman/auth-management-go/internal/app"
+	"github.com/fyfirman/auth-management-go/internal/dto"
+	"github.com/fyfirman/auth-management-go/internal/service"
+	"github.com/fyfirman/auth-management-go/internal/service/mocks"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAdminHandler_ListUsers(t *testing.T) {
+	t.Run("applies defaults and filters", func(t *testing.T) {
+		mockAdminUserService := new(mocks.AdminUserServiceInterface)
+		handler := app.NewAdminHandler(mockAdminUserService)
+
+		mockAdminUserService.On("ListUsers", mock.Anything, mock.MatchedBy(func(req dto.ListUsersRequest) bool {
+			return req.Page == 2 && req.PageSize == 20 && req.Role == "admin" && req.CreatedAfter != nil
+		})).Return(&dto.ListUsersResponse{Data: []dto.UserResponse{}, Page: 2, PageSize: 20}, nil)
+
+		req, _ := http.NewRequest("GET", "/admin/users?page=2&role=admin&created_after=2024-01-01T00:00:00Z", nil)
+		recorder := httptest.NewRecorder()
+
+		handler.ListUsers(recorder, req)
+
+		if recorder.Code != http.StatusOK {
+			t.Errorf("expected status code %d, got %d", http.StatusOK, recorder.Code)
+		}
+		mockAdminUserService.AssertExpectations(t)
+	})
+
+	t.Run("invalid sort order", func(t *testing.T) {
+		mockAdminUserService := new(mocks.AdminUserServiceInterface)
+		handler := app.NewAdminHandler(mockAdminUserService)
+
+		req, _ := http.NewRequest("GET", "/admin/users?order=sideways", nil)
+		recorder := httptest.NewRecorder()
+
+		handler.ListUsers(recorder, req)
+
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("expected status code %d, got %d", http.StatusBadRequest, recorder.Code)
+		}
+		mockAdminUserService.AssertNotCalled(t, "ListUsers")
+	})
+}
+
+func TestAdminHandler_UpdateUser(t *testing.T) {
+	mux := http.NewServeMux()
+	mockAdminUserService := new(mocks.AdminUserServiceInterface)
+	handler := app.NewAdminHandler(mockAdminUserService)
+	mux.HandleFunc("PATCH /admin/users/{id}", app.Authenticate(handler.UpdateUser))
+	t.Setenv("JWT_SECRET", "secret_jwt")
+
+	mockAdminUserService.On("UpdateUser", mock.Anything, mock.AnythingOfType("*service.Claims"), uint(5),
+		mock.AnythingOfType("dto.UpdateUserRequest")).Return(nil, service.ErrUserConflict)
+
+	req, _ := http.NewRequest("PATCH", "/admin/users/5", bytes.NewBufferString(`{"email":"taken@example.com"}`))
+	req.Header.Set("Authorization", "Bearer "+signTestToken(t, "admin"))
+	recorder := httptest.NewRecorder()
+
+	mux.ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusConflict {
+		t.Errorf("expected status code %d, got %d", http.StatusConflict, recorder.Code)
+	}
+}
+
+func TestAdminHandler_DeleteUser(t *testing.T) {
+	mux := http.NewServeMux()
+	mockAdminUserService := new(mocks.AdminUserServiceInterface)
+	handler := app.NewAdminHandler(mockAdminUserService)
+	mux.HandleFunc("DELETE /admin/users/{id}", app.Authenticate(handler.DeleteUser))
+	t.Setenv("JWT_SECRET", "secret_jwt")
+
+	mockAdminUserService.On("DeleteUser", mock.Anything, mock.Anything, uint(5)).Return(nil)
+	mockAdminUserService.On("DeleteUser", mock.Anything, mock.Anything, uint(6)).Return(service.ErrUserNotFound)
+
+	cases := []struct {
+		path           string
+		expectedStatus int
+	}{
+		{"/admin/users/5", http.StatusNoContent},
+		{"/admin/users/6", http.StatusNotFound},
+		{"/admin/users/abc", http.StatusBadRequest},
+	}
+
+	for _, c := range cases {
+		req, _ := http.NewRequest("DELETE", c.path, nil)
+		req.Header.Set("Authorization", "Bearer "+signTestToken(t, "admin"))
+		recorder := httptest.NewRecorder()
+
+		mux.ServeHTTP(recorder, req)
+
+		if recorder.Code != c.expectedStatus {
+			t.Errorf("%s: expected status code %d, got %d", c.path, c.expectedStatus, recorder.Code)
+		}
+	}
+}
+
+func TestAuditHandler_ListEvents(t *testing.T) {
+	t.Run("parses the filters", func(t *testing.T) {
+		mockAuditService := new(mocks.AuditServiceInterface)
+		handler := app.NewAuditHandler(mockAuditService)
+
+		mockAuditService.On("ListEvents", mock.Anything, mock.MatchedBy(func(req dto.ListAuditEventsRequest) bool {
+			return req.Type == "login" && req.ActorID == 9 && req.Since != nil && req.Limit == 50
+		})).Return(&dto.ListAuditEventsResponse{Data: []dto.AuditEventResponse{}}, nil)
+
+		req, _ := http.NewRequest("GET", "/admin/audit-events?type=login&actor_id=9&since=2026-10-01T00:00:00Z", nil)
+		recorder := httptest.NewRecorder()
+
+		handler.ListEvents(recorder, req)
+
+		if recorder.Code != http.StatusOK {
+			t.Errorf("expected status code %d, got %d", http.StatusOK, recorder.Code)
+		}
+		mockAuditService.AssertExpectations(t)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		mockAuditService := new(mocks.AuditServiceInterface)
+		handler := app.NewAuditHandler(mockAuditService)
+		mockAuditService.On("ListEvents", mock.Anything, mock.Anything).Return(nil, service.ErrInvalidCursor)
+
+		req, _ := http.NewRequest("GET", "/admin/audit-events?cursor=bogus", nil)
+		recorder := httptest.NewRecorder()
+
+		handler.ListEvents(recorder, req)
+
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("expected status code %d, got %d", http.StatusBadRequest, recorder.Code)
+		}
+	})
+}
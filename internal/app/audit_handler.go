@@ -0,0 +1,84 @@
+package app
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/fyfirman/auth-management-go/internal/dto"
+	"github.com/fyfirman/auth-management-go/internal/service"
+	"github.com/fyfirman/auth-management-go/pkg"
+	"github.com/go-playground/validator/v10"
+)
+
+const defaultAuditPageSize = 50
+
+type AuditHandler struct {
+	auditService service.AuditServiceInterface
+	validator    *validator.Validate
+}
+
+func NewAuditHandler(auditService service.AuditServiceInterface) *AuditHandler {
+	return &AuditHandler{auditService: auditService, validator: validator.New()}
+}
+
+func (h *AuditHandler) ListEvents(w http.ResponseWriter, r *http.Request) {
+	req, err := parseListAuditEventsRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		pkg.WriteJSON(w, http.StatusBadRequest, pkg.PrepareValidationErrors(err))
+		return
+	}
+
+	resp, err := h.auditService.ListEvents(r.Context(), req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	pkg.WriteJSON(w, http.StatusOK, resp)
+}
+
+func parseListAuditEventsRequest(r *http.Request) (dto.ListAuditEventsRequest, error) {
+	query := r.URL.Query()
+	req := dto.ListAuditEventsRequest{
+		Type:    query.Get("type"),
+		Outcome: query.Get("outcome"),
+		IP:      query.Get("ip"),
+		Cursor:  query.Get("cursor"),
+		Limit:   defaultAuditPageSize,
+	}
+
+	var err error
+	if value := query.Get("limit"); value != "" {
+		if req.Limit, err = strconv.Atoi(value); err != nil {
+			return req, errors.New("limit must be a number")
+		}
+	}
+	if req.ActorID, err = parseUintQuery(query.Get("actor_id")); err != nil {
+		return req, errors.New("actor_id must be a number")
+	}
+	if req.TargetID, err = parseUintQuery(query.Get("target_id")); err != nil {
+		return req, errors.New("target_id must be a number")
+	}
+	if req.Since, err = parseTimeQuery(query.Get("since")); err != nil {
+		return req, errors.New("since must be an RFC 3339 timestamp")
+	}
+	if req.Until, err = parseTimeQuery(query.Get("until")); err != nil {
+		return req, errors.New("until must be an RFC 3339 timestamp")
+	}
+
+	return req, nil
+}
+
+func parseUintQuery(value string) (uint, error) {
+	if value == "" {
+		return 0, nil
+	}
+	parsed, err := strconv.ParseUint(value, 10, 64)
+	return uint(parsed), err
+}
@@ -0,0 +1,52 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/fyfirman/auth-management-go/internal/dto"
+	"github.com/fyfirman/auth-management-go/internal/service"
+	"github.com/fyfirman/auth-management-go/pkg"
+	"github.com/go-playground/validator/v10"
+)
+
+type AuthzHandler struct {
+	authzService service.AuthzServiceInterface
+	validator    *validator.Validate
+}
+
+func NewAuthzHandler(authzService service.AuthzServiceInterface) *AuthzHandler {
+	return &AuthzHandler{authzService: authzService, validator: validator.New()}
+}
+
+func (h *AuthzHandler) Check(w http.ResponseWriter, r *http.Request) {
+	claims, ok := ClaimsFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.AuthzCheckRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		pkg.WriteJSON(w, http.StatusBadRequest, pkg.PrepareValidationErrors(err))
+		return
+	}
+
+	resp, err := h.authzService.Check(r.Context(), claims, req)
+	if err != nil {
+		if errors.Is(err, service.ErrAuthzForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	pkg.WriteJSON(w, http.StatusOK, resp)
+}
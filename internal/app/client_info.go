@@ -0,0 +1,62 @@
+package app
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/fyfirman/auth-management-go/internal/clientinfo"
+)
+
+// WithClientInfo stores the IP and user agent of the client in the request context, for the services that
+// record them. trustForwardedFor takes the IP from X-Forwarded-For, which only a reverse proxy in front of
+// the service may set.
+func WithClientInfo(next http.Handler, trustForwardedFor bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := clientinfo.WithInfo(r.Context(), clientinfo.Info{
+			IP:        clientIP(r, trustForwardedFor),
+			UserAgent: r.UserAgent(),
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func clientIP(r *http.Request, trustForwardedFor bool) string {
+	if trustForwardedFor {
+		if client := forwardedClient(r.Header.Values("X-Forwarded-For")); client != "" {
+			return client
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// forwardedClient returns the rightmost hop of X-Forwarded-For that is not a proxy in front of the service,
+// taken to be those with loopback, private or link-local addresses. Clients can prepend any hop they like,
+// so the leftmost one is only used when every hop is such a proxy.
+func forwardedClient(headers []string) string {
+	var hops []string
+	for _, header := range headers {
+		for _, hop := range strings.Split(header, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				hops = append(hops, hop)
+			}
+		}
+	}
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(hops[i])
+		if err != nil || !(addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast()) {
+			return hops[i]
+		}
+	}
+	if len(hops) == 0 {
+		return ""
+	}
+	return hops[0]
+}
@@ -0,0 +1,44 @@
+package app_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fyfirman/auth-management-go/internal/app"
+	"github.com/fyfirman/auth-management-go/internal/clientinfo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithClientInfo(t *testing.T) {
+	cases := []struct {
+		name      string
+		forwarded []string
+		trust     bool
+		ip        string
+	}{
+		{"remote address without a proxy", []string{"198.51.100.1"}, false, "192.0.2.1"},
+		{"rightmost hop added by the proxy", []string{"198.51.100.1, 203.0.113.7"}, true, "203.0.113.7"},
+		{"skips private proxies", []string{"198.51.100.1, 203.0.113.7, 10.0.0.1", "127.0.0.1"}, true, "203.0.113.7"},
+		{"leftmost hop behind private proxies only", []string{"10.1.2.3, 10.0.0.1"}, true, "10.1.2.3"},
+		{"no header", nil, true, "192.0.2.1"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var ip string
+			handler := app.WithClientInfo(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ip = clientinfo.FromContext(r.Context()).IP
+			}), c.trust)
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = "192.0.2.1:4321"
+			for _, forwarded := range c.forwarded {
+				req.Header.Add("X-Forwarded-For", forwarded)
+			}
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, c.ip, ip)
+		})
+	}
+}
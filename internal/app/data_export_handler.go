@@ -0,0 +1,48 @@
+package app
+
+import (
+	"net/http"
+
+	"github.com/fyfirman/auth-management-go/internal/service"
+	"github.com/fyfirman/auth-management-go/pkg"
+)
+
+type DataExportHandler struct {
+	dataExportService service.DataExportServiceInterface
+}
+
+func NewDataExportHandler(dataExportService service.DataExportServiceInterface) *DataExportHandler {
+	return &DataExportHandler{dataExportService: dataExportService}
+}
+
+func (h *DataExportHandler) RequestExport(w http.ResponseWriter, r *http.Request) {
+	claims, ok := ClaimsFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	resp, err := h.dataExportService.RequestExport(r.Context(), claims)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	pkg.WriteJSON(w, http.StatusAccepted, resp)
+}
+
+// DownloadExport serves the archive as an attachment. The token in the path is the only credential, so the
+// link works from the email without signing in.
+func (h *DataExportHandler) DownloadExport(w http.ResponseWriter, r *http.Request) {
+	archive, err := h.dataExportService.DownloadExport(r.Context(), r.PathValue("token"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", `attachment; filename="data-export.json"`)
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	w.Write(archive)
+}
@@ -0,0 +1,222 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/fyfirman/auth-management-go/internal/dto"
+	"github.com/fyfirman/auth-management-go/internal/service"
+	"github.com/fyfirman/auth-management-go/pkg"
+	"github.com/go-playground/validator/v10"
+)
+
+type GroupHandler struct {
+	groupService service.GroupServiceInterface
+	validator    *validator.Validate
+}
+
+func NewGroupHandler(groupService service.GroupServiceInterface) *GroupHandler {
+	return &GroupHandler{groupService: groupService, validator: validator.New()}
+}
+
+func (h *GroupHandler) ListGroups(w http.ResponseWriter, r *http.Request) {
+	claims, ok := ClaimsFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	resp, err := h.groupService.ListGroups(r.Context(), claims)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	pkg.WriteJSON(w, http.StatusOK, resp)
+}
+
+func (h *GroupHandler) GetGroup(w http.ResponseWriter, r *http.Request) {
+	claims, ok := ClaimsFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := pathID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.groupService.GetGroup(r.Context(), claims, id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	pkg.WriteJSON(w, http.StatusOK, resp)
+}
+
+func (h *GroupHandler) CreateGroup(w http.ResponseWriter, r *http.Request) {
+	claims, ok := ClaimsFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.CreateGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		pkg.WriteJSON(w, http.StatusBadRequest, pkg.PrepareValidationErrors(err))
+		return
+	}
+
+	resp, err := h.groupService.CreateGroup(r.Context(), claims, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	pkg.WriteJSON(w, http.StatusCreated, resp)
+}
+
+func (h *GroupHandler) UpdateGroup(w http.ResponseWriter, r *http.Request) {
+	claims, ok := ClaimsFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := pathID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req dto.UpdateGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		pkg.WriteJSON(w, http.StatusBadRequest, pkg.PrepareValidationErrors(err))
+		return
+	}
+
+	resp, err := h.groupService.UpdateGroup(r.Context(), claims, id, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	pkg.WriteJSON(w, http.StatusOK, resp)
+}
+
+func (h *GroupHandler) DeleteGroup(w http.ResponseWriter, r *http.Request) {
+	claims, ok := ClaimsFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := pathID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.groupService.DeleteGroup(r.Context(), claims, id); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *GroupHandler) ListGroupMembers(w http.ResponseWriter, r *http.Request) {
+	claims, ok := ClaimsFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := pathID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.groupService.ListGroupMembers(r.Context(), claims, id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	pkg.WriteJSON(w, http.StatusOK, resp)
+}
+
+func (h *GroupHandler) AddGroupMember(w http.ResponseWriter, r *http.Request) {
+	h.updateMembership(w, r, h.groupService.AddGroupMember)
+}
+
+func (h *GroupHandler) RemoveGroupMember(w http.ResponseWriter, r *http.Request) {
+	h.updateMembership(w, r, h.groupService.RemoveGroupMember)
+}
+
+func (h *GroupHandler) EffectivePermissions(w http.ResponseWriter, r *http.Request) {
+	claims, ok := ClaimsFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := pathID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.groupService.EffectivePermissions(r.Context(), claims, id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	pkg.WriteJSON(w, http.StatusOK, resp)
+}
+
+func (h *GroupHandler) updateMembership(
+	w http.ResponseWriter,
+	r *http.Request,
+	update func(ctx context.Context, actor *service.Claims, id uint, userID uint) error,
+) {
+	claims, ok := ClaimsFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := pathID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	userID, err := pathUint(r, "userId")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := update(r.Context(), claims, id, userID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -1,8 +1,12 @@
 package app
 
 import (
+	"crypto/rand"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/fyfirman/auth-management-go/internal/dto"
 	"github.com/fyfirman/auth-management-go/internal/service"
@@ -10,6 +14,13 @@ import (
 	"github.com/go-playground/validator/v10"
 )
 
+const (
+	// deviceCookieName is the cookie telling apart the devices users log in from.
+	deviceCookieName = "device_id"
+
+	deviceCookieMaxAge = 2 * 365 * 24 * time.Hour
+)
+
 type UserHandler struct {
 	userService service.UserServiceInterface
 	validator   *validator.Validate
@@ -32,11 +43,6 @@ func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	if err := req.Validate(); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-
 	if err := h.validator.Struct(req); err != nil {
 		errors := pkg.PrepareValidationErrors(err)
 		w.Header().Set("Content-Type", "application/json")
@@ -51,7 +57,7 @@ func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
 
 	resp, err := h.userService.RegisterUser(r.Context(), &req)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeServiceError(w, err)
 		return
 	}
 
@@ -70,9 +76,11 @@ func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	req.DeviceID = deviceID(w, r)
+
 	resp, err := h.userService.Login(r.Context(), req)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusUnauthorized)
+		writeServiceError(w, err)
 		return
 	}
 
@@ -84,6 +92,53 @@ func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// VerifyLogin completes a login held back for its risk with the code emailed to the user.
+func (h *UserHandler) VerifyLogin(w http.ResponseWriter, r *http.Request) {
+	var req dto.VerifyLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		pkg.WriteJSON(w, http.StatusBadRequest, pkg.PrepareValidationErrors(err))
+		return
+	}
+	req.DeviceID = deviceID(w, r)
+
+	resp, err := h.userService.VerifyLoginChallenge(r.Context(), req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	pkg.WriteJSON(w, http.StatusOK, resp)
+}
+
+// deviceID returns the ID of the device cookie of the request, and gives the browser a new one when it has
+// none so its next logins are recognized.
+func deviceID(w http.ResponseWriter, r *http.Request) string {
+	if cookie, err := r.Cookie(deviceCookieName); err == nil && cookie.Value != "" {
+		return cookie.Value
+	}
+
+	secret := make([]byte, 16)
+	if _, err := rand.Read(secret); err != nil {
+		return ""
+	}
+	id := base64.RawURLEncoding.EncodeToString(secret)
+	http.SetCookie(w, &http.Cookie{
+		Name:     deviceCookieName,
+		Value:    id,
+		Path:     "/",
+		MaxAge:   int(deviceCookieMaxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https",
+		SameSite: http.SameSiteLaxMode,
+	})
+	return id
+}
+
 func (h *UserHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
 	var req dto.ForgotPasswordRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -112,8 +167,18 @@ func (h *UserHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := h.validator.Struct(req); err != nil {
+		pkg.WriteJSON(w, http.StatusBadRequest, pkg.PrepareValidationErrors(err))
+		return
+	}
+
 	resp, err := h.userService.ResetPassword(r.Context(), req)
 	if err != nil {
+		var fieldErrors pkg.FieldErrors
+		if errors.As(err, &fieldErrors) {
+			pkg.WriteJSON(w, http.StatusBadRequest, pkg.PrepareValidationErrors(fieldErrors))
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -125,3 +190,30 @@ func (h *UserHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+func (h *UserHandler) SwitchOrganization(w http.ResponseWriter, r *http.Request) {
+	claims, ok := ClaimsFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.SwitchOrganizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		pkg.WriteJSON(w, http.StatusBadRequest, pkg.PrepareValidationErrors(err))
+		return
+	}
+
+	resp, err := h.userService.SwitchOrganization(r.Context(), claims, req.OrganizationID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	pkg.WriteJSON(w, http.StatusOK, resp)
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/fyfirman/auth-management-go/internal/app"
 	"github.com/fyfirman/auth-management-go/internal/dto"
+	"github.com/fyfirman/auth-management-go/internal/service"
 	"github.com/fyfirman/auth-management-go/internal/service/mocks"
 	"github.com/fyfirman/auth-management-go/pkg"
 	"github.com/stretchr/testify/mock"
@@ -79,7 +80,7 @@ func TestUserHandler_Register_InvalidRequest(t *testing.T) {
 
 	mockUserService.Mock.On("RegisterUser", ctx, mock.AnythingOfType("*dto.RegisterRequest")).Return(response, nil)
 	// Create a new HTTP request
-	reqBody := []byte(`{"username": "testuser", "email": "testuser@gmail.com", "password": "testpassword", "role": "hack!!!"}`)
+	reqBody := []byte(`{"username": "testuser", "email": "not-an-email", "password": "testpassword"}`)
 	req, err := http.NewRequest("POST", "/register", bytes.NewBuffer(reqBody))
 	if err != nil {
 		t.Fatal(err)
@@ -100,6 +101,54 @@ func TestUserHandler_Register_InvalidRequest(t *testing.T) {
 	// TODO: Check the response body
 }
 
+func TestUserHandler_Register_Conflict(t *testing.T) {
+	mockUserService := new(mocks.UserServiceInterface)
+	handler := app.NewUserHandler(mockUserService)
+	mockUserService.Mock.On("RegisterUser", mock.Anything, mock.AnythingOfType("*dto.RegisterRequest")).
+		Return(nil, service.ErrUserConflict)
+
+	reqBody := []byte(`{"username": "testuser", "email": "testuser@gmail.com", "password": "testpassword"}`)
+	req := httptest.NewRequest("POST", "/register", bytes.NewBuffer(reqBody))
+	recorder := httptest.NewRecorder()
+
+	handler.Register(recorder, req)
+
+	if recorder.Code != http.StatusConflict {
+		t.Errorf("expected status code %d, got %d", http.StatusConflict, recorder.Code)
+	}
+}
+
+func TestUserHandler_Login_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+		body string
+	}{
+		{name: "invalid credentials", err: service.ErrInvalidCredentials, code: http.StatusUnauthorized,
+			body: "invalid credentials\n"},
+		{name: "database error", err: errors.New("pq: connection refused"), code: http.StatusInternalServerError,
+			body: "Internal Server Error\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserService := new(mocks.UserServiceInterface)
+			handler := app.NewUserHandler(mockUserService)
+			mockUserService.Mock.On("Login", mock.Anything, mock.AnythingOfType("dto.LoginRequest")).
+				Return(nil, tt.err)
+
+			req := httptest.NewRequest("POST", "/login", bytes.NewBufferString(`{"email": "a@b.co", "password": "x"}`))
+			recorder := httptest.NewRecorder()
+
+			handler.Login(recorder, req)
+
+			if recorder.Code != tt.code || recorder.Body.String() != tt.body {
+				t.Errorf("expected %d %q, got %d %q", tt.code, tt.body, recorder.Code, recorder.Body.String())
+			}
+		})
+	}
+}
+
 func TestForgotPassword(t *testing.T) {
 	t.Run("invalid request body", func(t *testing.T) {
 		mockUserService := new(mocks.UserServiceInterface) // Reinitialize mock
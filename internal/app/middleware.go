@@ -0,0 +1,109 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/fyfirman/auth-management-go/internal/service"
+	"github.com/fyfirman/auth-management-go/internal/tenant"
+)
+
+type contextKey string
+
+const claimsContextKey contextKey = "claims"
+
+// ClaimsFromContext returns the claims stored by Authenticate.
+func ClaimsFromContext(ctx context.Context) (*service.Claims, bool) {
+	claims, ok := ctx.Value(claimsContextKey).(*service.Claims)
+	return claims, ok
+}
+
+// Authenticate rejects requests without a valid bearer token and stores its claims in the request context.
+// The context is also scoped to the token's organization so repositories only see that tenant. Tokens
+// restricted to changing an expired password are refused with 403 Forbidden.
+func Authenticate(next http.HandlerFunc) http.HandlerFunc {
+	return authenticate(next, false)
+}
+
+// AuthenticatePasswordChange is Authenticate also accepting the tokens restricted to changing an expired
+// password, for the password change endpoint.
+func AuthenticatePasswordChange(next http.HandlerFunc) http.HandlerFunc {
+	return authenticate(next, true)
+}
+
+func authenticate(next http.HandlerFunc, allowPasswordChange bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tokenString, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || tokenString == "" {
+			http.Error(w, "Missing bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		claims, err := service.ParseJWT(tokenString)
+		if err != nil {
+			http.Error(w, "Invalid token", http.StatusUnauthorized)
+			return
+		}
+
+		if claims.PasswordChangeRequired && !allowPasswordChange {
+			http.Error(w, "Password change required", http.StatusForbidden)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), claimsContextKey, claims)
+		if claims.OrgID != 0 {
+			ctx = tenant.WithOrganization(ctx, claims.OrgID)
+		}
+
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// RequirePermission only lets through authenticated requests whose token carries permission.
+// It must be wrapped by Authenticate.
+func RequirePermission(permission string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := ClaimsFromContext(r.Context())
+			if !ok {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			if !claims.HasPermission(permission) {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+
+			next(w, r)
+		}
+	}
+}
+
+// RequireSession rejects authenticated requests whose session has been revoked, for example by a password
+// change. It must be wrapped by Authenticate.
+func RequireSession(sessionService service.SessionServiceInterface) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := ClaimsFromContext(r.Context())
+			if !ok {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			err := sessionService.CheckSession(r.Context(), claims)
+			if errors.Is(err, service.ErrSessionRevoked) {
+				http.Error(w, "Session has been revoked", http.StatusUnauthorized)
+				return
+			}
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			next(w, r)
+		}
+	}
+}
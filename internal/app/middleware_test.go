@@ -0,0 +1,183 @@
+package app_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fyfirman/auth-management-go/internal/app"
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+	"github.com/fyfirman/auth-management-go/internal/service"
+	"github.com/fyfirman/auth-management-go/internal/service/mocks"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/mock"
+)
+
+func signTestToken(t *testing.T, role string) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"exp":       time.Now().Add(time.Hour).Unix(),
+		"user_id":   42,
+		"user_role": role,
+	})
+	tokenString, err := token.SignedString([]byte("secret_jwt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tokenString
+}
+
+func TestAuthenticate(t *testing.T) {
+	t.Setenv("JWT_SECRET", "secret_jwt")
+
+	var userID uint
+	handler := app.Authenticate(func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := app.ClaimsFromContext(r.Context())
+		userID = claims.UserID
+		w.WriteHeader(http.StatusOK)
+	})
+
+	cases := []struct {
+		name           string
+		authorization  string
+		expectedStatus int
+	}{
+		{"missing token", "", http.StatusUnauthorized},
+		{"malformed token", "Bearer not-a-jwt", http.StatusUnauthorized},
+		{"valid token", "Bearer " + signTestToken(t, "general-user"), http.StatusOK},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/admin/users", nil)
+			if c.authorization != "" {
+				req.Header.Set("Authorization", c.authorization)
+			}
+			recorder := httptest.NewRecorder()
+
+			handler(recorder, req)
+
+			if recorder.Code != c.expectedStatus {
+				t.Errorf("expected status code %d, got %d", c.expectedStatus, recorder.Code)
+			}
+		})
+	}
+
+	if userID != 42 {
+		t.Errorf("expected claims user id 42, got %d", userID)
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	t.Setenv("JWT_SECRET", "secret_jwt")
+
+	handler := app.Authenticate(app.RequirePermission(datastruct.PermissionUsersWrite)(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		},
+	))
+
+	sign := func(permissions []string) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"exp":         time.Now().Add(time.Hour).Unix(),
+			"user_id":     42,
+			"user_role":   "support",
+			"permissions": permissions,
+		})
+		tokenString, err := token.SignedString([]byte("secret_jwt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tokenString
+	}
+
+	cases := []struct {
+		name           string
+		permissions    []string
+		expectedStatus int
+	}{
+		{"without permission", []string{datastruct.PermissionUsersRead}, http.StatusForbidden},
+		{"with permission", []string{datastruct.PermissionUsersRead, datastruct.PermissionUsersWrite}, http.StatusOK},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req, _ := http.NewRequest("PATCH", "/admin/users/1", nil)
+			req.Header.Set("Authorization", "Bearer "+sign(c.permissions))
+			recorder := httptest.NewRecorder()
+
+			handler(recorder, req)
+
+			if recorder.Code != c.expectedStatus {
+				t.Errorf("expected status code %d, got %d", c.expectedStatus, recorder.Code)
+			}
+		})
+	}
+}
+
+func TestAuthenticatePasswordChange(t *testing.T) {
+	t.Setenv("JWT_SECRET", "secret_jwt")
+
+	ok := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"exp":        time.Now().Add(time.Hour).Unix(),
+		"user_id":    42,
+		"user_role":  "general-user",
+		"pwd_change": true,
+	})
+	restricted, err := token.SignedString([]byte("secret_jwt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name           string
+		handler        http.HandlerFunc
+		authorization  string
+		expectedStatus int
+	}{
+		{"restricted token elsewhere", app.Authenticate(ok), restricted, http.StatusForbidden},
+		{"restricted token changing the password", app.AuthenticatePasswordChange(ok), restricted, http.StatusOK},
+		{"regular token changing the password", app.AuthenticatePasswordChange(ok),
+			signTestToken(t, "general-user"), http.StatusOK},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req, _ := http.NewRequest("POST", "/me/password", nil)
+			req.Header.Set("Authorization", "Bearer "+c.authorization)
+			recorder := httptest.NewRecorder()
+
+			c.handler(recorder, req)
+
+			if recorder.Code != c.expectedStatus {
+				t.Errorf("expected status code %d, got %d", c.expectedStatus, recorder.Code)
+			}
+		})
+	}
+}
+
+func TestRequireSession(t *testing.T) {
+	t.Setenv("JWT_SECRET", "secret_jwt")
+
+	mockSessionService := new(mocks.SessionServiceInterface)
+	handler := app.Authenticate(app.RequireSession(mockSessionService)(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	mockSessionService.On("CheckSession", mock.Anything, mock.AnythingOfType("*service.Claims")).
+		Return(service.ErrSessionRevoked)
+
+	req, _ := http.NewRequest("GET", "/me", nil)
+	req.Header.Set("Authorization", "Bearer "+signTestToken(t, "general-user"))
+	recorder := httptest.NewRecorder()
+
+	handler(recorder, req)
+
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("expected status code %d, got %d", http.StatusUnauthorized, recorder.Code)
+	}
+}
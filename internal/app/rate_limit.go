@@ -0,0 +1,107 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fyfirman/auth-management-go/pkg/ratelimit"
+)
+
+// maxRateLimitedBody caps how much of a request body RateLimiter reads to find the account.
+const maxRateLimitedBody = 1 << 20
+
+// RateLimiter throttles unauthenticated endpoints with token buckets keyed by endpoint and client IP, and by
+// endpoint and account, with or without the client IP, for requests naming one.
+type RateLimiter struct {
+	store ratelimit.Store
+	// trustForwardedFor takes the client IP from X-Forwarded-For, which only a reverse proxy in front of the
+	// service may set.
+	trustForwardedFor bool
+}
+
+func NewRateLimiter(store ratelimit.Store, trustForwardedFor bool) *RateLimiter {
+	return &RateLimiter{store: store, trustForwardedFor: trustForwardedFor}
+}
+
+// Limit rejects requests to endpoint with 429 Too Many Requests and a Retry-After header once the client IP
+// or the account in the "email" field of the JSON body runs out of tokens. The account bucket is kept per
+// client IP, so nobody can use up the budget of someone else's account; guessing the password of one account
+// from many IPs is stopped by the login lockout instead. When the store fails the request is let through.
+func (l *RateLimiter) Limit(endpoint string, limit ratelimit.Limit) func(http.HandlerFunc) http.HandlerFunc {
+	return l.limit(endpoint, limit, true)
+}
+
+// LimitMail is Limit for endpoints emailing the account, such as forgot-password: the account bucket is
+// shared by every client IP, so spreading requests over many addresses cannot flood an inbox. Anyone can
+// use up the budget of an account this way, which only holds back its emails.
+func (l *RateLimiter) LimitMail(endpoint string, limit ratelimit.Limit) func(http.HandlerFunc) http.HandlerFunc {
+	return l.limit(endpoint, limit, false)
+}
+
+func (l *RateLimiter) limit(
+	endpoint string,
+	limit ratelimit.Limit,
+	accountPerIP bool,
+) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			ip := clientIP(r, l.trustForwardedFor)
+			keys := []string{endpoint + ":ip:" + ip}
+			if account := requestAccount(r); account != "" {
+				key := endpoint + ":account:" + account
+				if accountPerIP {
+					key += ":ip:" + ip
+				}
+				keys = append(keys, key)
+			}
+
+			var retryAfter time.Duration
+			for _, key := range keys {
+				result, err := l.store.Take(r.Context(), key, limit, time.Now())
+				if err != nil {
+					log.Printf("Failed to check the rate limit of %s: %v", key, err)
+					continue
+				}
+				if !result.Allowed {
+					retryAfter = max(retryAfter, result.RetryAfter)
+				}
+			}
+
+			if retryAfter > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+				http.Error(w, "Too many requests", http.StatusTooManyRequests)
+				return
+			}
+
+			next(w, r)
+		}
+	}
+}
+
+// requestAccount returns the normalized email of a JSON body and leaves the body readable for the handler.
+func requestAccount(r *http.Request) string {
+	if r.Body == nil {
+		return ""
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRateLimitedBody))
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return ""
+	}
+
+	var req struct {
+		Email string `json:"email"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(req.Email))
+}
@@ -0,0 +1,78 @@
+package app_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fyfirman/auth-management-go/internal/app"
+	"github.com/fyfirman/auth-management-go/pkg/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter_Limit(t *testing.T) {
+	var bodies []string
+	store := ratelimit.NewMemoryStore()
+	handler := app.NewRateLimiter(store, true).
+		Limit("login", ratelimit.Limit{Burst: 2, Per: time.Minute})(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		w.WriteHeader(http.StatusOK)
+	})
+
+	login := func(ip string, email string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/login", strings.NewReader(`{"email":"`+email+`","password":"x"}`))
+		req.Header.Set("X-Forwarded-For", ip+", 10.0.0.1")
+		rr := httptest.NewRecorder()
+		handler(rr, req)
+		return rr
+	}
+
+	assert.Equal(t, http.StatusOK, login("203.0.113.7", "jdoe@example.com").Code)
+	assert.Equal(t, `{"email":"jdoe@example.com","password":"x"}`, bodies[0])
+
+	// The IP is limited across accounts
+	assert.Equal(t, http.StatusOK, login("203.0.113.7", "other@example.com").Code)
+	rr := login("203.0.113.7", "third@example.com")
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "30", rr.Header().Get("Retry-After"))
+
+	// Another client cannot use up the budget of the account
+	assert.Equal(t, http.StatusOK, login("203.0.113.8", "jdoe@example.com").Code)
+	assert.Len(t, bodies, 3)
+
+	// The account is limited per client, whatever the case of the email
+	result, err := store.Take(context.TODO(), "login:account:jdoe@example.com:ip:203.0.113.8",
+		ratelimit.Limit{Burst: 2, Per: time.Minute}, time.Now())
+	assert.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, http.StatusTooManyRequests, login("203.0.113.8", "JDoe@example.com").Code)
+}
+
+func TestRateLimiter_LimitMail(t *testing.T) {
+	limit := ratelimit.Limit{Burst: 2, Per: time.Minute}
+	handler := app.NewRateLimiter(ratelimit.NewMemoryStore(), true).
+		LimitMail("forgot-password", limit)(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	forgotPassword := func(ip string, email string) int {
+		req := httptest.NewRequest("POST", "/forgot-password", strings.NewReader(`{"email":"`+email+`"}`))
+		req.Header.Set("X-Forwarded-For", ip+", 10.0.0.1")
+		rr := httptest.NewRecorder()
+		handler(rr, req)
+		return rr.Code
+	}
+
+	// Spreading requests over many IPs does not flood the inbox
+	assert.Equal(t, http.StatusOK, forgotPassword("203.0.113.7", "jdoe@example.com"))
+	assert.Equal(t, http.StatusOK, forgotPassword("203.0.113.8", "jdoe@example.com"))
+	assert.Equal(t, http.StatusTooManyRequests, forgotPassword("203.0.113.9", "JDoe@example.com"))
+
+	// Other accounts keep their budget
+	assert.Equal(t, http.StatusOK, forgotPassword("203.0.113.10", "other@example.com"))
+}
@@ -0,0 +1,352 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/fyfirman/auth-management-go/internal/dto"
+	"github.com/fyfirman/auth-management-go/internal/service"
+	"github.com/fyfirman/auth-management-go/internal/tenant"
+	"github.com/fyfirman/auth-management-go/pkg"
+	"github.com/fyfirman/auth-management-go/pkg/scim"
+	"github.com/go-playground/validator/v10"
+)
+
+// SCIMHandler serves /scim/v2 with SCIM bodies and errors, and the admin endpoints managing SCIM tokens.
+type SCIMHandler struct {
+	scimService service.SCIMServiceInterface
+	validator   *validator.Validate
+}
+
+func NewSCIMHandler(scimService service.SCIMServiceInterface) *SCIMHandler {
+	return &SCIMHandler{scimService: scimService, validator: validator.New()}
+}
+
+// Authenticate rejects SCIM requests without a valid SCIM token and scopes the context to its organization.
+func (h *SCIMHandler) Authenticate(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			writeSCIMError(w, service.ErrInvalidSCIMToken)
+			return
+		}
+
+		organizationID, err := h.scimService.Authenticate(r.Context(), token)
+		if err != nil {
+			writeSCIMError(w, err)
+			return
+		}
+
+		next(w, r.WithContext(tenant.WithOrganization(r.Context(), organizationID)))
+	}
+}
+
+func (h *SCIMHandler) ServiceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	writeSCIM(w, http.StatusOK, map[string]interface{}{
+		"schemas":        []string{scim.SchemaServiceProviderConfig},
+		"patch":          map[string]bool{"supported": true},
+		"bulk":           map[string]interface{}{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]interface{}{"supported": true, "maxResults": service.SCIMMaxResults},
+		"changePassword": map[string]bool{"supported": true},
+		"sort":           map[string]bool{"supported": false},
+		"etag":           map[string]bool{"supported": false},
+		"authenticationSchemes": []map[string]interface{}{{
+			"type":        "oauthbearertoken",
+			"name":        "OAuth Bearer Token",
+			"description": "Authentication with a SCIM token issued through /admin/scim/tokens",
+			"primary":     true,
+		}},
+	})
+}
+
+func (h *SCIMHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	query, err := parseSCIMListQuery(r)
+	if err != nil {
+		writeSCIMError(w, err)
+		return
+	}
+
+	resp, err := h.scimService.ListUsers(r.Context(), query)
+	if err != nil {
+		writeSCIMError(w, err)
+		return
+	}
+
+	writeSCIM(w, http.StatusOK, resp)
+}
+
+func (h *SCIMHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.scimService.GetUser(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeSCIMError(w, err)
+		return
+	}
+
+	writeSCIM(w, http.StatusOK, resp)
+}
+
+func (h *SCIMHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	var req dto.SCIMUser
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeSCIMError(w, errInvalidSCIMSyntax)
+		return
+	}
+
+	resp, err := h.scimService.CreateUser(r.Context(), req)
+	if err != nil {
+		writeSCIMError(w, err)
+		return
+	}
+
+	writeSCIM(w, http.StatusCreated, resp)
+}
+
+func (h *SCIMHandler) ReplaceUser(w http.ResponseWriter, r *http.Request) {
+	var req dto.SCIMUser
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeSCIMError(w, errInvalidSCIMSyntax)
+		return
+	}
+
+	resp, err := h.scimService.ReplaceUser(r.Context(), r.PathValue("id"), req)
+	if err != nil {
+		writeSCIMError(w, err)
+		return
+	}
+
+	writeSCIM(w, http.StatusOK, resp)
+}
+
+func (h *SCIMHandler) PatchUser(w http.ResponseWriter, r *http.Request) {
+	var req scim.PatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeSCIMError(w, errInvalidSCIMSyntax)
+		return
+	}
+
+	resp, err := h.scimService.PatchUser(r.Context(), r.PathValue("id"), req)
+	if err != nil {
+		writeSCIMError(w, err)
+		return
+	}
+
+	writeSCIM(w, http.StatusOK, resp)
+}
+
+func (h *SCIMHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	if err := h.scimService.DeleteUser(r.Context(), r.PathValue("id")); err != nil {
+		writeSCIMError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *SCIMHandler) ListGroups(w http.ResponseWriter, r *http.Request) {
+	query, err := parseSCIMListQuery(r)
+	if err != nil {
+		writeSCIMError(w, err)
+		return
+	}
+
+	resp, err := h.scimService.ListGroups(r.Context(), query)
+	if err != nil {
+		writeSCIMError(w, err)
+		return
+	}
+
+	writeSCIM(w, http.StatusOK, resp)
+}
+
+func (h *SCIMHandler) GetGroup(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.scimService.GetGroup(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeSCIMError(w, err)
+		return
+	}
+
+	writeSCIM(w, http.StatusOK, resp)
+}
+
+func (h *SCIMHandler) CreateGroup(w http.ResponseWriter, r *http.Request) {
+	var req dto.SCIMGroup
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeSCIMError(w, errInvalidSCIMSyntax)
+		return
+	}
+
+	resp, err := h.scimService.CreateGroup(r.Context(), req)
+	if err != nil {
+		writeSCIMError(w, err)
+		return
+	}
+
+	writeSCIM(w, http.StatusCreated, resp)
+}
+
+func (h *SCIMHandler) ReplaceGroup(w http.ResponseWriter, r *http.Request) {
+	var req dto.SCIMGroup
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeSCIMError(w, errInvalidSCIMSyntax)
+		return
+	}
+
+	resp, err := h.scimService.ReplaceGroup(r.Context(), r.PathValue("id"), req)
+	if err != nil {
+		writeSCIMError(w, err)
+		return
+	}
+
+	writeSCIM(w, http.StatusOK, resp)
+}
+
+func (h *SCIMHandler) PatchGroup(w http.ResponseWriter, r *http.Request) {
+	var req scim.PatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeSCIMError(w, errInvalidSCIMSyntax)
+		return
+	}
+
+	resp, err := h.scimService.PatchGroup(r.Context(), r.PathValue("id"), req)
+	if err != nil {
+		writeSCIMError(w, err)
+		return
+	}
+
+	writeSCIM(w, http.StatusOK, resp)
+}
+
+func (h *SCIMHandler) DeleteGroup(w http.ResponseWriter, r *http.Request) {
+	if err := h.scimService.DeleteGroup(r.Context(), r.PathValue("id")); err != nil {
+		writeSCIMError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *SCIMHandler) CreateToken(w http.ResponseWriter, r *http.Request) {
+	claims, ok := ClaimsFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.CreateSCIMTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		pkg.WriteJSON(w, http.StatusBadRequest, pkg.PrepareValidationErrors(err))
+		return
+	}
+
+	resp, err := h.scimService.CreateToken(r.Context(), claims, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	pkg.WriteJSON(w, http.StatusCreated, resp)
+}
+
+func (h *SCIMHandler) ListTokens(w http.ResponseWriter, r *http.Request) {
+	claims, ok := ClaimsFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	resp, err := h.scimService.ListTokens(r.Context(), claims)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	pkg.WriteJSON(w, http.StatusOK, resp)
+}
+
+func (h *SCIMHandler) DeleteToken(w http.ResponseWriter, r *http.Request) {
+	claims, ok := ClaimsFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := pathID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.scimService.DeleteToken(r.Context(), claims, id); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+var errInvalidSCIMSyntax = errors.New("request body is not valid JSON")
+
+// parseSCIMListQuery reads filter, startIndex and count. count defaults to service.SCIMMaxResults.
+func parseSCIMListQuery(r *http.Request) (dto.SCIMListQuery, error) {
+	values := r.URL.Query()
+	query := dto.SCIMListQuery{Filter: values.Get("filter"), StartIndex: 1, Count: service.SCIMMaxResults}
+
+	if raw := values.Get("startIndex"); raw != "" {
+		startIndex, err := strconv.Atoi(raw)
+		if err != nil {
+			return query, fmt.Errorf("%w: startIndex must be an integer", scim.ErrInvalidValue)
+		}
+		query.StartIndex = startIndex
+	}
+	if raw := values.Get("count"); raw != "" {
+		count, err := strconv.Atoi(raw)
+		if err != nil {
+			return query, fmt.Errorf("%w: count must be an integer", scim.ErrInvalidValue)
+		}
+		query.Count = count
+	}
+	return query, nil
+}
+
+func writeSCIM(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", scim.ContentType)
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// writeSCIMError maps service errors onto SCIM error responses (RFC 7644 section 3.12).
+func writeSCIMError(w http.ResponseWriter, err error) {
+	status, scimType := http.StatusInternalServerError, ""
+	switch {
+	case errors.Is(err, service.ErrInvalidSCIMToken):
+		status = http.StatusUnauthorized
+	case errors.Is(err, service.ErrUserNotFound), errors.Is(err, service.ErrGroupNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, service.ErrNotHomeOrganization):
+		status = http.StatusForbidden
+	case errors.Is(err, service.ErrUserConflict), errors.Is(err, service.ErrGroupConflict):
+		status, scimType = http.StatusConflict, "uniqueness"
+	case errors.Is(err, errInvalidSCIMSyntax):
+		status, scimType = http.StatusBadRequest, "invalidSyntax"
+	case scim.ErrorType(err) != "":
+		status, scimType = http.StatusBadRequest, scim.ErrorType(err)
+	}
+
+	writeSCIM(w, status, scim.Error{
+		Schemas:  []string{scim.SchemaError},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   err.Error(),
+	})
+}
@@ -0,0 +1,22 @@
+package clientinfo
+
+import "context"
+
+type contextKey struct{}
+
+// Info describes the client a request comes from.
+type Info struct {
+	IP        string
+	UserAgent string
+}
+
+// WithInfo attaches the client of the request being served to ctx.
+func WithInfo(ctx context.Context, info Info) context.Context {
+	return context.WithValue(ctx, contextKey{}, info)
+}
+
+// FromContext returns the client set by WithInfo, empty outside of requests.
+func FromContext(ctx context.Context) Info {
+	info, _ := ctx.Value(contextKey{}).(Info)
+	return info
+}
@@ -0,0 +1,63 @@
+package datastruct
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Audit event types.
+const (
+	AuditRegister               = "register"
+	AuditLogin                  = "login"
+	AuditLoginChallenged        = "login_challenged"
+	AuditPasswordResetRequested = "password_reset_requested"
+	AuditPasswordResetCompleted = "password_reset_completed"
+	AuditRoleChanged            = "role_changed"
+	AuditSessionsRevoked        = "sessions_revoked"
+	AuditScimTokenRevoked       = "scim_token_revoked"
+)
+
+// Audit event outcomes.
+const (
+	AuditSuccess = "success"
+	AuditFailure = "failure"
+)
+
+// AuditEvent records who did what to whom, from which client, and whether it succeeded. ActorId is the
+// user acting, unset for anonymous requests, and TargetId the user acted upon. Details holds the
+// specifics of each type, such as the reason of a failure. The table is append-only, and each event is
+// chained to the one before by Hash, see pkg/auditchain.
+type AuditEvent struct {
+	ID             uint `gorm:"primaryKey"`
+	OrganizationId *uint
+	Type           string `gorm:"not null"`
+	Outcome        string `gorm:"not null"`
+	ActorId        *uint
+	TargetId       *uint
+	IP             string            `gorm:"not null;default:''"`
+	UserAgent      string            `gorm:"not null;default:''"`
+	Details        map[string]string `gorm:"serializer:json"`
+	PrevHash       string            `gorm:"not null;default:''"`
+	Hash           string            `gorm:"not null;default:''"`
+	CreatedAt      time.Time
+}
+
+// ChainPayload is the canonical encoding of the event hashed into the audit chain. It covers every column
+// but the ID, assigned on insert, and the hashes themselves.
+func (e *AuditEvent) ChainPayload() []byte {
+	payload, _ := json.Marshal(struct {
+		OrganizationId *uint             `json:"organization_id"`
+		Type           string            `json:"type"`
+		Outcome        string            `json:"outcome"`
+		ActorId        *uint             `json:"actor_id"`
+		TargetId       *uint             `json:"target_id"`
+		IP             string            `json:"ip"`
+		UserAgent      string            `json:"user_agent"`
+		Details        map[string]string `json:"details"`
+		CreatedAt      string            `json:"created_at"`
+	}{
+		e.OrganizationId, e.Type, e.Outcome, e.ActorId, e.TargetId, e.IP, e.UserAgent, e.Details,
+		e.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+	return payload
+}
@@ -0,0 +1,20 @@
+package datastruct
+
+import (
+	"time"
+)
+
+// AuthzDecision records the outcome of a policy check. Subject, Resource and Context hold the JSON input.
+type AuthzDecision struct {
+	ID           uint   `gorm:"primaryKey"`
+	CallerId     uint   `gorm:"not null"`
+	Action       string `gorm:"not null"`
+	ResourceType string
+	Subject      string
+	Resource     string
+	Context      string
+	Allowed      bool `gorm:"not null"`
+	PolicyId     string
+	Reason       string
+	CreatedAt    time.Time
+}
@@ -0,0 +1,27 @@
+package datastruct
+
+import (
+	"time"
+)
+
+const (
+	DataExportPending    = "pending"
+	DataExportProcessing = "processing"
+	DataExportReady      = "ready"
+	DataExportFailed     = "failed"
+)
+
+// DataExport is a request for a copy of a user's personal data. A background job builds the JSON Archive
+// and emails a download link carrying a token, whose hash is Token, valid until ExpiredAt. ClaimedAt is
+// when a job started building it. Each user has at most one pending or processing export.
+type DataExport struct {
+	ID          uint   `gorm:"primaryKey"`
+	UserId      uint   `gorm:"not null"`
+	Status      string `gorm:"not null"`
+	Token       string `gorm:"unique;not null"`
+	Archive     []byte
+	ExpiredAt   *time.Time
+	ClaimedAt   *time.Time
+	CompletedAt *time.Time
+	CreatedAt   time.Time
+}
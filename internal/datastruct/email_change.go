@@ -0,0 +1,24 @@
+package datastruct
+
+import (
+	"time"
+)
+
+// EmailChange is a request to move an account to NewEmail. Token is sent to the new address to confirm
+// the change, and CancelToken to the current one so its owner can stop it. Both are stored hashed.
+type EmailChange struct {
+	ID          uint   `gorm:"primaryKey"`
+	UserId      uint   `gorm:"not null"`
+	NewEmail    string `gorm:"not null"`
+	Token       string `gorm:"unique;not null"`
+	CancelToken string `gorm:"unique;not null"`
+	ExpiredAt   time.Time
+	ConfirmedAt *time.Time
+	CancelledAt *time.Time
+	CreatedAt   time.Time
+}
+
+// Pending reports whether the change can still be confirmed or cancelled.
+func (c *EmailChange) Pending(now time.Time) bool {
+	return c.ConfirmedAt == nil && c.CancelledAt == nil && c.ExpiredAt.After(now)
+}
@@ -0,0 +1,42 @@
+package datastruct
+
+import (
+	"time"
+)
+
+// Group hands its roles and permissions to every member, on top of the role each member holds in the
+// organization.
+type Group struct {
+	ID             uint         `gorm:"primaryKey"`
+	OrganizationId uint         `gorm:"not null"`
+	ExternalId     string       `gorm:"not null;default:''"`
+	Name           string       `gorm:"not null"`
+	Description    string       `gorm:"not null"`
+	Roles          []Role       `gorm:"many2many:group_roles;"`
+	Permissions    []Permission `gorm:"many2many:group_permissions;"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (g *Group) RoleNames() []string {
+	names := make([]string, 0, len(g.Roles))
+	for _, role := range g.Roles {
+		names = append(names, role.Name)
+	}
+	return names
+}
+
+// PermissionNames returns the permissions set directly on the group, without those of its roles.
+func (g *Group) PermissionNames() []string {
+	names := make([]string, 0, len(g.Permissions))
+	for _, permission := range g.Permissions {
+		names = append(names, permission.Name)
+	}
+	return names
+}
+
+type GroupMember struct {
+	GroupId   uint `gorm:"primaryKey"`
+	UserId    uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+}
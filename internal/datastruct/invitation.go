@@ -0,0 +1,34 @@
+package datastruct
+
+import (
+	"time"
+)
+
+// Invitation lets an organization admin onboard someone by email with a given role. Token is the hash of
+// the token in the emailed link.
+type Invitation struct {
+	ID             uint   `gorm:"primaryKey"`
+	OrganizationId uint   `gorm:"not null"`
+	Email          string `gorm:"not null"`
+	Role           string `gorm:"not null"`
+	Token          string `gorm:"unique;not null"`
+	InvitedBy      uint   `gorm:"not null"`
+	ExpiredAt      time.Time
+	AcceptedAt     *time.Time
+	RevokedAt      *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (i *Invitation) Status(now time.Time) string {
+	switch {
+	case i.AcceptedAt != nil:
+		return "accepted"
+	case i.RevokedAt != nil:
+		return "revoked"
+	case i.ExpiredAt.Before(now):
+		return "expired"
+	default:
+		return "pending"
+	}
+}
@@ -0,0 +1,33 @@
+package datastruct
+
+import "time"
+
+// LoginDevice is a device a user logged in from, told apart by Fingerprint, with where it was last seen
+// from. Country is empty when the IP could not be located.
+type LoginDevice struct {
+	ID          uint   `gorm:"primaryKey"`
+	UserId      uint   `gorm:"not null"`
+	Fingerprint string `gorm:"not null"`
+	Name        string `gorm:"not null"`
+	IP          string `gorm:"not null"`
+	Country     string `gorm:"not null"`
+	Region      string `gorm:"not null"`
+	City        string `gorm:"not null"`
+	Latitude    float64
+	Longitude   float64
+	CreatedAt   time.Time
+	LastSeenAt  time.Time `gorm:"not null"`
+}
+
+// LoginChallenge holds back a risky login until the one-time code emailed to the user is entered from the
+// same device. Only hashes of the token and the code are stored.
+type LoginChallenge struct {
+	ID          uint   `gorm:"primaryKey"`
+	TokenHash   string `gorm:"not null;unique"`
+	UserId      uint   `gorm:"not null;unique"`
+	CodeHash    string `gorm:"not null"`
+	Fingerprint string `gorm:"not null"`
+	Attempts    int    `gorm:"not null;default:0"`
+	ExpiredAt   time.Time
+	CreatedAt   time.Time
+}
@@ -0,0 +1,25 @@
+package datastruct
+
+import (
+	"time"
+)
+
+// DefaultOrganizationSlug is the organization public registrations join.
+const DefaultOrganizationSlug = "default"
+
+type Organization struct {
+	ID        uint   `gorm:"primaryKey"`
+	Name      string `gorm:"not null"`
+	Slug      string `gorm:"unique;not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// OrganizationMember links a user to an organization with the role they hold there.
+type OrganizationMember struct {
+	OrganizationId uint          `gorm:"primaryKey"`
+	UserId         uint          `gorm:"primaryKey"`
+	Role           string        `gorm:"not null"`
+	Organization   *Organization `gorm:"foreignKey:OrganizationId"`
+	CreatedAt      time.Time
+}
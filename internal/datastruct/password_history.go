@@ -0,0 +1,11 @@
+package datastruct
+
+import "time"
+
+// PasswordHistory is a password a user set, kept so it cannot be reused for a while.
+type PasswordHistory struct {
+	ID           uint   `gorm:"primaryKey"`
+	UserId       uint   `gorm:"not null"`
+	PasswordHash string `gorm:"not null"`
+	CreatedAt    time.Time
+}
@@ -0,0 +1,10 @@
+package datastruct
+
+import "time"
+
+// RateLimitBucket is the token bucket of a rate limit key, shared by every instance of the service.
+type RateLimitBucket struct {
+	Key       string    `gorm:"primaryKey"`
+	Tokens    float64   `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime:false"`
+}
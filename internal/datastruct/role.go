@@ -0,0 +1,61 @@
+package datastruct
+
+import (
+	"time"
+)
+
+const (
+	PermissionUsersRead       = "users:read"
+	PermissionUsersWrite      = "users:write"
+	PermissionUsersDelete     = "users:delete"
+	PermissionUsersAssignRole = "users:assign-role"
+	PermissionRolesRead       = "roles:read"
+	PermissionRolesWrite      = "roles:write"
+	PermissionAuthzCheck      = "authz:check"
+	PermissionOrgsRead        = "organizations:read"
+	PermissionOrgsWrite       = "organizations:write"
+	PermissionGroupsRead      = "groups:read"
+	PermissionGroupsWrite     = "groups:write"
+	PermissionScimManage      = "scim:manage"
+	PermissionAuditRead       = "audit:read"
+)
+
+// PlatformPermissions act beyond a single organization. They are only granted to platform superadmins
+// (User.Role), never through the role held in an organization or its groups.
+var PlatformPermissions = []string{PermissionRolesWrite, PermissionOrgsWrite, PermissionAuthzCheck}
+
+// IsPlatformPermission reports whether name is one of PlatformPermissions.
+func IsPlatformPermission(name string) bool {
+	for _, permission := range PlatformPermissions {
+		if permission == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Role is a named set of permissions. The three UserRole values are seeded as built-in roles.
+type Role struct {
+	ID          uint         `gorm:"primaryKey"`
+	Name        string       `gorm:"unique;not null"`
+	Description string       `gorm:"not null"`
+	BuiltIn     bool         `gorm:"not null;default:false"`
+	Permissions []Permission `gorm:"many2many:role_permissions;"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (r *Role) PermissionNames() []string {
+	names := make([]string, 0, len(r.Permissions))
+	for _, permission := range r.Permissions {
+		names = append(names, permission.Name)
+	}
+	return names
+}
+
+type Permission struct {
+	ID          uint   `gorm:"primaryKey"`
+	Name        string `gorm:"unique;not null"`
+	Description string `gorm:"not null"`
+	CreatedAt   time.Time
+}
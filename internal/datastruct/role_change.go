@@ -0,0 +1,16 @@
+package datastruct
+
+import (
+	"time"
+)
+
+// RoleChange is the audit record written whenever a user's role in an organization is changed.
+type RoleChange struct {
+	ID             uint `gorm:"primaryKey"`
+	OrganizationId uint
+	ActorId        uint   `gorm:"not null"`
+	UserId         uint   `gorm:"not null"`
+	OldRole        string `gorm:"not null"`
+	NewRole        string `gorm:"not null"`
+	CreatedAt      time.Time
+}
@@ -0,0 +1,15 @@
+package datastruct
+
+import (
+	"time"
+)
+
+// ScimToken authenticates the SCIM client of an organization. Only the SHA-256 hash of the token is kept.
+type ScimToken struct {
+	ID             uint   `gorm:"primaryKey"`
+	OrganizationId uint   `gorm:"not null"`
+	Description    string `gorm:"not null"`
+	TokenHash      string `gorm:"unique;not null"`
+	CreatedBy      uint   `gorm:"not null"`
+	CreatedAt      time.Time
+}
@@ -0,0 +1,11 @@
+package datastruct
+
+import "time"
+
+// Session is a login. Tokens carry its ID as the "sid" claim and stop being accepted once it is revoked.
+type Session struct {
+	ID        string `gorm:"primaryKey"`
+	UserId    uint   `gorm:"not null"`
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
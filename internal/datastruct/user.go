@@ -3,6 +3,8 @@ package datastruct
 import (
 	"strings"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type UserRole int
@@ -30,12 +32,47 @@ func ParseUserRole(str string) (UserRole, bool) {
 	return c, ok
 }
 
+// AtLeast reports whether r ranks the same as or higher than other in the
+// SuperAdmin > Admin > GeneralUser hierarchy.
+func (r UserRole) AtLeast(other UserRole) bool {
+	return r <= other
+}
+
+// User is an account.
 type User struct {
-	ID           uint   `gorm:"primaryKey"`
-	Username     string `gorm:"unique;not null"`
-	Email        string `gorm:"unique;not null"`
-	Role         string `gorm:"not null"`
-	PasswordHash string `gorm:"not null"`
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	ID uint `gorm:"primaryKey"`
+	// OrganizationId is the organization the account was created in.
+	OrganizationId uint `gorm:"not null"`
+	// ExternalId is the identifier the SCIM client of the organization knows the user by.
+	ExternalId string `gorm:"not null;default:''"`
+	Username   string `gorm:"not null"`
+	// DisplayName, Locale and TimeZone are profile fields the user manages through /me.
+	DisplayName string `gorm:"not null;default:''"`
+	Locale      string `gorm:"not null;default:''"`
+	TimeZone    string `gorm:"not null;default:''"`
+	Email       string `gorm:"unique;not null"`
+	// Role is the platform wide role, where only superadmin grants access across organizations.
+	Role string `gorm:"not null"`
+	// OrganizationRole is only filled by queries scoped to an organization, with the role held in it.
+	OrganizationRole string `gorm:"->;-:migration"`
+	PasswordHash     string `gorm:"not null"`
+	// PasswordChangedAt is when the user last chose a password, which expires after the configured maximum
+	// age.
+	PasswordChangedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	Disabled          bool      `gorm:"not null;default:false"`
+	// FailedLoginAttempts counts the consecutive failed logins since the last success or lock.
+	FailedLoginAttempts int `gorm:"not null;default:0"`
+	// LockoutCount counts the consecutive locks.
+	LockoutCount int `gorm:"not null;default:0"`
+	// LockedUntil is set while the account is locked.
+	LockedUntil *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	// DeletedAt soft deletes the account, which is purged once the deletion grace period is over.
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+// Locked reports whether the account is locked at now.
+func (u *User) Locked(now time.Time) bool {
+	return u.LockedUntil != nil && u.LockedUntil.After(now)
 }
@@ -0,0 +1,64 @@
+package dto
+
+import "time"
+
+type ListUsersRequest struct {
+	Page          int        `validate:"min=1"`
+	PageSize      int        `validate:"min=1,max=100"`
+	Role          string     `validate:"max=64"`
+	Email         string     `validate:"max=255"`
+	CreatedAfter  *time.Time `validate:"omitempty"`
+	CreatedBefore *time.Time `validate:"omitempty"`
+	Sort          string     `validate:"omitempty,oneof=id username email role created_at updated_at"`
+	Order         string     `validate:"omitempty,oneof=asc desc"`
+}
+
+type ListUsersResponse struct {
+	Data     []UserResponse `json:"data"`
+	Page     int            `json:"page"`
+	PageSize int            `json:"page_size"`
+	Total    int64          `json:"total"`
+}
+
+type UpdateUserRequest struct {
+	Username *string `json:"username" validate:"omitempty,alphanum,min=3,max=25"`
+	Email    *string `json:"email"    validate:"omitempty,email"`
+}
+
+type ChangeRoleRequest struct {
+	Role string `json:"role" validate:"required,max=64"`
+}
+
+// ImportUsersRequest creates users with the password hashes of the system they are migrated from.
+type ImportUsersRequest struct {
+	Users []ImportUser `json:"users" validate:"required,min=1,max=1000,dive"`
+}
+
+// ImportUser is a user to import. PasswordHashFormat is empty for hashes that name their algorithm
+// (bcrypt, PHC strings and Django hashes), or one of firebase_scrypt, sha1, sha256 and sha512 along with
+// PasswordSalt and, for the SHA formats, PasswordSaltPosition.
+type ImportUser struct {
+	Username             string `json:"username"               validate:"required,alphanum,min=3,max=25"`
+	Email                string `json:"email"                  validate:"required,email"`
+	PasswordHash         string `json:"password_hash"          validate:"required,max=255"`
+	PasswordHashFormat   string `json:"password_hash_format"   validate:"omitempty,max=32"`
+	PasswordSalt         string `json:"password_salt"          validate:"max=255"`
+	PasswordSaltPosition string `json:"password_salt_position" validate:"omitempty,oneof=prefix suffix"`
+}
+
+// ImportUsersResponse lists the users created and why the others were not, by position in the request.
+type ImportUsersResponse struct {
+	Imported []ImportedUser      `json:"imported"`
+	Failed   []ImportUserFailure `json:"failed"`
+}
+
+type ImportedUser struct {
+	Index int   `json:"index"`
+	ID    int64 `json:"id"`
+}
+
+type ImportUserFailure struct {
+	Index int    `json:"index"`
+	Email string `json:"email"`
+	Error string `json:"error"`
+}
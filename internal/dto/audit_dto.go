@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+)
+
+type ListAuditEventsRequest struct {
+	Type     string     `validate:"max=64"`
+	Outcome  string     `validate:"omitempty,oneof=success failure"`
+	ActorID  uint       `validate:"omitempty"`
+	TargetID uint       `validate:"omitempty"`
+	IP       string     `validate:"max=64"`
+	Since    *time.Time `validate:"omitempty"`
+	Until    *time.Time `validate:"omitempty"`
+	Cursor   string     `validate:"max=64"`
+	Limit    int        `validate:"min=1,max=200"`
+}
+
+// ListAuditEventsResponse lists events newest first. NextCursor, when set, is passed as the cursor query
+// parameter to get the following page.
+type ListAuditEventsResponse struct {
+	Data       []AuditEventResponse `json:"data"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+}
+
+type AuditEventResponse struct {
+	ID             int64             `json:"id"`
+	OrganizationID *uint             `json:"organization_id,omitempty"`
+	Type           string            `json:"type"`
+	Outcome        string            `json:"outcome"`
+	ActorID        *uint             `json:"actor_id,omitempty"`
+	TargetID       *uint             `json:"target_id,omitempty"`
+	IP             string            `json:"ip,omitempty"`
+	UserAgent      string            `json:"user_agent,omitempty"`
+	Details        map[string]string `json:"details,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+}
+
+func NewAuditEventResponse(event *datastruct.AuditEvent) AuditEventResponse {
+	return AuditEventResponse{
+		ID:             int64(event.ID),
+		OrganizationID: event.OrganizationId,
+		Type:           event.Type,
+		Outcome:        event.Outcome,
+		ActorID:        event.ActorId,
+		TargetID:       event.TargetId,
+		IP:             event.IP,
+		UserAgent:      event.UserAgent,
+		Details:        event.Details,
+		CreatedAt:      event.CreatedAt,
+	}
+}
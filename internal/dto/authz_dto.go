@@ -0,0 +1,16 @@
+package dto
+
+// AuthzCheckRequest asks whether Subject may perform Action on Resource. When Subject is omitted
+// the decision is made for the caller.
+type AuthzCheckRequest struct {
+	Subject  map[string]interface{} `json:"subject"`
+	Action   string                 `json:"action"   validate:"required,max=128"`
+	Resource map[string]interface{} `json:"resource"`
+	Context  map[string]interface{} `json:"context"`
+}
+
+type AuthzCheckResponse struct {
+	Allowed  bool   `json:"allowed"`
+	PolicyID string `json:"policy_id,omitempty"`
+	Reason   string `json:"reason"`
+}
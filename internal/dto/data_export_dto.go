@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"time"
+)
+
+type DataExportResponse struct {
+	ID        int64     `json:"id"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DataExportArchive is the JSON document handed out by a personal data export.
+type DataExportArchive struct {
+	ExportedAt  time.Time                `json:"exported_at"`
+	Profile     *ProfileResponse         `json:"profile"`
+	Identities  []ExportedIdentity       `json:"identities"`
+	Memberships []ExportedMembership     `json:"memberships"`
+	Sessions    []ExportedSession        `json:"sessions"`
+	AuditEvents []ExportedAuditEvent     `json:"audit_events"`
+	Consents    []map[string]interface{} `json:"consents"`
+}
+
+// ExportedIdentity is an account linked to the user by an identity provider.
+type ExportedIdentity struct {
+	Provider       string `json:"provider"`
+	ExternalID     string `json:"external_id"`
+	OrganizationID int64  `json:"organization_id"`
+}
+
+type ExportedMembership struct {
+	OrganizationID   int64     `json:"organization_id"`
+	OrganizationName string    `json:"organization_name"`
+	Role             string    `json:"role"`
+	Groups           []string  `json:"groups"`
+	JoinedAt         time.Time `json:"joined_at"`
+}
+
+// ExportedSession leaves out the session ID, which still authenticates tokens until the session is revoked.
+type ExportedSession struct {
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+type ExportedAuditEvent struct {
+	Type           string                 `json:"type"`
+	OrganizationID int64                  `json:"organization_id"`
+	ActorID        int64                  `json:"actor_id"`
+	UserID         int64                  `json:"user_id"`
+	Details        map[string]interface{} `json:"details"`
+	CreatedAt      time.Time              `json:"created_at"`
+}
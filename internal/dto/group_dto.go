@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+)
+
+type GroupResponse struct {
+	ID             int64     `json:"id"`
+	OrganizationID int64     `json:"organization_id"`
+	Name           string    `json:"name"`
+	Description    string    `json:"description"`
+	Roles          []string  `json:"roles"`
+	Permissions    []string  `json:"permissions"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+func NewGroupResponse(group *datastruct.Group) *GroupResponse {
+	return &GroupResponse{
+		ID:             int64(group.ID),
+		OrganizationID: int64(group.OrganizationId),
+		Name:           group.Name,
+		Description:    group.Description,
+		Roles:          group.RoleNames(),
+		Permissions:    group.PermissionNames(),
+		CreatedAt:      group.CreatedAt,
+		UpdatedAt:      group.UpdatedAt,
+	}
+}
+
+type CreateGroupRequest struct {
+	Name        string   `json:"name"        validate:"required,min=2,max=64"`
+	Description string   `json:"description" validate:"max=255"`
+	Roles       []string `json:"roles"       validate:"dive,required,max=64"`
+	Permissions []string `json:"permissions" validate:"dive,required,max=128"`
+}
+
+// UpdateGroupRequest leaves omitted fields untouched; an empty list clears the roles or permissions.
+type UpdateGroupRequest struct {
+	Name        *string  `json:"name"        validate:"omitempty,min=2,max=64"`
+	Description *string  `json:"description" validate:"omitempty,max=255"`
+	Roles       []string `json:"roles"       validate:"omitempty,dive,required,max=64"`
+	Permissions []string `json:"permissions" validate:"omitempty,dive,required,max=128"`
+}
+
+// EffectivePermissionsResponse lists what a user is granted in an organization, merging the organization
+// role with the roles and permissions of the user's groups.
+type EffectivePermissionsResponse struct {
+	UserID         int64    `json:"user_id"`
+	OrganizationID int64    `json:"organization_id"`
+	Role           string   `json:"role"`
+	Groups         []string `json:"groups"`
+	Permissions    []string `json:"permissions"`
+}
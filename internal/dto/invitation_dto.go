@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+)
+
+type InvitationResponse struct {
+	ID             int64      `json:"id"`
+	OrganizationID int64      `json:"organization_id"`
+	Email          string     `json:"email"`
+	Role           string     `json:"role"`
+	Status         string     `json:"status"`
+	InvitedBy      int64      `json:"invited_by"`
+	ExpiredAt      time.Time  `json:"expired_at"`
+	AcceptedAt     *time.Time `json:"accepted_at,omitempty"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+func NewInvitationResponse(invitation *datastruct.Invitation) *InvitationResponse {
+	return &InvitationResponse{
+		ID:             int64(invitation.ID),
+		OrganizationID: int64(invitation.OrganizationId),
+		Email:          invitation.Email,
+		Role:           invitation.Role,
+		Status:         invitation.Status(time.Now()),
+		InvitedBy:      int64(invitation.InvitedBy),
+		ExpiredAt:      invitation.ExpiredAt,
+		AcceptedAt:     invitation.AcceptedAt,
+		RevokedAt:      invitation.RevokedAt,
+		CreatedAt:      invitation.CreatedAt,
+	}
+}
+
+type CreateInvitationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role"  validate:"required,max=64"`
+}
+
+// AcceptInvitationRequest needs Username and Password only when the invited email has no account yet.
+type AcceptInvitationRequest struct {
+	Token    string `json:"token"    validate:"required"`
+	Username string `json:"username" validate:"omitempty,alphanum,min=3,max=25"`
+	Password string `json:"password"`
+}
+
+type AcceptInvitationResponse struct {
+	UserID         int64  `json:"user_id"`
+	OrganizationID int64  `json:"organization_id"`
+	Role           string `json:"role"`
+	AccountCreated bool   `json:"account_created"`
+}
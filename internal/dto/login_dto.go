@@ -1,10 +1,24 @@
 package dto
 
+// LoginRequest is sent by the client, DeviceID comes from its device cookie.
 type LoginRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
+	DeviceID string `json:"-"`
 }
 
+// LoginResponse carries a token restricted to POST /me/password when PasswordChangeRequired is set. When
+// MFARequired is set there is no token yet: MFAToken and the code emailed to the user complete the login
+// at POST /login/verify.
 type LoginResponse struct {
-	Token string `json:"token"`
+	Token                  string `json:"token,omitempty"`
+	PasswordChangeRequired bool   `json:"password_change_required,omitempty"`
+	MFARequired            bool   `json:"mfa_required,omitempty"`
+	MFAToken               string `json:"mfa_token,omitempty"`
+}
+
+type VerifyLoginRequest struct {
+	Token    string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+	DeviceID string `json:"-"`
 }
@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+)
+
+type OrganizationResponse struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewOrganizationResponse(organization *datastruct.Organization) *OrganizationResponse {
+	return &OrganizationResponse{
+		ID:        int64(organization.ID),
+		Name:      organization.Name,
+		Slug:      organization.Slug,
+		CreatedAt: organization.CreatedAt,
+		UpdatedAt: organization.UpdatedAt,
+	}
+}
+
+type MembershipResponse struct {
+	Organization OrganizationResponse `json:"organization"`
+	Role         string               `json:"role"`
+	Active       bool                 `json:"active"`
+}
+
+type CreateOrganizationRequest struct {
+	Name string `json:"name" validate:"required,max=255"`
+	Slug string `json:"slug" validate:"required,lowercase,min=2,max=64"`
+}
+
+type SwitchOrganizationRequest struct {
+	OrganizationID uint `json:"organization_id" validate:"required"`
+}
@@ -0,0 +1,70 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+)
+
+type ProfileResponse struct {
+	ID             int64     `json:"id"`
+	OrganizationID int64     `json:"organization_id"`
+	Username       string    `json:"username"`
+	Email          string    `json:"email"`
+	DisplayName    string    `json:"display_name"`
+	Locale         string    `json:"locale"`
+	TimeZone       string    `json:"time_zone"`
+	Role           string    `json:"role"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+func NewProfileResponse(user *datastruct.User) *ProfileResponse {
+	return &ProfileResponse{
+		ID:             int64(user.ID),
+		OrganizationID: int64(user.OrganizationId),
+		Username:       user.Username,
+		Email:          user.Email,
+		DisplayName:    user.DisplayName,
+		Locale:         user.Locale,
+		TimeZone:       user.TimeZone,
+		Role:           user.Role,
+		CreatedAt:      user.CreatedAt,
+		UpdatedAt:      user.UpdatedAt,
+	}
+}
+
+// UpdateProfileRequest changes the fields that are set. UpdatedAt must be the updated_at last read from
+// GET /me, so concurrent edits are detected instead of silently overwritten. Empty strings clear Locale
+// and TimeZone.
+type UpdateProfileRequest struct {
+	Username    *string   `json:"username"     validate:"omitempty,alphanum,min=3,max=25"`
+	DisplayName *string   `json:"display_name" validate:"omitempty,max=100"`
+	Locale      *string   `json:"locale"       validate:"omitempty,eq=|bcp47_language_tag"`
+	TimeZone    *string   `json:"time_zone"    validate:"omitempty,eq=|timezone"`
+	UpdatedAt   time.Time `json:"updated_at"   validate:"required"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password"     validate:"required"`
+}
+
+type ChangeEmailRequest struct {
+	NewEmail        string `json:"new_email"        validate:"required,email,max=255"`
+	CurrentPassword string `json:"current_password" validate:"required"`
+}
+
+// EmailChangeTokenRequest carries the token of a confirmation or cancel link.
+type EmailChangeTokenRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type DeleteAccountRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+}
+
+type RestoreAccountRequest struct {
+	Email    string `json:"email"    validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
@@ -1,8 +1,8 @@
 package dto
 
 type ResetPasswordRequest struct {
-	Token       string `json:"token"`
-	NewPassword string `json:"new_password"`
+	Token       string `json:"token"        validate:"required"`
+	NewPassword string `json:"new_password" validate:"required"`
 }
 
 type ResetPasswordResponse struct {
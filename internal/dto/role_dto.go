@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+)
+
+type RoleResponse struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	BuiltIn     bool      `json:"built_in"`
+	Permissions []string  `json:"permissions"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func NewRoleResponse(role *datastruct.Role) *RoleResponse {
+	return &RoleResponse{
+		ID:          int64(role.ID),
+		Name:        role.Name,
+		Description: role.Description,
+		BuiltIn:     role.BuiltIn,
+		Permissions: role.PermissionNames(),
+		CreatedAt:   role.CreatedAt,
+		UpdatedAt:   role.UpdatedAt,
+	}
+}
+
+type CreateRoleRequest struct {
+	Name        string   `json:"name"        validate:"required,lowercase,min=3,max=64"`
+	Description string   `json:"description" validate:"max=255"`
+	Permissions []string `json:"permissions" validate:"dive,required,max=128"`
+}
+
+// UpdateRoleRequest leaves the permission set untouched when Permissions is omitted.
+type UpdateRoleRequest struct {
+	Description *string  `json:"description" validate:"omitempty,max=255"`
+	Permissions []string `json:"permissions" validate:"omitempty,dive,required,max=128"`
+}
+
+type PermissionResponse struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type CreatePermissionRequest struct {
+	Name        string `json:"name"        validate:"required,contains=:,max=128"`
+	Description string `json:"description" validate:"max=255"`
+}
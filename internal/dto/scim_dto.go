@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/fyfirman/auth-management-go/pkg/scim"
+)
+
+// SCIMUser is the core User resource of RFC 7643 limited to the attributes stored for a user. Other
+// attributes sent by clients, such as name, are accepted and ignored. Password is write only.
+type SCIMUser struct {
+	Schemas    []string      `json:"schemas"`
+	ID         string        `json:"id,omitempty"`
+	ExternalID string        `json:"externalId,omitempty"`
+	UserName   string        `json:"userName"`
+	Emails     []SCIMEmail   `json:"emails,omitempty"`
+	Active     *scim.Boolean `json:"active,omitempty"`
+	Password   string        `json:"password,omitempty"`
+	Groups     []SCIMMember  `json:"groups,omitempty"`
+	Meta       *scim.Meta    `json:"meta,omitempty"`
+}
+
+type SCIMEmail struct {
+	Value   string       `json:"value"`
+	Type    string       `json:"type,omitempty"`
+	Primary scim.Boolean `json:"primary,omitempty"`
+}
+
+type SCIMMember struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+type SCIMGroup struct {
+	Schemas     []string     `json:"schemas"`
+	ID          string       `json:"id,omitempty"`
+	ExternalID  string       `json:"externalId,omitempty"`
+	DisplayName string       `json:"displayName"`
+	Members     []SCIMMember `json:"members,omitempty"`
+	Meta        *scim.Meta   `json:"meta,omitempty"`
+}
+
+// SCIMListQuery holds the filter and the 1-based pagination of a SCIM list request.
+type SCIMListQuery struct {
+	Filter     string
+	StartIndex int
+	Count      int
+}
+
+type CreateSCIMTokenRequest struct {
+	Description string `json:"description" validate:"required,max=255"`
+}
+
+// SCIMTokenResponse only carries Token right after the token is created.
+type SCIMTokenResponse struct {
+	ID          int64     `json:"id"`
+	Description string    `json:"description"`
+	Token       string    `json:"token,omitempty"`
+	CreatedBy   int64     `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
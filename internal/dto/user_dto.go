@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+)
+
+type UserResponse struct {
+	ID               int64      `json:"id"`
+	OrganizationID   int64      `json:"organization_id"`
+	Username         string     `json:"username"`
+	Email            string     `json:"email"`
+	Role             string     `json:"role"`
+	OrganizationRole string     `json:"organization_role,omitempty"`
+	Disabled         bool       `json:"disabled"`
+	LockedUntil      *time.Time `json:"locked_until,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+func NewUserResponse(user *datastruct.User) *UserResponse {
+	return &UserResponse{
+		ID:               int64(user.ID),
+		OrganizationID:   int64(user.OrganizationId),
+		Username:         user.Username,
+		Email:            user.Email,
+		Role:             user.Role,
+		OrganizationRole: user.OrganizationRole,
+		Disabled:         user.Disabled,
+		LockedUntil:      user.LockedUntil,
+		CreatedAt:        user.CreatedAt,
+		UpdatedAt:        user.UpdatedAt,
+	}
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+	"github.com/fyfirman/auth-management-go/internal/tenant"
+	"github.com/fyfirman/auth-management-go/pkg/auditchain"
+	"gorm.io/gorm"
+)
+
+type AuditRepositoryInterface interface {
+	AppendAuditEvent(ctx context.Context, event *datastruct.AuditEvent) error
+	ListAuditEvents(ctx context.Context, filter AuditFilter) ([]datastruct.AuditEvent, error)
+	ListAuditEventsByUserId(ctx context.Context, userID uint) ([]datastruct.AuditEvent, error)
+	ListAuditChain(ctx context.Context, afterID uint, limit int) ([]datastruct.AuditEvent, error)
+	FindLastAuditEvent(ctx context.Context) (*datastruct.AuditEvent, error)
+}
+
+// AuditFilter selects audit events, newest first. Zero values do not filter. BeforeId continues a listing
+// after the last event of the previous page.
+type AuditFilter struct {
+	Type     string
+	Outcome  string
+	ActorId  uint
+	TargetId uint
+	IP       string
+	Since    *time.Time
+	Until    *time.Time
+	BeforeId uint
+	Limit    int
+}
+
+type AuditRepository struct{}
+
+func NewAuditRepository() *AuditRepository {
+	return &AuditRepository{}
+}
+
+// auditChainLock is the key of the advisory lock serializing the appends to the audit chain.
+const auditChainLock = 0x61756469
+
+// AppendAuditEvent chains event to the last event and inserts it. A transaction advisory lock serializes the
+// appends, so each event links to the one inserted right before, without locking the table for anyone else.
+func (r *AuditRepository) AppendAuditEvent(ctx context.Context, event *datastruct.AuditEvent) error {
+	return DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", auditChainLock).Error; err != nil {
+			return err
+		}
+		var last datastruct.AuditEvent
+		if err := tx.Select("hash").Order("id DESC").Limit(1).Find(&last).Error; err != nil {
+			return err
+		}
+
+		// Postgres keeps microseconds, the hash must cover the time as it is read back
+		event.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+		event.PrevHash = last.Hash
+		event.Hash = auditchain.Hash(event.PrevHash, event.ChainPayload())
+		return tx.Create(event).Error
+	})
+}
+
+// ListAuditEvents returns the events matching filter, limited to the organization of ctx when it is scoped
+// to one.
+func (r *AuditRepository) ListAuditEvents(
+	ctx context.Context,
+	filter AuditFilter,
+) ([]datastruct.AuditEvent, error) {
+	query := DB.WithContext(ctx).Model(&datastruct.AuditEvent{})
+	if organizationID, ok := tenant.OrganizationFromContext(ctx); ok {
+		query = query.Where("organization_id = ?", organizationID)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Outcome != "" {
+		query = query.Where("outcome = ?", filter.Outcome)
+	}
+	if filter.ActorId != 0 {
+		query = query.Where("actor_id = ?", filter.ActorId)
+	}
+	if filter.TargetId != 0 {
+		query = query.Where("target_id = ?", filter.TargetId)
+	}
+	if filter.IP != "" {
+		query = query.Where("ip = ?", filter.IP)
+	}
+	if filter.Since != nil {
+		query = query.Where("created_at >= ?", *filter.Since)
+	}
+	if filter.Until != nil {
+		query = query.Where("created_at < ?", *filter.Until)
+	}
+	if filter.BeforeId != 0 {
+		query = query.Where("id < ?", filter.BeforeId)
+	}
+
+	var events []datastruct.AuditEvent
+	err := query.Order("id DESC").Limit(filter.Limit).Find(&events).Error
+	return events, err
+}
+
+// ListAuditEventsByUserId returns the events the user was the actor or the target of, oldest first.
+func (r *AuditRepository) ListAuditEventsByUserId(
+	ctx context.Context,
+	userID uint,
+) ([]datastruct.AuditEvent, error) {
+	var events []datastruct.AuditEvent
+	err := DB.WithContext(ctx).Where("actor_id = ? OR target_id = ?", userID, userID).Order("id").
+		Find(&events).Error
+	return events, err
+}
+
+// ListAuditChain returns up to limit events of every organization after the event afterID, in chain order.
+func (r *AuditRepository) ListAuditChain(
+	ctx context.Context,
+	afterID uint,
+	limit int,
+) ([]datastruct.AuditEvent, error) {
+	var events []datastruct.AuditEvent
+	err := DB.WithContext(ctx).Where("id > ?", afterID).Order("id").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+// FindLastAuditEvent returns the head of the audit chain.
+func (r *AuditRepository) FindLastAuditEvent(ctx context.Context) (*datastruct.AuditEvent, error) {
+	var event datastruct.AuditEvent
+	if err := DB.WithContext(ctx).Order("id DESC").First(&event).Error; err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
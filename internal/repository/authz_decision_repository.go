@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+)
+
+type AuthzDecisionRepositoryInterface interface {
+	CreateDecision(ctx context.Context, decision *datastruct.AuthzDecision) error
+}
+
+type AuthzDecisionRepository struct{}
+
+func NewAuthzDecisionRepository() *AuthzDecisionRepository {
+	return &AuthzDecisionRepository{}
+}
+
+func (r *AuthzDecisionRepository) CreateDecision(ctx context.Context, decision *datastruct.AuthzDecision) error {
+	result := DB.WithContext(ctx).Create(decision)
+	return result.Error
+}
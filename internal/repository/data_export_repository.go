@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DataExportRepositoryInterface interface {
+	CreateDataExport(ctx context.Context, export *datastruct.DataExport) error
+	ClaimPendingDataExports(ctx context.Context, limit int, staleBefore time.Time) ([]datastruct.DataExport, error)
+	SaveDataExport(ctx context.Context, export *datastruct.DataExport) error
+	FindDataExportByToken(ctx context.Context, token string) (*datastruct.DataExport, error)
+	DeleteExpiredDataExports(ctx context.Context, now time.Time) error
+}
+
+type DataExportRepository struct{}
+
+func NewDataExportRepository() *DataExportRepository {
+	return &DataExportRepository{}
+}
+
+func (r *DataExportRepository) CreateDataExport(ctx context.Context, export *datastruct.DataExport) error {
+	result := DB.WithContext(ctx).Create(export)
+	return result.Error
+}
+
+// ClaimPendingDataExports moves up to limit pending exports to processing and returns them, together with
+// the exports claimed before staleBefore whose job never finished them. Rows locked by another instance are
+// skipped, so each export is built once.
+func (r *DataExportRepository) ClaimPendingDataExports(
+	ctx context.Context,
+	limit int,
+	staleBefore time.Time,
+) ([]datastruct.DataExport, error) {
+	var exports []datastruct.DataExport
+	err := DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? OR (status = ? AND claimed_at < ?)",
+				datastruct.DataExportPending, datastruct.DataExportProcessing, staleBefore).
+			Order("id").
+			Limit(limit).
+			Find(&exports).Error
+		if err != nil || len(exports) == 0 {
+			return err
+		}
+
+		now := time.Now()
+		ids := make([]uint, 0, len(exports))
+		for i := range exports {
+			exports[i].Status = datastruct.DataExportProcessing
+			exports[i].ClaimedAt = &now
+			ids = append(ids, exports[i].ID)
+		}
+		return tx.Model(&datastruct.DataExport{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{"status": datastruct.DataExportProcessing, "claimed_at": now}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+func (r *DataExportRepository) SaveDataExport(ctx context.Context, export *datastruct.DataExport) error {
+	result := DB.WithContext(ctx).Save(export)
+	return result.Error
+}
+
+func (r *DataExportRepository) FindDataExportByToken(ctx context.Context, token string) (*datastruct.DataExport, error) {
+	var export datastruct.DataExport
+	result := DB.WithContext(ctx).Where("token = ?", token).First(&export)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &export, nil
+}
+
+// DeleteExpiredDataExports removes the ready and failed exports that expired before now.
+func (r *DataExportRepository) DeleteExpiredDataExports(ctx context.Context, now time.Time) error {
+	return DB.WithContext(ctx).Where("expired_at < ?", now).Delete(&datastruct.DataExport{}).Error
+}
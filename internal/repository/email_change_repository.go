@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+	"gorm.io/gorm"
+)
+
+type EmailChangeRepositoryInterface interface {
+	CreateEmailChange(ctx context.Context, change *datastruct.EmailChange) error
+	FindEmailChangeByToken(ctx context.Context, token string) (*datastruct.EmailChange, error)
+	FindEmailChangeByCancelToken(ctx context.Context, cancelToken string) (*datastruct.EmailChange, error)
+	ConfirmEmailChange(ctx context.Context, change *datastruct.EmailChange) error
+	CancelEmailChange(ctx context.Context, change *datastruct.EmailChange) error
+}
+
+type EmailChangeRepository struct{}
+
+func NewEmailChangeRepository() *EmailChangeRepository {
+	return &EmailChangeRepository{}
+}
+
+// CreateEmailChange stores the change and cancels the other pending changes of the user, so only the
+// latest link can be confirmed.
+func (r *EmailChangeRepository) CreateEmailChange(ctx context.Context, change *datastruct.EmailChange) error {
+	return DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&datastruct.EmailChange{}).
+			Where("user_id = ? AND confirmed_at IS NULL AND cancelled_at IS NULL", change.UserId).
+			Update("cancelled_at", time.Now()).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(change).Error
+	})
+}
+
+func (r *EmailChangeRepository) FindEmailChangeByToken(
+	ctx context.Context,
+	token string,
+) (*datastruct.EmailChange, error) {
+	var change datastruct.EmailChange
+	result := DB.WithContext(ctx).Where("token = ?", token).First(&change)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &change, nil
+}
+
+func (r *EmailChangeRepository) FindEmailChangeByCancelToken(
+	ctx context.Context,
+	cancelToken string,
+) (*datastruct.EmailChange, error) {
+	var change datastruct.EmailChange
+	result := DB.WithContext(ctx).Where("cancel_token = ?", cancelToken).First(&change)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &change, nil
+}
+
+// ConfirmEmailChange moves the user to the new email, marks the change confirmed and deletes the password
+// reset tokens of the user in one transaction. The change is claimed with a conditional update, so a change
+// that was confirmed, cancelled or expired in the meantime returns gorm.ErrRecordNotFound. The unique index
+// on users.email keeps the address from being taken twice.
+func (r *EmailChangeRepository) ConfirmEmailChange(ctx context.Context, change *datastruct.EmailChange) error {
+	now := time.Now()
+	err := DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&datastruct.EmailChange{}).
+			Where("id = ? AND confirmed_at IS NULL AND cancelled_at IS NULL AND expired_at > ?", change.ID, now).
+			Update("confirmed_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		result = tx.Model(&datastruct.User{}).Where("id = ?", change.UserId).Update("email", change.NewEmail)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("user_id = ?", change.UserId).Delete(&datastruct.Token{}).Error
+	})
+	if err != nil {
+		return err
+	}
+	change.ConfirmedAt = &now
+	return nil
+}
+
+func (r *EmailChangeRepository) CancelEmailChange(ctx context.Context, change *datastruct.EmailChange) error {
+	now := time.Now()
+	change.CancelledAt = &now
+	return DB.WithContext(ctx).Model(change).Update("cancelled_at", now).Error
+}
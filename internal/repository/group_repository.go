@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+	"github.com/fyfirman/auth-management-go/pkg/scim"
+	"gorm.io/gorm"
+)
+
+// GroupRepositoryInterface looks groups up within an organization. Groups are loaded with their roles,
+// the permissions of those roles and their direct permissions.
+type GroupRepositoryInterface interface {
+	ListGroups(ctx context.Context, organizationID uint) ([]datastruct.Group, error)
+	FindGroups(ctx context.Context, filter GroupFilter) ([]datastruct.Group, int64, error)
+	FindGroupById(ctx context.Context, organizationID uint, id uint) (*datastruct.Group, error)
+	CreateGroup(ctx context.Context, group *datastruct.Group) error
+	SaveGroup(ctx context.Context, group *datastruct.Group) error
+	DeleteGroupById(ctx context.Context, organizationID uint, id uint) error
+	ListGroupMembers(ctx context.Context, groupID uint) ([]datastruct.User, error)
+	AddGroupMember(ctx context.Context, member *datastruct.GroupMember) error
+	RemoveGroupMember(ctx context.Context, groupID uint, userID uint) error
+	ListGroupsByUserId(ctx context.Context, organizationID uint, userID uint) ([]datastruct.Group, error)
+}
+
+// GroupFilter pages through the groups of an organization, optionally restricted with a SCIM filter on the
+// attributes of scimGroupColumns.
+type GroupFilter struct {
+	OrganizationID uint
+	SCIM           scim.Filter
+	Limit          int
+	Offset         int
+}
+
+var scimGroupColumns = map[string]scim.Column{
+	"id":                {Name: "groups.id", Type: scim.ColumnNumber},
+	"externalid":        {Name: "groups.external_id", CaseExact: true},
+	"displayname":       {Name: "groups.name"},
+	"meta.created":      {Name: "groups.created_at", Type: scim.ColumnTime},
+	"meta.lastmodified": {Name: "groups.updated_at", Type: scim.ColumnTime},
+}
+
+type GroupRepository struct{}
+
+func NewGroupRepository() *GroupRepository {
+	return &GroupRepository{}
+}
+
+func preloadGroup(ctx context.Context) *gorm.DB {
+	return DB.WithContext(ctx).Preload("Roles.Permissions").Preload("Permissions")
+}
+
+func (r *GroupRepository) ListGroups(ctx context.Context, organizationID uint) ([]datastruct.Group, error) {
+	var groups []datastruct.Group
+	result := preloadGroup(ctx).Where("organization_id = ?", organizationID).Order("name").Find(&groups)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return groups, nil
+}
+
+func (r *GroupRepository) FindGroups(ctx context.Context, filter GroupFilter) ([]datastruct.Group, int64, error) {
+	query := DB.WithContext(ctx).Model(&datastruct.Group{}).Where("organization_id = ?", filter.OrganizationID)
+	if filter.SCIM != nil {
+		condition, args, err := scim.ToSQL(filter.SCIM, scimGroupColumns)
+		if err != nil {
+			return nil, 0, err
+		}
+		query = query.Where(condition, args...)
+	}
+
+	var total int64
+	if result := query.Count(&total); result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	var groups []datastruct.Group
+	result := query.Preload("Roles.Permissions").Preload("Permissions").Order("groups.id").Find(&groups)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+	return groups, total, nil
+}
+
+func (r *GroupRepository) FindGroupById(ctx context.Context, organizationID uint, id uint) (*datastruct.Group, error) {
+	var group datastruct.Group
+	result := preloadGroup(ctx).Where("organization_id = ? AND id = ?", organizationID, id).First(&group)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &group, nil
+}
+
+func (r *GroupRepository) CreateGroup(ctx context.Context, group *datastruct.Group) error {
+	result := DB.WithContext(ctx).Omit("Roles.*", "Permissions.*").Create(group)
+	return result.Error
+}
+
+// SaveGroup updates the group columns and replaces its roles and permissions.
+func (r *GroupRepository) SaveGroup(ctx context.Context, group *datastruct.Group) error {
+	return DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Roles", "Permissions").Save(group).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(group).Association("Roles").Replace(group.Roles); err != nil {
+			return err
+		}
+		return tx.Model(group).Association("Permissions").Replace(group.Permissions)
+	})
+}
+
+func (r *GroupRepository) DeleteGroupById(ctx context.Context, organizationID uint, id uint) error {
+	result := DB.WithContext(ctx).Where("organization_id = ? AND id = ?", organizationID, id).Delete(&datastruct.Group{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *GroupRepository) ListGroupMembers(ctx context.Context, groupID uint) ([]datastruct.User, error) {
+	var users []datastruct.User
+	result := DB.WithContext(ctx).
+		Select("users.*").
+		Joins("JOIN group_members ON group_members.user_id = users.id").
+		Where("group_members.group_id = ?", groupID).
+		Order("users.id").
+		Find(&users)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return users, nil
+}
+
+func (r *GroupRepository) AddGroupMember(ctx context.Context, member *datastruct.GroupMember) error {
+	result := DB.WithContext(ctx).Create(member)
+	return result.Error
+}
+
+func (r *GroupRepository) RemoveGroupMember(ctx context.Context, groupID uint, userID uint) error {
+	result := DB.WithContext(ctx).
+		Where("group_id = ? AND user_id = ?", groupID, userID).
+		Delete(&datastruct.GroupMember{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *GroupRepository) ListGroupsByUserId(
+	ctx context.Context,
+	organizationID uint,
+	userID uint,
+) ([]datastruct.Group, error) {
+	var groups []datastruct.Group
+	result := preloadGroup(ctx).
+		Where("organization_id = ?", organizationID).
+		Where("id IN (?)", DB.Model(&datastruct.GroupMember{}).Select("group_id").Where("user_id = ?", userID)).
+		Order("name").
+		Find(&groups)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return groups, nil
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+	"gorm.io/gorm"
+)
+
+type InvitationRepositoryInterface interface {
+	CreateInvitation(ctx context.Context, invitation *datastruct.Invitation) error
+	FindInvitationById(ctx context.Context, organizationID uint, id uint) (*datastruct.Invitation, error)
+	FindInvitationByToken(ctx context.Context, token string) (*datastruct.Invitation, error)
+	ListInvitations(ctx context.Context, organizationID uint) ([]datastruct.Invitation, error)
+	UpdateInvitation(ctx context.Context, invitation *datastruct.Invitation) error
+	AcceptInvitation(ctx context.Context, invitation *datastruct.Invitation, user *datastruct.User) error
+}
+
+type InvitationRepository struct{}
+
+func NewInvitationRepository() *InvitationRepository {
+	return &InvitationRepository{}
+}
+
+func (r *InvitationRepository) CreateInvitation(ctx context.Context, invitation *datastruct.Invitation) error {
+	result := DB.WithContext(ctx).Create(invitation)
+	return result.Error
+}
+
+func (r *InvitationRepository) FindInvitationById(
+	ctx context.Context,
+	organizationID uint,
+	id uint,
+) (*datastruct.Invitation, error) {
+	var invitation datastruct.Invitation
+	result := DB.WithContext(ctx).Where("organization_id = ? AND id = ?", organizationID, id).First(&invitation)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &invitation, nil
+}
+
+func (r *InvitationRepository) FindInvitationByToken(ctx context.Context, token string) (*datastruct.Invitation, error) {
+	var invitation datastruct.Invitation
+	result := DB.WithContext(ctx).Where("token = ?", token).First(&invitation)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &invitation, nil
+}
+
+func (r *InvitationRepository) ListInvitations(ctx context.Context, organizationID uint) ([]datastruct.Invitation, error) {
+	var invitations []datastruct.Invitation
+	result := DB.WithContext(ctx).Where("organization_id = ?", organizationID).Order("id DESC").Find(&invitations)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return invitations, nil
+}
+
+func (r *InvitationRepository) UpdateInvitation(ctx context.Context, invitation *datastruct.Invitation) error {
+	result := DB.WithContext(ctx).Save(invitation)
+	return result.Error
+}
+
+// AcceptInvitation marks the invitation accepted and adds user to its organization in one transaction. A user
+// without an ID is created first. The invitation is claimed with a conditional update, so an invitation that
+// was accepted, revoked or expired in the meantime returns gorm.ErrRecordNotFound and changes nothing.
+func (r *InvitationRepository) AcceptInvitation(
+	ctx context.Context,
+	invitation *datastruct.Invitation,
+	user *datastruct.User,
+) error {
+	now := time.Now()
+	err := DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&datastruct.Invitation{}).
+			Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL AND expired_at > ?", invitation.ID, now).
+			Update("accepted_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if user.ID == 0 {
+			if err := tx.Omit("OrganizationRole").Create(user).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(&datastruct.OrganizationMember{
+			OrganizationId: invitation.OrganizationId,
+			UserId:         user.ID,
+			Role:           invitation.Role,
+		}).Error
+	})
+	if err != nil {
+		return err
+	}
+	invitation.AcceptedAt = &now
+	return nil
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LoginDeviceRepositoryInterface interface {
+	ListLoginDevices(ctx context.Context, userID uint) ([]datastruct.LoginDevice, error)
+	SaveLoginDevice(ctx context.Context, device *datastruct.LoginDevice) error
+	CreateLoginChallenge(ctx context.Context, challenge *datastruct.LoginChallenge) error
+	FindLoginChallengeByTokenHash(ctx context.Context, tokenHash string) (*datastruct.LoginChallenge, error)
+	RecordLoginChallengeAttempt(ctx context.Context, id uint) (int, error)
+	DeleteLoginChallenge(ctx context.Context, id uint) error
+	DeleteExpiredLoginChallenges(ctx context.Context, now time.Time) (int64, error)
+}
+
+type LoginDeviceRepository struct{}
+
+func NewLoginDeviceRepository() *LoginDeviceRepository {
+	return &LoginDeviceRepository{}
+}
+
+// ListLoginDevices returns the devices of the user, most recently seen first.
+func (r *LoginDeviceRepository) ListLoginDevices(ctx context.Context, userID uint) ([]datastruct.LoginDevice, error) {
+	var devices []datastruct.LoginDevice
+	err := DB.WithContext(ctx).Where("user_id = ?", userID).Order("last_seen_at DESC").Find(&devices).Error
+	return devices, err
+}
+
+// SaveLoginDevice adds the device, or refreshes where and when it was last seen when the user already has
+// a device of that fingerprint.
+func (r *LoginDeviceRepository) SaveLoginDevice(ctx context.Context, device *datastruct.LoginDevice) error {
+	return DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "fingerprint"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"name", "ip", "country", "region", "city", "latitude", "longitude", "last_seen_at",
+		}),
+	}).Create(device).Error
+}
+
+// CreateLoginChallenge adds the challenge, replacing the one the user already has so an older code stops
+// working, even when logins race.
+func (r *LoginDeviceRepository) CreateLoginChallenge(ctx context.Context, challenge *datastruct.LoginChallenge) error {
+	return DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"token_hash", "code_hash", "fingerprint", "attempts", "expired_at", "created_at",
+		}),
+	}).Create(challenge).Error
+}
+
+func (r *LoginDeviceRepository) FindLoginChallengeByTokenHash(
+	ctx context.Context,
+	tokenHash string,
+) (*datastruct.LoginChallenge, error) {
+	var challenge datastruct.LoginChallenge
+	if err := DB.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&challenge).Error; err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+// RecordLoginChallengeAttempt counts an attempt at the code of the challenge and returns the attempts so
+// far, including concurrent ones.
+func (r *LoginDeviceRepository) RecordLoginChallengeAttempt(ctx context.Context, id uint) (int, error) {
+	var challenge datastruct.LoginChallenge
+	result := DB.WithContext(ctx).Model(&challenge).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "attempts"}}}).
+		Where("id = ?", id).
+		UpdateColumn("attempts", gorm.Expr("attempts + 1"))
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return 0, gorm.ErrRecordNotFound
+	}
+	return challenge.Attempts, nil
+}
+
+func (r *LoginDeviceRepository) DeleteLoginChallenge(ctx context.Context, id uint) error {
+	return DB.WithContext(ctx).Delete(&datastruct.LoginChallenge{}, id).Error
+}
+
+// DeleteExpiredLoginChallenges removes the challenges expired at now.
+func (r *LoginDeviceRepository) DeleteExpiredLoginChallenges(ctx context.Context, now time.Time) (int64, error) {
+	result := DB.WithContext(ctx).Where("expired_at < ?", now).Delete(&datastruct.LoginChallenge{})
+	return result.RowsAffected, result.Error
+}
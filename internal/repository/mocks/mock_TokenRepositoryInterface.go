@@ -32,6 +32,24 @@ func (_m *TokenRepositoryInterface) CreateToken(ctx context.Context, user *datas
 	return r0
 }
 
+// DeleteToken provides a mock function with given fields: ctx, token
+func (_m *TokenRepositoryInterface) DeleteToken(ctx context.Context, token string) error {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // FindByToken provides a mock function with given fields: ctx, token
 func (_m *TokenRepositoryInterface) FindByToken(ctx context.Context, token string) (*datastruct.Token, error) {
 	ret := _m.Called(ctx, token)
@@ -4,8 +4,10 @@ package mocks
 
 import (
 	context "context"
+	time "time"
 
 	datastruct "github.com/fyfirman/auth-management-go/internal/datastruct"
+	repository "github.com/fyfirman/auth-management-go/internal/repository"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -14,6 +16,24 @@ type UserRepositoryInterface struct {
 	mock.Mock
 }
 
+// ChangePasswordById provides a mock function with given fields: ctx, id, passwordHash, historySize
+func (_m *UserRepositoryInterface) ChangePasswordById(ctx context.Context, id uint, passwordHash string, historySize int) error {
+	ret := _m.Called(ctx, id, passwordHash, historySize)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangePasswordById")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string, int) error); ok {
+		r0 = rf(ctx, id, passwordHash, historySize)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateUser provides a mock function with given fields: ctx, user
 func (_m *UserRepositoryInterface) CreateUser(ctx context.Context, user *datastruct.User) error {
 	ret := _m.Called(ctx, user)
@@ -32,6 +52,24 @@ func (_m *UserRepositoryInterface) CreateUser(ctx context.Context, user *datastr
 	return r0
 }
 
+// DeleteUserById provides a mock function with given fields: ctx, id
+func (_m *UserRepositoryInterface) DeleteUserById(ctx context.Context, id uint) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUserById")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // FindByEmail provides a mock function with given fields: ctx, email
 func (_m *UserRepositoryInterface) FindByEmail(ctx context.Context, email string) (*datastruct.User, error) {
 	ret := _m.Called(ctx, email)
@@ -62,6 +100,273 @@ func (_m *UserRepositoryInterface) FindByEmail(ctx context.Context, email string
 	return r0, r1
 }
 
+// FindById provides a mock function with given fields: ctx, id
+func (_m *UserRepositoryInterface) FindById(ctx context.Context, id uint) (*datastruct.User, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindById")
+	}
+
+	var r0 *datastruct.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*datastruct.User, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *datastruct.User); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datastruct.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindDeletedUserByEmail provides a mock function with given fields: ctx, email
+func (_m *UserRepositoryInterface) FindDeletedUserByEmail(ctx context.Context, email string) (*datastruct.User, error) {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindDeletedUserByEmail")
+	}
+
+	var r0 *datastruct.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*datastruct.User, error)); ok {
+		return rf(ctx, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *datastruct.User); ok {
+		r0 = rf(ctx, email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datastruct.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindDeletedUserById provides a mock function with given fields: ctx, id
+func (_m *UserRepositoryInterface) FindDeletedUserById(ctx context.Context, id uint) (*datastruct.User, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindDeletedUserById")
+	}
+
+	var r0 *datastruct.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*datastruct.User, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *datastruct.User); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*datastruct.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPasswordHistory provides a mock function with given fields: ctx, id, limit
+func (_m *UserRepositoryInterface) ListPasswordHistory(ctx context.Context, id uint, limit int) ([]datastruct.PasswordHistory, error) {
+	ret := _m.Called(ctx, id, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPasswordHistory")
+	}
+
+	var r0 []datastruct.PasswordHistory
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, int) ([]datastruct.PasswordHistory, error)); ok {
+		return rf(ctx, id, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, int) []datastruct.PasswordHistory); ok {
+		r0 = rf(ctx, id, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]datastruct.PasswordHistory)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, int) error); ok {
+		r1 = rf(ctx, id, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListUsers provides a mock function with given fields: ctx, filter
+func (_m *UserRepositoryInterface) ListUsers(ctx context.Context, filter repository.UserFilter) ([]datastruct.User, int64, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUsers")
+	}
+
+	var r0 []datastruct.User
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.UserFilter) ([]datastruct.User, int64, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.UserFilter) []datastruct.User); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]datastruct.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.UserFilter) int64); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, repository.UserFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// LockUserById provides a mock function with given fields: ctx, id, until
+func (_m *UserRepositoryInterface) LockUserById(ctx context.Context, id uint, until time.Time) error {
+	ret := _m.Called(ctx, id, until)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockUserById")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, time.Time) error); ok {
+		r0 = rf(ctx, id, until)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PurgeDeletedUsers provides a mock function with given fields: ctx, deletedBefore
+func (_m *UserRepositoryInterface) PurgeDeletedUsers(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	ret := _m.Called(ctx, deletedBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeDeletedUsers")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, deletedBefore)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, deletedBefore)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, deletedBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RecordFailedLogin provides a mock function with given fields: ctx, id
+func (_m *UserRepositoryInterface) RecordFailedLogin(ctx context.Context, id uint) (int, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordFailedLogin")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (int, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) int); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResetFailedLogins provides a mock function with given fields: ctx, id
+func (_m *UserRepositoryInterface) ResetFailedLogins(ctx context.Context, id uint) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetFailedLogins")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RestoreUserById provides a mock function with given fields: ctx, id
+func (_m *UserRepositoryInterface) RestoreUserById(ctx context.Context, id uint) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreUserById")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdatePasswordById provides a mock function with given fields: ctx, id, passwordHash
 func (_m *UserRepositoryInterface) UpdatePasswordById(ctx context.Context, id uint, passwordHash string) (*datastruct.User, error) {
 	ret := _m.Called(ctx, id, passwordHash)
@@ -92,6 +397,42 @@ func (_m *UserRepositoryInterface) UpdatePasswordById(ctx context.Context, id ui
 	return r0, r1
 }
 
+// UpdateUser provides a mock function with given fields: ctx, user
+func (_m *UserRepositoryInterface) UpdateUser(ctx context.Context, user *datastruct.User) error {
+	ret := _m.Called(ctx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datastruct.User) error); ok {
+		r0 = rf(ctx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateUserIfUnmodified provides a mock function with given fields: ctx, user, updatedAt
+func (_m *UserRepositoryInterface) UpdateUserIfUnmodified(ctx context.Context, user *datastruct.User, updatedAt time.Time) error {
+	ret := _m.Called(ctx, user, updatedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUserIfUnmodified")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *datastruct.User, time.Time) error); ok {
+		r0 = rf(ctx, user, updatedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewUserRepositoryInterface creates a new instance of UserRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserRepositoryInterface(t interface {
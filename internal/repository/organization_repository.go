@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+	"gorm.io/gorm"
+)
+
+type OrganizationRepositoryInterface interface {
+	CreateOrganization(ctx context.Context, organization *datastruct.Organization) error
+	FindOrganizationById(ctx context.Context, id uint) (*datastruct.Organization, error)
+	FindOrganizationBySlug(ctx context.Context, slug string) (*datastruct.Organization, error)
+	ListOrganizations(ctx context.Context) ([]datastruct.Organization, error)
+	AddMember(ctx context.Context, member *datastruct.OrganizationMember) error
+	FindMember(ctx context.Context, organizationID uint, userID uint) (*datastruct.OrganizationMember, error)
+	ListMembershipsByUserId(ctx context.Context, userID uint) ([]datastruct.OrganizationMember, error)
+	UpdateMemberRole(ctx context.Context, member *datastruct.OrganizationMember, change *datastruct.RoleChange) error
+	RemoveMember(ctx context.Context, organizationID uint, userID uint) error
+	ListRoleChangesByUserId(ctx context.Context, userID uint) ([]datastruct.RoleChange, error)
+}
+
+type OrganizationRepository struct{}
+
+func NewOrganizationRepository() *OrganizationRepository {
+	return &OrganizationRepository{}
+}
+
+func (r *OrganizationRepository) CreateOrganization(ctx context.Context, organization *datastruct.Organization) error {
+	result := DB.WithContext(ctx).Create(organization)
+	return result.Error
+}
+
+func (r *OrganizationRepository) FindOrganizationById(ctx context.Context, id uint) (*datastruct.Organization, error) {
+	var organization datastruct.Organization
+	result := DB.WithContext(ctx).Where("id = ?", id).First(&organization)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &organization, nil
+}
+
+func (r *OrganizationRepository) FindOrganizationBySlug(
+	ctx context.Context,
+	slug string,
+) (*datastruct.Organization, error) {
+	var organization datastruct.Organization
+	result := DB.WithContext(ctx).Where("slug = ?", slug).First(&organization)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &organization, nil
+}
+
+func (r *OrganizationRepository) ListOrganizations(ctx context.Context) ([]datastruct.Organization, error) {
+	var organizations []datastruct.Organization
+	result := DB.WithContext(ctx).Order("id").Find(&organizations)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return organizations, nil
+}
+
+func (r *OrganizationRepository) AddMember(ctx context.Context, member *datastruct.OrganizationMember) error {
+	result := DB.WithContext(ctx).Create(member)
+	return result.Error
+}
+
+func (r *OrganizationRepository) FindMember(
+	ctx context.Context,
+	organizationID uint,
+	userID uint,
+) (*datastruct.OrganizationMember, error) {
+	var member datastruct.OrganizationMember
+	result := DB.WithContext(ctx).
+		Where("organization_id = ? AND user_id = ?", organizationID, userID).
+		First(&member)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &member, nil
+}
+
+func (r *OrganizationRepository) ListMembershipsByUserId(
+	ctx context.Context,
+	userID uint,
+) ([]datastruct.OrganizationMember, error) {
+	var members []datastruct.OrganizationMember
+	result := DB.WithContext(ctx).Preload("Organization").
+		Where("user_id = ?", userID).
+		Order("organization_id").
+		Find(&members)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return members, nil
+}
+
+// UpdateMemberRole stores the member's new role together with its audit record in a single transaction.
+func (r *OrganizationRepository) UpdateMemberRole(
+	ctx context.Context,
+	member *datastruct.OrganizationMember,
+	change *datastruct.RoleChange,
+) error {
+	return DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&datastruct.OrganizationMember{}).
+			Where("organization_id = ? AND user_id = ?", member.OrganizationId, member.UserId).
+			Update("role", member.Role)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Create(change).Error
+	})
+}
+
+// RemoveMember removes the user from the organization and from its groups.
+func (r *OrganizationRepository) RemoveMember(ctx context.Context, organizationID uint, userID uint) error {
+	return DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("organization_id = ? AND user_id = ?", organizationID, userID).
+			Delete(&datastruct.OrganizationMember{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("user_id = ? AND group_id IN (?)", userID, tx.Model(&datastruct.Group{}).
+			Select("id").
+			Where("organization_id = ?", organizationID)).
+			Delete(&datastruct.GroupMember{}).Error
+	})
+}
+
+// ListRoleChangesByUserId returns the role changes the user was the subject or the actor of, oldest first.
+func (r *OrganizationRepository) ListRoleChangesByUserId(
+	ctx context.Context,
+	userID uint,
+) ([]datastruct.RoleChange, error) {
+	var changes []datastruct.RoleChange
+	result := DB.WithContext(ctx).Where("user_id = ? OR actor_id = ?", userID, userID).Order("id").Find(&changes)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return changes, nil
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+	"github.com/fyfirman/auth-management-go/pkg/ratelimit"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RateLimitRepository is a ratelimit.Store keeping the buckets in Postgres, so every instance of the
+// service draws from the same budget.
+type RateLimitRepository struct{}
+
+func NewRateLimitRepository() *RateLimitRepository {
+	return &RateLimitRepository{}
+}
+
+// Take locks the bucket row for the duration of the take, creating it full on first use.
+func (r *RateLimitRepository) Take(
+	ctx context.Context,
+	key string,
+	limit ratelimit.Limit,
+	now time.Time,
+) (ratelimit.Result, error) {
+	var result ratelimit.Result
+	err := DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		initial := ratelimit.NewBucket(limit, now)
+		err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&datastruct.RateLimitBucket{
+			Key:       key,
+			Tokens:    initial.Tokens,
+			UpdatedAt: initial.UpdatedAt,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		var row datastruct.RateLimitBucket
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("key = ?", key).First(&row).Error
+		if err != nil {
+			return err
+		}
+
+		bucket := ratelimit.Bucket{Tokens: row.Tokens, UpdatedAt: row.UpdatedAt}
+		result = bucket.Take(limit, now)
+		return tx.Model(&row).Updates(map[string]interface{}{
+			"tokens":     bucket.Tokens,
+			"updated_at": bucket.UpdatedAt,
+		}).Error
+	})
+	return result, err
+}
+
+// DeleteIdleBuckets removes the buckets untouched since idleSince. Once refilled they behave like new ones.
+func (r *RateLimitRepository) DeleteIdleBuckets(ctx context.Context, idleSince time.Time) (int64, error) {
+	result := DB.WithContext(ctx).Where("updated_at < ?", idleSince).Delete(&datastruct.RateLimitBucket{})
+	return result.RowsAffected, result.Error
+}
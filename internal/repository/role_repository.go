@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+	"gorm.io/gorm"
+)
+
+type RoleRepositoryInterface interface {
+	ListRoles(ctx context.Context) ([]datastruct.Role, error)
+	FindRoleById(ctx context.Context, id uint) (*datastruct.Role, error)
+	FindRoleByName(ctx context.Context, name string) (*datastruct.Role, error)
+	CreateRole(ctx context.Context, role *datastruct.Role) error
+	SaveRole(ctx context.Context, role *datastruct.Role) error
+	DeleteRoleById(ctx context.Context, id uint) error
+	ListPermissions(ctx context.Context) ([]datastruct.Permission, error)
+	FindPermissionsByNames(ctx context.Context, names []string) ([]datastruct.Permission, error)
+	CreatePermission(ctx context.Context, permission *datastruct.Permission) error
+}
+
+type RoleRepository struct{}
+
+func NewRoleRepository() *RoleRepository {
+	return &RoleRepository{}
+}
+
+func (r *RoleRepository) ListRoles(ctx context.Context) ([]datastruct.Role, error) {
+	var roles []datastruct.Role
+	result := DB.WithContext(ctx).Preload("Permissions").Order("id").Find(&roles)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return roles, nil
+}
+
+func (r *RoleRepository) FindRoleById(ctx context.Context, id uint) (*datastruct.Role, error) {
+	var role datastruct.Role
+	result := DB.WithContext(ctx).Preload("Permissions").Where("id = ?", id).First(&role)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &role, nil
+}
+
+func (r *RoleRepository) FindRoleByName(ctx context.Context, name string) (*datastruct.Role, error) {
+	var role datastruct.Role
+	result := DB.WithContext(ctx).Preload("Permissions").Where("name = ?", name).First(&role)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &role, nil
+}
+
+func (r *RoleRepository) CreateRole(ctx context.Context, role *datastruct.Role) error {
+	result := DB.WithContext(ctx).Create(role)
+	return result.Error
+}
+
+// SaveRole updates the role columns and replaces its permission set.
+func (r *RoleRepository) SaveRole(ctx context.Context, role *datastruct.Role) error {
+	return DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Permissions").Save(role).Error; err != nil {
+			return err
+		}
+		return tx.Model(role).Association("Permissions").Replace(role.Permissions)
+	})
+}
+
+func (r *RoleRepository) DeleteRoleById(ctx context.Context, id uint) error {
+	result := DB.WithContext(ctx).Where("id = ?", id).Delete(&datastruct.Role{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *RoleRepository) ListPermissions(ctx context.Context) ([]datastruct.Permission, error) {
+	var permissions []datastruct.Permission
+	result := DB.WithContext(ctx).Order("name").Find(&permissions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return permissions, nil
+}
+
+func (r *RoleRepository) FindPermissionsByNames(ctx context.Context, names []string) ([]datastruct.Permission, error) {
+	var permissions []datastruct.Permission
+	result := DB.WithContext(ctx).Where("name IN ?", names).Find(&permissions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return permissions, nil
+}
+
+func (r *RoleRepository) CreatePermission(ctx context.Context, permission *datastruct.Permission) error {
+	result := DB.WithContext(ctx).Create(permission)
+	return result.Error
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+	"gorm.io/gorm"
+)
+
+type ScimTokenRepositoryInterface interface {
+	CreateScimToken(ctx context.Context, token *datastruct.ScimToken) error
+	FindScimTokenByHash(ctx context.Context, tokenHash string) (*datastruct.ScimToken, error)
+	ListScimTokens(ctx context.Context, organizationID uint) ([]datastruct.ScimToken, error)
+	DeleteScimTokenById(ctx context.Context, organizationID uint, id uint) error
+}
+
+type ScimTokenRepository struct{}
+
+func NewScimTokenRepository() *ScimTokenRepository {
+	return &ScimTokenRepository{}
+}
+
+func (r *ScimTokenRepository) CreateScimToken(ctx context.Context, token *datastruct.ScimToken) error {
+	result := DB.WithContext(ctx).Create(token)
+	return result.Error
+}
+
+func (r *ScimTokenRepository) FindScimTokenByHash(ctx context.Context, tokenHash string) (*datastruct.ScimToken, error) {
+	var token datastruct.ScimToken
+	result := DB.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &token, nil
+}
+
+func (r *ScimTokenRepository) ListScimTokens(ctx context.Context, organizationID uint) ([]datastruct.ScimToken, error) {
+	var tokens []datastruct.ScimToken
+	result := DB.WithContext(ctx).Where("organization_id = ?", organizationID).Order("id").Find(&tokens)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return tokens, nil
+}
+
+func (r *ScimTokenRepository) DeleteScimTokenById(ctx context.Context, organizationID uint, id uint) error {
+	result := DB.WithContext(ctx).
+		Where("organization_id = ? AND id = ?", organizationID, id).
+		Delete(&datastruct.ScimToken{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+)
+
+type SessionRepositoryInterface interface {
+	CreateSession(ctx context.Context, session *datastruct.Session) error
+	FindSessionById(ctx context.Context, id string) (*datastruct.Session, error)
+	ListSessionsByUserId(ctx context.Context, userID uint) ([]datastruct.Session, error)
+	RevokeSessionsByUserId(ctx context.Context, userID uint, exceptID string) error
+}
+
+type SessionRepository struct{}
+
+func NewSessionRepository() *SessionRepository {
+	return &SessionRepository{}
+}
+
+func (r *SessionRepository) CreateSession(ctx context.Context, session *datastruct.Session) error {
+	result := DB.WithContext(ctx).Create(session)
+	return result.Error
+}
+
+func (r *SessionRepository) FindSessionById(ctx context.Context, id string) (*datastruct.Session, error) {
+	var session datastruct.Session
+	result := DB.WithContext(ctx).Where("id = ?", id).First(&session)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &session, nil
+}
+
+func (r *SessionRepository) ListSessionsByUserId(ctx context.Context, userID uint) ([]datastruct.Session, error) {
+	var sessions []datastruct.Session
+	result := DB.WithContext(ctx).Where("user_id = ?", userID).Order("created_at").Find(&sessions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return sessions, nil
+}
+
+// RevokeSessionsByUserId revokes every active session of the user except exceptID, which may be empty.
+func (r *SessionRepository) RevokeSessionsByUserId(ctx context.Context, userID uint, exceptID string) error {
+	query := DB.WithContext(ctx).Model(&datastruct.Session{}).Where("user_id = ? AND revoked_at IS NULL", userID)
+	if exceptID != "" {
+		query = query.Where("id <> ?", exceptID)
+	}
+	return query.Update("revoked_at", time.Now()).Error
+}
@@ -9,6 +9,7 @@ import (
 type TokenRepositoryInterface interface {
 	CreateToken(ctx context.Context, user *datastruct.Token) error
 	FindByToken(ctx context.Context, token string) (*datastruct.Token, error)
+	DeleteToken(ctx context.Context, token string) error
 }
 
 type TokenRepository struct{}
@@ -30,3 +31,8 @@ func (r *TokenRepository) FindByToken(ctx context.Context, token string) (*datas
 	}
 	return &tokenData, nil
 }
+
+// DeleteToken removes a password reset token once it has been used.
+func (r *TokenRepository) DeleteToken(ctx context.Context, token string) error {
+	return DB.WithContext(ctx).Where("token = ?", token).Delete(&datastruct.Token{}).Error
+}
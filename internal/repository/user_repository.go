@@ -2,14 +2,77 @@ package repository
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/fyfirman/auth-management-go/internal/datastruct"
+	"github.com/fyfirman/auth-management-go/internal/tenant"
+	"github.com/fyfirman/auth-management-go/pkg/scim"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// UserRepositoryInterface queries are restricted to the members of the organization set with
+// tenant.WithOrganization, and unrestricted when ctx carries no organization.
 type UserRepositoryInterface interface {
 	CreateUser(ctx context.Context, user *datastruct.User) error
 	FindByEmail(ctx context.Context, email string) (*datastruct.User, error)
+	FindById(ctx context.Context, id uint) (*datastruct.User, error)
+	ListUsers(ctx context.Context, filter UserFilter) ([]datastruct.User, int64, error)
+	UpdateUser(ctx context.Context, user *datastruct.User) error
+	UpdateUserIfUnmodified(ctx context.Context, user *datastruct.User, updatedAt time.Time) error
 	UpdatePasswordById(ctx context.Context, id uint, passwordHash string) (*datastruct.User, error)
+	ChangePasswordById(ctx context.Context, id uint, passwordHash string, historySize int) error
+	ListPasswordHistory(ctx context.Context, id uint, limit int) ([]datastruct.PasswordHistory, error)
+	DeleteUserById(ctx context.Context, id uint) error
+	FindDeletedUserByEmail(ctx context.Context, email string) (*datastruct.User, error)
+	FindDeletedUserById(ctx context.Context, id uint) (*datastruct.User, error)
+	RestoreUserById(ctx context.Context, id uint) error
+	PurgeDeletedUsers(ctx context.Context, deletedBefore time.Time) (int64, error)
+	RecordFailedLogin(ctx context.Context, id uint) (int, error)
+	LockUserById(ctx context.Context, id uint, until time.Time) error
+	ResetFailedLogins(ctx context.Context, id uint) error
+}
+
+// UserFilter narrows down and orders the result of ListUsers. Zero values are ignored. SCIM restricts the
+// users with a SCIM filter on the attributes of scimUserColumns.
+type UserFilter struct {
+	Role          string
+	Email         string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	SCIM          scim.Filter
+	SortBy        string
+	SortDesc      bool
+	Limit         int
+	Offset        int
+}
+
+var userSortColumns = map[string]string{
+	"id":         "users.id",
+	"username":   "users.username",
+	"email":      "users.email",
+	"role":       "users.role",
+	"created_at": "users.created_at",
+	"updated_at": "users.updated_at",
+}
+
+var scimUserColumns = map[string]scim.Column{
+	"id":                {Name: "users.id", Type: scim.ColumnNumber},
+	"externalid":        {Name: "users.external_id", CaseExact: true},
+	"username":          {Name: "users.username"},
+	"emails":            {Name: "users.email"},
+	"emails.value":      {Name: "users.email"},
+	"active":            {Name: "(NOT users.disabled)", Type: scim.ColumnBool},
+	"meta.created":      {Name: "users.created_at", Type: scim.ColumnTime},
+	"meta.lastmodified": {Name: "users.updated_at", Type: scim.ColumnTime},
+}
+
+// userUpdateOmitted are the columns UpdateUser never writes. The login lockout columns only change through
+// the lockout methods, so saving a user read before a failed login does not reset its counters, and the
+// password change time through ChangePasswordById.
+var userUpdateOmitted = []string{
+	"ID", "CreatedAt", "FailedLoginAttempts", "LockoutCount", "LockedUntil", "PasswordChangedAt",
 }
 
 type UserRepository struct{}
@@ -18,26 +81,354 @@ func NewUserRepository() *UserRepository {
 	return &UserRepository{}
 }
 
+// selectUsers joins the organization membership when ctx is scoped to an organization,
+// exposing the role held there as organization_role.
+func selectUsers(ctx context.Context) *gorm.DB {
+	query := DB.WithContext(ctx).Model(&datastruct.User{})
+	if organizationID, ok := tenant.OrganizationFromContext(ctx); ok {
+		query = query.Select("users.*, organization_members.role AS organization_role").
+			Joins("JOIN organization_members ON organization_members.user_id = users.id"+
+				" AND organization_members.organization_id = ?", organizationID)
+	}
+	return query
+}
+
+// likeEscaper escapes the wildcards of LIKE patterns with the default escape character.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// escapeLike makes value match literally inside a LIKE pattern.
+func escapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
+
+// scopeMembers restricts write statements on users to the members of the ctx organization.
+func scopeMembers(ctx context.Context, query *gorm.DB) *gorm.DB {
+	if organizationID, ok := tenant.OrganizationFromContext(ctx); ok {
+		return query.Where("id IN (?)", DB.Model(&datastruct.OrganizationMember{}).
+			Select("user_id").
+			Where("organization_id = ?", organizationID))
+	}
+	return query
+}
+
+// CreateUser stores the user and makes it a member of its organization with user.OrganizationRole,
+// falling back to the user's role when it is empty.
 func (r *UserRepository) CreateUser(ctx context.Context, user *datastruct.User) error {
-	result := DB.WithContext(ctx).Create(user)
-	return result.Error
+	memberRole := user.OrganizationRole
+	if memberRole == "" {
+		memberRole = user.Role
+	}
+	return DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("OrganizationRole").Create(user).Error; err != nil {
+			return err
+		}
+		return tx.Create(&datastruct.OrganizationMember{
+			OrganizationId: user.OrganizationId,
+			UserId:         user.ID,
+			Role:           memberRole,
+		}).Error
+	})
 }
 
 func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*datastruct.User, error) {
 	var user datastruct.User
-	result := DB.WithContext(ctx).Where("email = ?", email).First(&user)
+	result := selectUsers(ctx).Where("users.email = ?", email).First(&user)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &user, nil
+}
+
+func (r *UserRepository) FindById(ctx context.Context, id uint) (*datastruct.User, error) {
+	var user datastruct.User
+	result := selectUsers(ctx).Where("users.id = ?", id).First(&user)
 	if result.Error != nil {
 		return nil, result.Error
 	}
 	return &user, nil
 }
 
+func (r *UserRepository) ListUsers(ctx context.Context, filter UserFilter) ([]datastruct.User, int64, error) {
+	query := selectUsers(ctx)
+	if filter.Role != "" {
+		if _, ok := tenant.OrganizationFromContext(ctx); ok {
+			query = query.Where("organization_members.role = ?", filter.Role)
+		} else {
+			query = query.Where("users.role = ?", filter.Role)
+		}
+	}
+	if filter.Email != "" {
+		query = query.Where("users.email ILIKE ?", "%"+escapeLike(filter.Email)+"%")
+	}
+	if filter.CreatedAfter != nil {
+		query = query.Where("users.created_at >= ?", *filter.CreatedAfter)
+	}
+	if filter.CreatedBefore != nil {
+		query = query.Where("users.created_at < ?", *filter.CreatedBefore)
+	}
+	if filter.SCIM != nil {
+		condition, args, err := scim.ToSQL(filter.SCIM, scimUserColumns)
+		if err != nil {
+			return nil, 0, err
+		}
+		query = query.Where(condition, args...)
+	}
+
+	var total int64
+	if result := query.Count(&total); result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	column, ok := userSortColumns[filter.SortBy]
+	if !ok {
+		column = "users.id"
+	}
+	if filter.SortDesc {
+		column += " DESC"
+	}
+	query = query.Order(column)
+
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	var users []datastruct.User
+	if result := query.Find(&users); result.Error != nil {
+		return nil, 0, result.Error
+	}
+	return users, total, nil
+}
+
+func (r *UserRepository) UpdateUser(ctx context.Context, user *datastruct.User) error {
+	query := scopeMembers(ctx, DB.WithContext(ctx).Model(user))
+	result := query.Select("*").Omit(userUpdateOmitted...).Updates(user)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// UpdateUserIfUnmodified saves the user only while its updated_at still equals updatedAt. It returns
+// gorm.ErrRecordNotFound when the row is missing or was modified in the meantime.
+func (r *UserRepository) UpdateUserIfUnmodified(ctx context.Context, user *datastruct.User, updatedAt time.Time) error {
+	query := scopeMembers(ctx, DB.WithContext(ctx).Model(user)).Where("updated_at = ?", updatedAt)
+	result := query.Select("*").Omit(userUpdateOmitted...).Updates(user)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
 func (r *UserRepository) UpdatePasswordById(ctx context.Context, id uint, passwordHash string) (*datastruct.User, error) {
 	var user datastruct.User
-	result := DB.WithContext(ctx).Model(&user).Where("id = ?", id).Update("password_hash", passwordHash)
+	result := scopeMembers(ctx, DB.WithContext(ctx).Model(&user)).Where("id = ?", id).Update("password_hash", passwordHash)
 	if result.Error != nil {
 		return nil, result.Error
 	}
 
 	return &user, nil
 }
+
+// ChangePasswordById sets a password the user chose, restarting its maximum age, and records it in the
+// password history, which keeps the historySize most recent ones. UpdatePasswordById only replaces the hash
+// of the same password.
+func (r *UserRepository) ChangePasswordById(ctx context.Context, id uint, passwordHash string, historySize int) error {
+	return DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := scopeMembers(ctx, tx.Model(&datastruct.User{})).Where("id = ?", id).Updates(map[string]interface{}{
+			"password_hash":       passwordHash,
+			"password_changed_at": time.Now(),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if historySize > 0 {
+			err := tx.Create(&datastruct.PasswordHistory{UserId: id, PasswordHash: passwordHash}).Error
+			if err != nil {
+				return err
+			}
+		}
+		kept := tx.Model(&datastruct.PasswordHistory{}).Select("id").Where("user_id = ?", id).
+			Order("created_at DESC, id DESC").Limit(historySize)
+		return tx.Where("user_id = ? AND id NOT IN (?)", id, kept).Delete(&datastruct.PasswordHistory{}).Error
+	})
+}
+
+// ListPasswordHistory returns the limit passwords the user set most recently, newest first.
+func (r *UserRepository) ListPasswordHistory(
+	ctx context.Context,
+	id uint,
+	limit int,
+) ([]datastruct.PasswordHistory, error) {
+	var history []datastruct.PasswordHistory
+	err := DB.WithContext(ctx).Where("user_id = ?", id).Order("created_at DESC, id DESC").Limit(limit).
+		Find(&history).Error
+	return history, err
+}
+
+// DeleteUserById soft deletes the user, revokes their sessions and drops their password reset tokens. The
+// account stays restorable until PurgeDeletedUsers removes it.
+func (r *UserRepository) DeleteUserById(ctx context.Context, id uint) error {
+	return DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := scopeMembers(ctx, tx.Where("id = ?", id)).Delete(&datastruct.User{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&datastruct.Token{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&datastruct.Session{}).
+			Where("user_id = ? AND revoked_at IS NULL", id).
+			Update("revoked_at", time.Now()).Error
+	})
+}
+
+func (r *UserRepository) FindDeletedUserByEmail(ctx context.Context, email string) (*datastruct.User, error) {
+	var user datastruct.User
+	result := selectUsers(ctx).Unscoped().
+		Where("users.email = ? AND users.deleted_at IS NOT NULL", email).
+		First(&user)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &user, nil
+}
+
+func (r *UserRepository) FindDeletedUserById(ctx context.Context, id uint) (*datastruct.User, error) {
+	var user datastruct.User
+	result := selectUsers(ctx).Unscoped().
+		Where("users.id = ? AND users.deleted_at IS NOT NULL", id).
+		First(&user)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &user, nil
+}
+
+func (r *UserRepository) RestoreUserById(ctx context.Context, id uint) error {
+	query := scopeMembers(ctx, DB.WithContext(ctx).Unscoped().Model(&datastruct.User{}))
+	result := query.Where("id = ? AND deleted_at IS NOT NULL", id).Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// PurgeDeletedUsers permanently removes the users deleted before deletedBefore together with their tokens,
+// the role changes made to them and the invitations sent to their email. Rows that only name them as the
+// actor, such as role changes they made, invitations they sent, SCIM tokens they issued and authorization
+// checks they asked for, are kept with the ID set to 0. Sessions, memberships and other rows owned by the
+// users are removed by their foreign keys.
+func (r *UserRepository) PurgeDeletedUsers(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	var purged int64
+	err := DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		expired := func(column string) *gorm.DB {
+			return tx.Unscoped().Model(&datastruct.User{}).
+				Select(column).
+				Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore)
+		}
+
+		if err := tx.Where("user_id IN (?)", expired("id")).Delete(&datastruct.Token{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id IN (?)", expired("id")).Delete(&datastruct.RoleChange{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("email IN (?)", expired("email")).Delete(&datastruct.Invitation{}).Error; err != nil {
+			return err
+		}
+
+		anonymized := []struct {
+			model  interface{}
+			column string
+		}{
+			{&datastruct.RoleChange{}, "actor_id"},
+			{&datastruct.Invitation{}, "invited_by"},
+			{&datastruct.ScimToken{}, "created_by"},
+			{&datastruct.AuthzDecision{}, "caller_id"},
+		}
+		for _, table := range anonymized {
+			err := tx.Model(table.model).Where(table.column+" IN (?)", expired("id")).UpdateColumn(table.column, 0).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		result := tx.Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+			Delete(&datastruct.User{})
+		purged = result.RowsAffected
+		return result.Error
+	})
+	return purged, err
+}
+
+// RecordFailedLogin increments the consecutive failed logins of the user and returns the new count. The
+// login state columns are updated without touching updated_at. Deleted users count too, as restoring them
+// checks their password.
+func (r *UserRepository) RecordFailedLogin(ctx context.Context, id uint) (int, error) {
+	var user datastruct.User
+	result := DB.WithContext(ctx).Unscoped().Model(&user).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "failed_login_attempts"}}}).
+		Where("id = ?", id).
+		UpdateColumn("failed_login_attempts", gorm.Expr("failed_login_attempts + 1"))
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return 0, gorm.ErrRecordNotFound
+	}
+	return user.FailedLoginAttempts, nil
+}
+
+// LockUserById locks the user until the given time, counting the lock and restarting the failed logins.
+// Deleted users can be locked, like RecordFailedLogin counts their failures.
+func (r *UserRepository) LockUserById(ctx context.Context, id uint, until time.Time) error {
+	result := DB.WithContext(ctx).Unscoped().Model(&datastruct.User{}).Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"locked_until":          until,
+			"lockout_count":         gorm.Expr("lockout_count + 1"),
+			"failed_login_attempts": 0,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ResetFailedLogins clears the failed logins, the lock and its escalation.
+func (r *UserRepository) ResetFailedLogins(ctx context.Context, id uint) error {
+	query := scopeMembers(ctx, DB.WithContext(ctx).Model(&datastruct.User{})).Where("id = ?", id)
+	result := query.UpdateColumns(map[string]interface{}{
+		"failed_login_attempts": 0,
+		"lockout_count":         0,
+		"locked_until":          nil,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
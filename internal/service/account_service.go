@@ -0,0 +1,401 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+	"github.com/fyfirman/auth-management-go/internal/dto"
+	"github.com/fyfirman/auth-management-go/internal/repository"
+	"github.com/fyfirman/auth-management-go/internal/tenant"
+	"github.com/fyfirman/auth-management-go/pkg/mail_server"
+	"gorm.io/gorm"
+)
+
+// AccountServiceInterface is the self-service side of user management: everything acts on the account of
+// the authenticated user, whichever organization the token is scoped to.
+type AccountServiceInterface interface {
+	GetProfile(ctx context.Context, actor *Claims) (*dto.ProfileResponse, error)
+	UpdateProfile(ctx context.Context, actor *Claims, req dto.UpdateProfileRequest) (*dto.ProfileResponse, error)
+	ChangePassword(ctx context.Context, actor *Claims, req dto.ChangePasswordRequest) error
+	RequestEmailChange(ctx context.Context, actor *Claims, req dto.ChangeEmailRequest) error
+	ConfirmEmailChange(ctx context.Context, req dto.EmailChangeTokenRequest) (*dto.ProfileResponse, error)
+	CancelEmailChange(ctx context.Context, req dto.EmailChangeTokenRequest) error
+	DeleteAccount(ctx context.Context, actor *Claims, req dto.DeleteAccountRequest) error
+	RestoreAccount(ctx context.Context, req dto.RestoreAccountRequest) (*dto.ProfileResponse, error)
+	PurgeDeletedAccounts(ctx context.Context) (int64, error)
+}
+
+const (
+	emailChangeExpiry = 24 * time.Hour
+
+	// defaultDeletionGracePeriod applies when ACCOUNT_DELETION_GRACE_PERIOD is not set.
+	defaultDeletionGracePeriod = 30 * 24 * time.Hour
+)
+
+type AccountService struct {
+	userRepository        repository.UserRepositoryInterface
+	sessionRepository     repository.SessionRepositoryInterface
+	emailChangeRepository repository.EmailChangeRepositoryInterface
+	mailer                mail_server.MailInterface
+	auditRecorder         AuditRecorder
+}
+
+func NewAccountService(
+	userRepository repository.UserRepositoryInterface,
+	sessionRepository repository.SessionRepositoryInterface,
+	emailChangeRepository repository.EmailChangeRepositoryInterface,
+	mailer mail_server.MailInterface,
+	auditRecorder AuditRecorder,
+) *AccountService {
+	return &AccountService{
+		userRepository:        userRepository,
+		sessionRepository:     sessionRepository,
+		emailChangeRepository: emailChangeRepository,
+		mailer:                mailer,
+		auditRecorder:         auditRecorder,
+	}
+}
+
+func (s *AccountService) GetProfile(ctx context.Context, actor *Claims) (*dto.ProfileResponse, error) {
+	user, err := s.findAccount(ctx, actor)
+	if err != nil {
+		return nil, err
+	}
+	return dto.NewProfileResponse(user), nil
+}
+
+// UpdateProfile applies the changes only if the profile is unchanged since req.UpdatedAt, and fails with
+// ErrProfileModified otherwise.
+func (s *AccountService) UpdateProfile(
+	ctx context.Context,
+	actor *Claims,
+	req dto.UpdateProfileRequest,
+) (*dto.ProfileResponse, error) {
+	user, err := s.findAccount(ctx, actor)
+	if err != nil {
+		return nil, err
+	}
+	if !user.UpdatedAt.Equal(req.UpdatedAt) {
+		return nil, ErrProfileModified
+	}
+
+	if req.Username != nil {
+		user.Username = *req.Username
+	}
+	if req.DisplayName != nil {
+		user.DisplayName = *req.DisplayName
+	}
+	if req.Locale != nil {
+		user.Locale = *req.Locale
+	}
+	if req.TimeZone != nil {
+		user.TimeZone = *req.TimeZone
+	}
+
+	err = s.userRepository.UpdateUserIfUnmodified(accountContext(ctx), user, req.UpdatedAt)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// The account was loaded above, so it was modified in between.
+		return nil, ErrProfileModified
+	}
+	if err != nil {
+		return nil, mapUserError(err)
+	}
+	return dto.NewProfileResponse(user), nil
+}
+
+// ChangePassword replaces the password after checking the current one, then revokes every other session
+// of the user and notifies them by email. A failing notification does not undo the change.
+func (s *AccountService) ChangePassword(ctx context.Context, actor *Claims, req dto.ChangePasswordRequest) error {
+	user, err := s.findAccount(ctx, actor)
+	if err != nil {
+		return err
+	}
+
+	if match, _ := verifyPassword(user.PasswordHash, req.CurrentPassword); !match {
+		return ErrInvalidCurrentPassword
+	}
+	if match, _ := verifyPassword(user.PasswordHash, req.NewPassword); match {
+		return ErrPasswordReused
+	}
+	if err := setPassword(accountContext(ctx), s.userRepository, user, "NewPassword", req.NewPassword); err != nil {
+		return mapUserError(err)
+	}
+
+	if err := s.sessionRepository.RevokeSessionsByUserId(ctx, user.ID, actor.SessionID); err != nil {
+		return err
+	}
+	auditSessionsRevoked(ctx, s.auditRecorder, auditID(user.ID), user.ID, "password_change", actor.SessionID)
+
+	if err := s.notify(user, "Your password was changed",
+		"The password of your account was just changed and your other sessions were signed out. "+
+			"If this was not you, reset your password immediately : "+os.Getenv("BASE_URL")+"/forgot-password"); err != nil {
+		log.Printf("Failed to send the password change notification to user %d: %v", user.ID, err)
+	}
+	return nil
+}
+
+// RequestEmailChange sends a confirmation link to the new address and a notice with a cancel link to the
+// current one. The account keeps its email until the change is confirmed.
+func (s *AccountService) RequestEmailChange(ctx context.Context, actor *Claims, req dto.ChangeEmailRequest) error {
+	user, err := s.findAccount(ctx, actor)
+	if err != nil {
+		return err
+	}
+
+	if match, _ := verifyPassword(user.PasswordHash, req.CurrentPassword); !match {
+		return ErrInvalidCurrentPassword
+	}
+	if strings.EqualFold(user.Email, req.NewEmail) {
+		return ErrEmailUnchanged
+	}
+	if err := s.ensureEmailAvailable(ctx, req.NewEmail); err != nil {
+		return err
+	}
+
+	token, err := generateForgotPasswordToken()
+	if err != nil {
+		return err
+	}
+	cancelToken, err := generateForgotPasswordToken()
+	if err != nil {
+		return err
+	}
+	change := &datastruct.EmailChange{
+		UserId:      user.ID,
+		NewEmail:    req.NewEmail,
+		Token:       hashToken(token),
+		CancelToken: hashToken(cancelToken),
+		ExpiredAt:   time.Now().Add(emailChangeExpiry),
+	}
+	if err := s.emailChangeRepository.CreateEmailChange(ctx, change); err != nil {
+		return err
+	}
+
+	if err := s.send(change.NewEmail, "Confirm your new email address",
+		"Confirm that this address should be used for your account : "+
+			os.Getenv("BASE_URL")+"/email-change/confirm/"+token); err != nil {
+		return err
+	}
+
+	if err := s.notify(user, "Your email address is being changed",
+		"A change of your account email to "+html.EscapeString(change.NewEmail)+" was requested. "+
+			"If this was not you, cancel it here : "+os.Getenv("BASE_URL")+"/email-change/cancel/"+cancelToken+
+			" and change your password."); err != nil {
+		log.Printf("Failed to send the email change notice to user %d: %v", user.ID, err)
+	}
+	return nil
+}
+
+// ConfirmEmailChange swaps the account email for the one the token was sent to. Password reset links sent
+// to the old address stop working and every session of the account is signed out.
+func (s *AccountService) ConfirmEmailChange(
+	ctx context.Context,
+	req dto.EmailChangeTokenRequest,
+) (*dto.ProfileResponse, error) {
+	change, err := s.emailChangeRepository.FindEmailChangeByToken(ctx, hashToken(req.Token))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrEmailChangeInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !change.Pending(time.Now()) {
+		return nil, ErrEmailChangeInvalid
+	}
+
+	err = s.emailChangeRepository.ConfirmEmailChange(ctx, change)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrEmailChangeInvalid
+	}
+	if err != nil {
+		return nil, mapUserError(err)
+	}
+	if err := s.sessionRepository.RevokeSessionsByUserId(ctx, change.UserId, ""); err != nil {
+		return nil, err
+	}
+	auditSessionsRevoked(ctx, s.auditRecorder, auditID(change.UserId), change.UserId, "email_change", "")
+
+	user, err := s.userRepository.FindById(accountContext(ctx), change.UserId)
+	if err != nil {
+		return nil, mapUserError(err)
+	}
+	return dto.NewProfileResponse(user), nil
+}
+
+// CancelEmailChange stops a pending change through the link sent to the current address.
+func (s *AccountService) CancelEmailChange(ctx context.Context, req dto.EmailChangeTokenRequest) error {
+	change, err := s.emailChangeRepository.FindEmailChangeByCancelToken(ctx, hashToken(req.Token))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrEmailChangeInvalid
+	}
+	if err != nil {
+		return err
+	}
+	if !change.Pending(time.Now()) {
+		return ErrEmailChangeInvalid
+	}
+	return s.emailChangeRepository.CancelEmailChange(ctx, change)
+}
+
+// DeleteAccount soft deletes the account of the actor and signs out all of their sessions. The account can
+// be restored with RestoreAccount until the deletion grace period is over.
+func (s *AccountService) DeleteAccount(ctx context.Context, actor *Claims, req dto.DeleteAccountRequest) error {
+	gracePeriod, err := DeletionGracePeriod()
+	if err != nil {
+		return err
+	}
+
+	user, err := s.findAccount(ctx, actor)
+	if err != nil {
+		return err
+	}
+	if match, _ := verifyPassword(user.PasswordHash, req.CurrentPassword); !match {
+		return ErrInvalidCurrentPassword
+	}
+
+	if err := s.userRepository.DeleteUserById(accountContext(ctx), user.ID); err != nil {
+		return mapUserError(err)
+	}
+	auditSessionsRevoked(ctx, s.auditRecorder, auditID(user.ID), user.ID, "account_deletion", "")
+
+	purgeDate := time.Now().Add(gracePeriod).Format("2 January 2006")
+	if err := s.notify(user, "Your account was deleted",
+		"Your account was deleted and will be permanently removed on "+purgeDate+". "+
+			"To keep it, restore it before then : "+os.Getenv("BASE_URL")+"/restore-account"); err != nil {
+		log.Printf("Failed to send the account deletion notice to user %d: %v", user.ID, err)
+	}
+	return nil
+}
+
+// RestoreAccount brings back a deleted account within the grace period given its email and password.
+// Every failure is reported as ErrAccountNotRestorable so the response does not reveal which accounts exist.
+func (s *AccountService) RestoreAccount(
+	ctx context.Context,
+	req dto.RestoreAccountRequest,
+) (*dto.ProfileResponse, error) {
+	user, err := s.userRepository.FindDeletedUserByEmail(accountContext(ctx), req.Email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAccountNotRestorable
+	}
+	if err != nil {
+		return nil, err
+	}
+	match, _ := verifyPassword(user.PasswordHash, req.Password)
+	if user.Locked(time.Now()) {
+		return nil, ErrAccountNotRestorable
+	}
+	if !match {
+		if err := recordFailedLogin(ctx, s.userRepository, s.mailer, user); err != nil {
+			return nil, err
+		}
+		return nil, ErrAccountNotRestorable
+	}
+
+	restored, err := restoreUser(accountContext(ctx), s.userRepository, user)
+	if err != nil {
+		return nil, err
+	}
+	if restored.FailedLoginAttempts > 0 || restored.LockoutCount > 0 || restored.LockedUntil != nil {
+		if err := s.userRepository.ResetFailedLogins(accountContext(ctx), restored.ID); err != nil {
+			return nil, err
+		}
+	}
+	return dto.NewProfileResponse(restored), nil
+}
+
+// PurgeDeletedAccounts permanently removes the accounts deleted longer than the grace period ago and
+// returns how many were removed.
+func (s *AccountService) PurgeDeletedAccounts(ctx context.Context) (int64, error) {
+	gracePeriod, err := DeletionGracePeriod()
+	if err != nil {
+		return 0, err
+	}
+	return s.userRepository.PurgeDeletedUsers(ctx, time.Now().Add(-gracePeriod))
+}
+
+func (s *AccountService) ensureEmailAvailable(ctx context.Context, email string) error {
+	_, err := s.userRepository.FindByEmail(accountContext(ctx), email)
+	if err == nil {
+		return ErrUserConflict
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
+
+// notify sends a security notification to the account email.
+func (s *AccountService) notify(user *datastruct.User, subject string, message string) error {
+	return s.send(user.Email, subject, message)
+}
+
+func (s *AccountService) send(to string, subject string, message string) error {
+	_, err := s.mailer.Send(&mail_server.SendEmailRequest{
+		From:    os.Getenv("EMAIL_SENDER"),
+		To:      []string{to},
+		Subject: "Auth management - " + subject,
+		Html:    "<p> " + message + "</p>",
+	})
+	return err
+}
+
+func (s *AccountService) findAccount(ctx context.Context, actor *Claims) (*datastruct.User, error) {
+	user, err := s.userRepository.FindById(accountContext(ctx), actor.UserID)
+	if err != nil {
+		return nil, mapUserError(err)
+	}
+	return user, nil
+}
+
+// accountContext lifts the organization scope: users own their account in every organization they
+// belong to.
+func accountContext(ctx context.Context) context.Context {
+	return tenant.WithOrganization(ctx, 0)
+}
+
+// restoreUser undeletes a soft deleted user that is still within the deletion grace period.
+func restoreUser(
+	ctx context.Context,
+	userRepository repository.UserRepositoryInterface,
+	user *datastruct.User,
+) (*datastruct.User, error) {
+	gracePeriod, err := DeletionGracePeriod()
+	if err != nil {
+		return nil, err
+	}
+	if !user.DeletedAt.Valid || user.DeletedAt.Time.Add(gracePeriod).Before(time.Now()) {
+		return nil, ErrAccountNotRestorable
+	}
+
+	if err := userRepository.RestoreUserById(ctx, user.ID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAccountNotRestorable
+		}
+		return nil, err
+	}
+	return userRepository.FindById(ctx, user.ID)
+}
+
+// DeletionGracePeriod reads ACCOUNT_DELETION_GRACE_PERIOD as a Go duration such as "720h". It must not be
+// negative.
+func DeletionGracePeriod() (time.Duration, error) {
+	value := os.Getenv("ACCOUNT_DELETION_GRACE_PERIOD")
+	if value == "" {
+		return defaultDeletionGracePeriod, nil
+	}
+	gracePeriod, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if gracePeriod < 0 {
+		return 0, fmt.Errorf("ACCOUNT_DELETION_GRACE_PERIOD: must not be negative, got %s", value)
+	}
+	return gracePeriod, nil
+}
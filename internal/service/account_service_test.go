@@ -0,0 +1,491 @@
+package service_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+	"github.com/fyfirman/auth-management-go/internal/dto"
+	"github.com/fyfirman/auth-management-go/internal/repository/mocks"
+	"github.com/fyfirman/auth-management-go/internal/service"
+	servicemocks "github.com/fyfirman/auth-management-go/internal/service/mocks"
+	"github.com/fyfirman/auth-management-go/pkg"
+	"github.com/fyfirman/auth-management-go/pkg/mail_server"
+	mailmocks "github.com/fyfirman/auth-management-go/pkg/mail_server/mocks"
+	"github.com/fyfirman/auth-management-go/pkg/password"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+func TestAccountService_UpdateProfile(t *testing.T) {
+	ctx := context.TODO()
+	actor := &service.Claims{UserID: 7, OrgID: 3}
+	updatedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	username, displayName := "janedoe", "Jane Doe"
+
+	newAccount := func() *datastruct.User {
+		return &datastruct.User{ID: 7, Username: "jdoe", Email: "jdoe@example.com", UpdatedAt: updatedAt}
+	}
+
+	t.Run("updates the given fields", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepositoryInterface)
+		accountService := service.NewAccountService(mockUserRepository, nil, nil, nil, nil)
+		mockUserRepository.On("FindById", mock.Anything, uint(7)).Return(newAccount(), nil)
+		mockUserRepository.On("UpdateUserIfUnmodified", mock.Anything, mock.MatchedBy(func(user *datastruct.User) bool {
+			return user.Username == username && user.DisplayName == displayName && user.Email == "jdoe@example.com"
+		}), updatedAt).Return(nil)
+
+		res, err := accountService.UpdateProfile(ctx, actor, dto.UpdateProfileRequest{
+			Username:    &username,
+			DisplayName: &displayName,
+			UpdatedAt:   updatedAt,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, username, res.Username)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("rejects a stale updated_at", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepositoryInterface)
+		accountService := service.NewAccountService(mockUserRepository, nil, nil, nil, nil)
+		mockUserRepository.On("FindById", mock.Anything, uint(7)).Return(newAccount(), nil)
+
+		_, err := accountService.UpdateProfile(ctx, actor, dto.UpdateProfileRequest{
+			Username:  &username,
+			UpdatedAt: updatedAt.Add(-time.Minute),
+		})
+
+		assert.ErrorIs(t, err, service.ErrProfileModified)
+		mockUserRepository.AssertNotCalled(t, "UpdateUserIfUnmodified", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("detects a concurrent update", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepositoryInterface)
+		accountService := service.NewAccountService(mockUserRepository, nil, nil, nil, nil)
+		mockUserRepository.On("FindById", mock.Anything, uint(7)).Return(newAccount(), nil)
+		mockUserRepository.On("UpdateUserIfUnmodified", mock.Anything, mock.Anything, updatedAt).
+			Return(gorm.ErrRecordNotFound)
+
+		_, err := accountService.UpdateProfile(ctx, actor, dto.UpdateProfileRequest{
+			DisplayName: &displayName,
+			UpdatedAt:   updatedAt,
+		})
+
+		assert.ErrorIs(t, err, service.ErrProfileModified)
+	})
+
+	t.Run("reports a taken username", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepositoryInterface)
+		accountService := service.NewAccountService(mockUserRepository, nil, nil, nil, nil)
+		mockUserRepository.On("FindById", mock.Anything, uint(7)).Return(newAccount(), nil)
+		mockUserRepository.On("UpdateUserIfUnmodified", mock.Anything, mock.Anything, updatedAt).
+			Return(gorm.ErrDuplicatedKey)
+
+		_, err := accountService.UpdateProfile(ctx, actor, dto.UpdateProfileRequest{
+			Username:  &username,
+			UpdatedAt: updatedAt,
+		})
+
+		assert.ErrorIs(t, err, service.ErrUserConflict)
+	})
+}
+
+func TestAccountService_ChangePassword(t *testing.T) {
+	ctx := context.TODO()
+	actor := &service.Claims{SessionID: "current", UserID: 7, OrgID: 3}
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("old-password"), bcrypt.MinCost)
+
+	newAccountService := func() (*service.AccountService, *mocks.UserRepositoryInterface,
+		*mocks.SessionRepositoryInterface, *mailmocks.MailInterface) {
+		userRepository := new(mocks.UserRepositoryInterface)
+		sessionRepository := new(mocks.SessionRepositoryInterface)
+		mailer := new(mailmocks.MailInterface)
+		userRepository.On("FindById", mock.Anything, uint(7)).Return(&datastruct.User{
+			ID:           7,
+			Email:        "jdoe@example.com",
+			PasswordHash: string(hashedPassword),
+		}, nil)
+		return service.NewAccountService(userRepository, sessionRepository, nil, mailer, nil), userRepository,
+			sessionRepository, mailer
+	}
+
+	t.Run("changes the password and signs out the other sessions", func(t *testing.T) {
+		accountService, userRepository, sessionRepository, mailer := newAccountService()
+		userRepository.On("ChangePasswordById", mock.Anything, uint(7), mock.AnythingOfType("string"), 0).
+			Return(nil)
+		sessionRepository.On("RevokeSessionsByUserId", ctx, uint(7), "current").Return(nil)
+		mailer.On("Send", mock.Anything).Return(true, nil)
+
+		err := accountService.ChangePassword(ctx, actor, dto.ChangePasswordRequest{
+			CurrentPassword: "old-password",
+			NewPassword:     "new-password",
+		})
+
+		assert.NoError(t, err)
+		newHash := userRepository.Calls[1].Arguments.String(2)
+		match, err := password.Argon2id{}.Verify("new-password", newHash)
+		assert.NoError(t, err)
+		assert.True(t, match)
+		sessionRepository.AssertExpectations(t)
+		mailer.AssertExpectations(t)
+	})
+
+	t.Run("records a hash of the session kept signed in", func(t *testing.T) {
+		userRepository := new(mocks.UserRepositoryInterface)
+		sessionRepository := new(mocks.SessionRepositoryInterface)
+		mailer := new(mailmocks.MailInterface)
+		auditRecorder := new(servicemocks.AuditRecorder)
+		accountService := service.NewAccountService(userRepository, sessionRepository, nil, mailer, auditRecorder)
+		userRepository.On("FindById", mock.Anything, uint(7)).
+			Return(&datastruct.User{ID: 7, Email: "jdoe@example.com", PasswordHash: string(hashedPassword)}, nil)
+		userRepository.On("ChangePasswordById", mock.Anything, uint(7), mock.AnythingOfType("string"), 0).
+			Return(nil)
+		sessionRepository.On("RevokeSessionsByUserId", ctx, uint(7), "current").Return(nil)
+		mailer.On("Send", mock.Anything).Return(true, nil)
+		auditRecorder.On("Record", ctx, mock.MatchedBy(func(event *datastruct.AuditEvent) bool {
+			return event.Type == datastruct.AuditSessionsRevoked && event.Details["reason"] == "password_change" &&
+				event.Details["kept_session_hash"] == tokenHash("current")
+		})).Return()
+
+		err := accountService.ChangePassword(ctx, actor, dto.ChangePasswordRequest{
+			CurrentPassword: "old-password",
+			NewPassword:     "new-password",
+		})
+
+		assert.NoError(t, err)
+		auditRecorder.AssertExpectations(t)
+	})
+
+	t.Run("requires the current password", func(t *testing.T) {
+		accountService, userRepository, _, _ := newAccountService()
+
+		err := accountService.ChangePassword(ctx, actor, dto.ChangePasswordRequest{
+			CurrentPassword: "wrong-password",
+			NewPassword:     "new-password",
+		})
+
+		assert.ErrorIs(t, err, service.ErrInvalidCurrentPassword)
+		userRepository.AssertNotCalled(t, "ChangePasswordById", mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything)
+	})
+
+	t.Run("rejects reusing the current password", func(t *testing.T) {
+		accountService, userRepository, _, _ := newAccountService()
+
+		err := accountService.ChangePassword(ctx, actor, dto.ChangePasswordRequest{
+			CurrentPassword: "old-password",
+			NewPassword:     "old-password",
+		})
+
+		assert.ErrorIs(t, err, service.ErrPasswordReused)
+		userRepository.AssertNotCalled(t, "ChangePasswordById", mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything)
+	})
+
+	t.Run("rejects the last passwords and keeps the new one in the history", func(t *testing.T) {
+		t.Setenv("PASSWORD_HISTORY", "3")
+		previousHash, _ := bcrypt.GenerateFromPassword([]byte("new-password"), bcrypt.MinCost)
+		accountService, userRepository, sessionRepository, mailer := newAccountService()
+		userRepository.On("ListPasswordHistory", mock.Anything, uint(7), 3).Return([]datastruct.PasswordHistory{
+			{UserId: 7, PasswordHash: string(previousHash)},
+		}, nil)
+		userRepository.On("ChangePasswordById", mock.Anything, uint(7), mock.AnythingOfType("string"), 3).
+			Return(nil)
+		sessionRepository.On("RevokeSessionsByUserId", ctx, uint(7), "current").Return(nil)
+		mailer.On("Send", mock.Anything).Return(true, nil)
+
+		err := accountService.ChangePassword(ctx, actor, dto.ChangePasswordRequest{
+			CurrentPassword: "old-password",
+			NewPassword:     "new-password",
+		})
+
+		var fieldErrors pkg.FieldErrors
+		assert.ErrorAs(t, err, &fieldErrors)
+		assert.Equal(t, "NewPassword must differ from your last 3 passwords", fieldErrors[0].Message)
+
+		err = accountService.ChangePassword(ctx, actor, dto.ChangePasswordRequest{
+			CurrentPassword: "old-password",
+			NewPassword:     "c0rrect-horse",
+		})
+
+		assert.NoError(t, err)
+		userRepository.AssertExpectations(t)
+	})
+}
+
+func TestAccountService_RequestEmailChange(t *testing.T) {
+	ctx := context.TODO()
+	actor := &service.Claims{UserID: 7, OrgID: 3}
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+
+	newAccountService := func() (*service.AccountService, *mocks.UserRepositoryInterface,
+		*mocks.EmailChangeRepositoryInterface, *mailmocks.MailInterface) {
+		userRepository := new(mocks.UserRepositoryInterface)
+		emailChangeRepository := new(mocks.EmailChangeRepositoryInterface)
+		mailer := new(mailmocks.MailInterface)
+		userRepository.On("FindById", mock.Anything, uint(7)).Return(&datastruct.User{
+			ID:           7,
+			Email:        "old@example.com",
+			PasswordHash: string(hashedPassword),
+		}, nil)
+		return service.NewAccountService(userRepository, nil, emailChangeRepository, mailer, nil), userRepository,
+			emailChangeRepository, mailer
+	}
+
+	t.Run("mails the new address and warns the old one", func(t *testing.T) {
+		accountService, userRepository, emailChangeRepository, mailer := newAccountService()
+		userRepository.On("FindByEmail", mock.Anything, "new@example.com").Return(nil, gorm.ErrRecordNotFound)
+		emailChangeRepository.On("CreateEmailChange", ctx, mock.MatchedBy(func(change *datastruct.EmailChange) bool {
+			return change.UserId == 7 && change.NewEmail == "new@example.com" && change.Token != change.CancelToken
+		})).Return(nil)
+		mailer.On("Send", mock.Anything).Return(true, nil)
+
+		err := accountService.RequestEmailChange(ctx, actor, dto.ChangeEmailRequest{
+			NewEmail:        "new@example.com",
+			CurrentPassword: "password",
+		})
+
+		assert.NoError(t, err)
+		change := emailChangeRepository.Calls[0].Arguments.Get(1).(*datastruct.EmailChange)
+		confirmation := mailer.Calls[0].Arguments.Get(0).(*mail_server.SendEmailRequest)
+		notice := mailer.Calls[1].Arguments.Get(0).(*mail_server.SendEmailRequest)
+		assert.Equal(t, []string{"new@example.com"}, confirmation.To)
+		assert.Equal(t, change.Token, tokenHash(linkToken(confirmation.Html, "/email-change/confirm/")))
+		assert.Equal(t, []string{"old@example.com"}, notice.To)
+		assert.Equal(t, change.CancelToken, tokenHash(linkToken(notice.Html, "/email-change/cancel/")))
+		userRepository.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects an address that is already taken", func(t *testing.T) {
+		accountService, userRepository, emailChangeRepository, _ := newAccountService()
+		userRepository.On("FindByEmail", mock.Anything, "taken@example.com").Return(&datastruct.User{ID: 8}, nil)
+
+		err := accountService.RequestEmailChange(ctx, actor, dto.ChangeEmailRequest{
+			NewEmail:        "taken@example.com",
+			CurrentPassword: "password",
+		})
+
+		assert.ErrorIs(t, err, service.ErrUserConflict)
+		emailChangeRepository.AssertNotCalled(t, "CreateEmailChange", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAccountService_ConfirmEmailChange(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("swaps the email and signs out every session", func(t *testing.T) {
+		userRepository := new(mocks.UserRepositoryInterface)
+		sessionRepository := new(mocks.SessionRepositoryInterface)
+		emailChangeRepository := new(mocks.EmailChangeRepositoryInterface)
+		accountService := service.NewAccountService(userRepository, sessionRepository, emailChangeRepository, nil, nil)
+		change := &datastruct.EmailChange{UserId: 7, NewEmail: "new@example.com", ExpiredAt: time.Now().Add(time.Hour)}
+		emailChangeRepository.On("FindEmailChangeByToken", ctx, tokenHash("token")).Return(change, nil)
+		emailChangeRepository.On("ConfirmEmailChange", ctx, change).Return(nil)
+		sessionRepository.On("RevokeSessionsByUserId", ctx, uint(7), "").Return(nil)
+		userRepository.On("FindById", mock.Anything, uint(7)).
+			Return(&datastruct.User{ID: 7, Email: "new@example.com"}, nil)
+
+		res, err := accountService.ConfirmEmailChange(ctx, dto.EmailChangeTokenRequest{Token: "token"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "new@example.com", res.Email)
+		sessionRepository.AssertExpectations(t)
+	})
+
+	t.Run("refuses a change confirmed concurrently", func(t *testing.T) {
+		emailChangeRepository := new(mocks.EmailChangeRepositoryInterface)
+		accountService := service.NewAccountService(nil, nil, emailChangeRepository, nil, nil)
+		change := &datastruct.EmailChange{UserId: 7, ExpiredAt: time.Now().Add(time.Hour)}
+		emailChangeRepository.On("FindEmailChangeByToken", ctx, tokenHash("token")).Return(change, nil)
+		emailChangeRepository.On("ConfirmEmailChange", ctx, change).Return(gorm.ErrRecordNotFound)
+
+		_, err := accountService.ConfirmEmailChange(ctx, dto.EmailChangeTokenRequest{Token: "token"})
+
+		assert.ErrorIs(t, err, service.ErrEmailChangeInvalid)
+	})
+
+	t.Run("refuses cancelled changes", func(t *testing.T) {
+		emailChangeRepository := new(mocks.EmailChangeRepositoryInterface)
+		accountService := service.NewAccountService(nil, nil, emailChangeRepository, nil, nil)
+		cancelledAt := time.Now()
+		emailChangeRepository.On("FindEmailChangeByToken", ctx, tokenHash("token")).Return(&datastruct.EmailChange{
+			UserId:      7,
+			ExpiredAt:   time.Now().Add(time.Hour),
+			CancelledAt: &cancelledAt,
+		}, nil)
+
+		_, err := accountService.ConfirmEmailChange(ctx, dto.EmailChangeTokenRequest{Token: "token"})
+
+		assert.ErrorIs(t, err, service.ErrEmailChangeInvalid)
+		emailChangeRepository.AssertNotCalled(t, "ConfirmEmailChange", mock.Anything, mock.Anything)
+	})
+
+	t.Run("reports an address taken in the meantime", func(t *testing.T) {
+		emailChangeRepository := new(mocks.EmailChangeRepositoryInterface)
+		accountService := service.NewAccountService(nil, nil, emailChangeRepository, nil, nil)
+		change := &datastruct.EmailChange{UserId: 7, ExpiredAt: time.Now().Add(time.Hour)}
+		emailChangeRepository.On("FindEmailChangeByToken", ctx, tokenHash("token")).Return(change, nil)
+		emailChangeRepository.On("ConfirmEmailChange", ctx, change).Return(gorm.ErrDuplicatedKey)
+
+		_, err := accountService.ConfirmEmailChange(ctx, dto.EmailChangeTokenRequest{Token: "token"})
+
+		assert.ErrorIs(t, err, service.ErrUserConflict)
+	})
+}
+
+func TestAccountService_DeleteAccount(t *testing.T) {
+	ctx := context.TODO()
+	actor := &service.Claims{UserID: 7, OrgID: 3}
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+
+	userRepository := new(mocks.UserRepositoryInterface)
+	mailer := new(mailmocks.MailInterface)
+	accountService := service.NewAccountService(userRepository, nil, nil, mailer, nil)
+	userRepository.On("FindById", mock.Anything, uint(7)).
+		Return(&datastruct.User{ID: 7, Email: "jdoe@example.com", PasswordHash: string(hashedPassword)}, nil)
+	userRepository.On("DeleteUserById", mock.Anything, uint(7)).Return(nil)
+	mailer.On("Send", mock.Anything).Return(true, nil)
+
+	err := accountService.DeleteAccount(ctx, actor, dto.DeleteAccountRequest{CurrentPassword: "password"})
+
+	assert.NoError(t, err)
+	userRepository.AssertExpectations(t)
+	mailer.AssertExpectations(t)
+}
+
+func TestAccountService_RestoreAccount(t *testing.T) {
+	t.Setenv("ACCOUNT_DELETION_GRACE_PERIOD", "720h")
+	ctx := context.TODO()
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	req := dto.RestoreAccountRequest{Email: "jdoe@example.com", Password: "password"}
+
+	deletedUser := func(deletedAt time.Time) *datastruct.User {
+		return &datastruct.User{
+			ID:           7,
+			Email:        "jdoe@example.com",
+			PasswordHash: string(hashedPassword),
+			DeletedAt:    gorm.DeletedAt{Time: deletedAt, Valid: true},
+		}
+	}
+
+	t.Run("restores within the grace period", func(t *testing.T) {
+		userRepository := new(mocks.UserRepositoryInterface)
+		accountService := service.NewAccountService(userRepository, nil, nil, nil, nil)
+		userRepository.On("FindDeletedUserByEmail", mock.Anything, req.Email).
+			Return(deletedUser(time.Now().Add(-24*time.Hour)), nil)
+		userRepository.On("RestoreUserById", mock.Anything, uint(7)).Return(nil)
+		userRepository.On("FindById", mock.Anything, uint(7)).
+			Return(&datastruct.User{ID: 7, Email: "jdoe@example.com"}, nil)
+
+		res, err := accountService.RestoreAccount(ctx, req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), res.ID)
+	})
+
+	t.Run("refuses once the grace period is over", func(t *testing.T) {
+		userRepository := new(mocks.UserRepositoryInterface)
+		accountService := service.NewAccountService(userRepository, nil, nil, nil, nil)
+		userRepository.On("FindDeletedUserByEmail", mock.Anything, req.Email).
+			Return(deletedUser(time.Now().Add(-31*24*time.Hour)), nil)
+
+		_, err := accountService.RestoreAccount(ctx, req)
+
+		assert.ErrorIs(t, err, service.ErrAccountNotRestorable)
+		userRepository.AssertNotCalled(t, "RestoreUserById", mock.Anything, mock.Anything)
+	})
+
+	t.Run("counts a wrong password towards the lockout", func(t *testing.T) {
+		userRepository := new(mocks.UserRepositoryInterface)
+		accountService := service.NewAccountService(userRepository, nil, nil, nil, nil)
+		userRepository.On("FindDeletedUserByEmail", mock.Anything, req.Email).
+			Return(deletedUser(time.Now().Add(-24*time.Hour)), nil)
+		userRepository.On("RecordFailedLogin", mock.Anything, uint(7)).Return(1, nil)
+
+		_, err := accountService.RestoreAccount(ctx, dto.RestoreAccountRequest{Email: req.Email, Password: "wrong"})
+
+		assert.ErrorIs(t, err, service.ErrAccountNotRestorable)
+		userRepository.AssertExpectations(t)
+		userRepository.AssertNotCalled(t, "RestoreUserById", mock.Anything, mock.Anything)
+	})
+
+	t.Run("refuses a locked account", func(t *testing.T) {
+		userRepository := new(mocks.UserRepositoryInterface)
+		accountService := service.NewAccountService(userRepository, nil, nil, nil, nil)
+		user := deletedUser(time.Now().Add(-24 * time.Hour))
+		lockedUntil := time.Now().Add(time.Hour)
+		user.LockedUntil = &lockedUntil
+		userRepository.On("FindDeletedUserByEmail", mock.Anything, req.Email).Return(user, nil)
+
+		_, err := accountService.RestoreAccount(ctx, req)
+
+		assert.ErrorIs(t, err, service.ErrAccountNotRestorable)
+		userRepository.AssertNotCalled(t, "RestoreUserById", mock.Anything, mock.Anything)
+	})
+
+	t.Run("does not reveal unknown accounts", func(t *testing.T) {
+		userRepository := new(mocks.UserRepositoryInterface)
+		accountService := service.NewAccountService(userRepository, nil, nil, nil, nil)
+		userRepository.On("FindDeletedUserByEmail", mock.Anything, req.Email).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := accountService.RestoreAccount(ctx, req)
+
+		assert.ErrorIs(t, err, service.ErrAccountNotRestorable)
+	})
+}
+
+func TestAccountService_PurgeDeletedAccounts(t *testing.T) {
+	t.Setenv("ACCOUNT_DELETION_GRACE_PERIOD", "48h")
+
+	userRepository := new(mocks.UserRepositoryInterface)
+	accountService := service.NewAccountService(userRepository, nil, nil, nil, nil)
+	userRepository.On("PurgeDeletedUsers", mock.Anything, mock.MatchedBy(func(deletedBefore time.Time) bool {
+		return time.Since(deletedBefore).Round(time.Hour) == 48*time.Hour
+	})).Return(int64(2), nil)
+
+	purged, err := accountService.PurgeDeletedAccounts(context.TODO())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), purged)
+}
+
+func TestDeletionGracePeriod(t *testing.T) {
+	t.Run("defaults to 30 days", func(t *testing.T) {
+		t.Setenv("ACCOUNT_DELETION_GRACE_PERIOD", "")
+
+		gracePeriod, err := service.DeletionGracePeriod()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 30*24*time.Hour, gracePeriod)
+	})
+
+	t.Run("rejects negative periods", func(t *testing.T) {
+		t.Setenv("ACCOUNT_DELETION_GRACE_PERIOD", "-1h")
+
+		_, err := service.DeletionGracePeriod()
+
+		assert.Error(t, err)
+	})
+}
+
+// tokenHash is the hash a token is stored under.
+func tokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// linkToken returns the token following path in an email body.
+func linkToken(body string, path string) string {
+	_, token, _ := strings.Cut(body, path)
+	if end := strings.IndexAny(token, " <"); end >= 0 {
+		token = token[:end]
+	}
+	return token
+}
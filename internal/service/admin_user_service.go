@@ -0,0 +1,483 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+	"github.com/fyfirman/auth-management-go/internal/dto"
+	"github.com/fyfirman/auth-management-go/internal/repository"
+	"github.com/fyfirman/auth-management-go/internal/tenant"
+	"github.com/fyfirman/auth-management-go/pkg/password"
+	"gorm.io/gorm"
+)
+
+type AdminUserServiceInterface interface {
+	ListUsers(ctx context.Context, req dto.ListUsersRequest) (*dto.ListUsersResponse, error)
+	GetUser(ctx context.Context, id uint) (*dto.UserResponse, error)
+	UpdateUser(ctx context.Context, actor *Claims, id uint, req dto.UpdateUserRequest) (*dto.UserResponse, error)
+	SetUserDisabled(ctx context.Context, actor *Claims, id uint, disabled bool) (*dto.UserResponse, error)
+	DeleteUser(ctx context.Context, actor *Claims, id uint) error
+	RestoreUser(ctx context.Context, actor *Claims, id uint) (*dto.UserResponse, error)
+	UnlockUser(ctx context.Context, actor *Claims, id uint) (*dto.UserResponse, error)
+	ImportUsers(ctx context.Context, req dto.ImportUsersRequest) (*dto.ImportUsersResponse, error)
+	ChangeUserRole(ctx context.Context, actor *Claims, id uint, req dto.ChangeRoleRequest) (*dto.UserResponse, error)
+}
+
+type AdminUserService struct {
+	userRepository         repository.UserRepositoryInterface
+	roleRepository         repository.RoleRepositoryInterface
+	organizationRepository repository.OrganizationRepositoryInterface
+	sessionRepository      repository.SessionRepositoryInterface
+	auditRecorder          AuditRecorder
+}
+
+func NewAdminUserService(
+	userRepository repository.UserRepositoryInterface,
+	roleRepository repository.RoleRepositoryInterface,
+	organizationRepository repository.OrganizationRepositoryInterface,
+	sessionRepository repository.SessionRepositoryInterface,
+	auditRecorder AuditRecorder,
+) *AdminUserService {
+	return &AdminUserService{
+		userRepository:         userRepository,
+		roleRepository:         roleRepository,
+		organizationRepository: organizationRepository,
+		sessionRepository:      sessionRepository,
+		auditRecorder:          auditRecorder,
+	}
+}
+
+func (s *AdminUserService) ListUsers(ctx context.Context, req dto.ListUsersRequest) (*dto.ListUsersResponse, error) {
+	users, total, err := s.userRepository.ListUsers(ctx, repository.UserFilter{
+		Role:          req.Role,
+		Email:         req.Email,
+		CreatedAfter:  req.CreatedAfter,
+		CreatedBefore: req.CreatedBefore,
+		SortBy:        req.Sort,
+		SortDesc:      req.Order == "desc",
+		Limit:         req.PageSize,
+		Offset:        (req.Page - 1) * req.PageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]dto.UserResponse, 0, len(users))
+	for i := range users {
+		data = append(data, *dto.NewUserResponse(&users[i]))
+	}
+
+	return &dto.ListUsersResponse{
+		Data:     data,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		Total:    total,
+	}, nil
+}
+
+func (s *AdminUserService) GetUser(ctx context.Context, id uint) (*dto.UserResponse, error) {
+	user, err := s.userRepository.FindById(ctx, id)
+	if err != nil {
+		return nil, mapUserError(err)
+	}
+	return dto.NewUserResponse(user), nil
+}
+
+func (s *AdminUserService) UpdateUser(
+	ctx context.Context,
+	actor *Claims,
+	id uint,
+	req dto.UpdateUserRequest,
+) (*dto.UserResponse, error) {
+	user, _, err := s.authorizeTarget(ctx, actor, id, s.userRepository.FindById)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireAccountAdmin(ctx, actor, user); err != nil {
+		return nil, err
+	}
+
+	if req.Username != nil {
+		user.Username = *req.Username
+	}
+	if req.Email != nil {
+		user.Email = *req.Email
+	}
+
+	if err := s.userRepository.UpdateUser(ctx, user); err != nil {
+		return nil, mapUserError(err)
+	}
+	return dto.NewUserResponse(user), nil
+}
+
+// SetUserDisabled disables or enables the user. Disabling signs them out of every session.
+func (s *AdminUserService) SetUserDisabled(
+	ctx context.Context,
+	actor *Claims,
+	id uint,
+	disabled bool,
+) (*dto.UserResponse, error) {
+	user, _, err := s.authorizeTarget(ctx, actor, id, s.userRepository.FindById)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireAccountAdmin(ctx, actor, user); err != nil {
+		return nil, err
+	}
+
+	user.Disabled = disabled
+	if err := s.userRepository.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+	if disabled {
+		if err := s.sessionRepository.RevokeSessionsByUserId(ctx, user.ID, ""); err != nil {
+			return nil, err
+		}
+		auditSessionsRevoked(ctx, s.auditRecorder, auditID(actor.UserID), user.ID, "account_disabled", "")
+	}
+	return dto.NewUserResponse(user), nil
+}
+
+// DeleteUser soft deletes the user. It can be restored with RestoreUser during the deletion grace period.
+// Users whose home is another organization are only removed from the actor's one, and signed out so their
+// tokens for it stop working.
+func (s *AdminUserService) DeleteUser(ctx context.Context, actor *Claims, id uint) error {
+	user, _, err := s.authorizeTarget(ctx, actor, id, s.userRepository.FindById)
+	if err != nil {
+		return err
+	}
+	err = s.requireAccountAdmin(ctx, actor, user)
+	if errors.Is(err, ErrNotHomeOrganization) {
+		if err := s.organizationRepository.RemoveMember(ctx, actor.OrgID, user.ID); err != nil {
+			return mapUserError(err)
+		}
+		if err := s.sessionRepository.RevokeSessionsByUserId(ctx, user.ID, ""); err != nil {
+			return err
+		}
+		auditSessionsRevoked(ctx, s.auditRecorder, auditID(actor.UserID), user.ID, "membership_removed", "")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.userRepository.DeleteUserById(ctx, id); err != nil {
+		return mapUserError(err)
+	}
+	auditSessionsRevoked(ctx, s.auditRecorder, auditID(actor.UserID), user.ID, "account_deletion", "")
+	return nil
+}
+
+func (s *AdminUserService) RestoreUser(ctx context.Context, actor *Claims, id uint) (*dto.UserResponse, error) {
+	user, _, err := s.authorizeTarget(ctx, actor, id, s.userRepository.FindDeletedUserById)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireAccountAdmin(ctx, actor, user); err != nil {
+		return nil, err
+	}
+
+	restored, err := restoreUser(ctx, s.userRepository, user)
+	if err != nil {
+		return nil, mapUserError(err)
+	}
+	return dto.NewUserResponse(restored), nil
+}
+
+// UnlockUser lifts a login lockout and clears the failed logins counted towards the next one.
+func (s *AdminUserService) UnlockUser(ctx context.Context, actor *Claims, id uint) (*dto.UserResponse, error) {
+	target, _, err := s.authorizeTarget(ctx, actor, id, s.userRepository.FindById)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireAccountAdmin(ctx, actor, target); err != nil {
+		return nil, err
+	}
+	if err := s.userRepository.ResetFailedLogins(ctx, id); err != nil {
+		return nil, mapUserError(err)
+	}
+
+	user, err := s.userRepository.FindById(ctx, id)
+	if err != nil {
+		return nil, mapUserError(err)
+	}
+	return dto.NewUserResponse(user), nil
+}
+
+// ImportUsers creates general users in the organization of ctx with the password hashes of another system,
+// which are replaced by a native hash on their first login. Users that cannot be imported are reported
+// without stopping the others.
+func (s *AdminUserService) ImportUsers(
+	ctx context.Context,
+	req dto.ImportUsersRequest,
+) (*dto.ImportUsersResponse, error) {
+	organizationID, ok := tenant.OrganizationFromContext(ctx)
+	if !ok {
+		return nil, ErrNoOrganization
+	}
+
+	resp := &dto.ImportUsersResponse{Imported: []dto.ImportedUser{}, Failed: []dto.ImportUserFailure{}}
+	for i, imported := range req.Users {
+		user, err := s.importUser(ctx, organizationID, imported)
+		if err != nil {
+			resp.Failed = append(resp.Failed, dto.ImportUserFailure{Index: i, Email: imported.Email, Error: err.Error()})
+			continue
+		}
+		resp.Imported = append(resp.Imported, dto.ImportedUser{Index: i, ID: int64(user.ID)})
+	}
+	return resp, nil
+}
+
+func (s *AdminUserService) importUser(
+	ctx context.Context,
+	organizationID uint,
+	imported dto.ImportUser,
+) (*datastruct.User, error) {
+	passwordHash, err := importPasswordHash(password.ForeignHash{
+		Format:       imported.PasswordHashFormat,
+		Hash:         imported.PasswordHash,
+		Salt:         imported.PasswordSalt,
+		SaltPosition: imported.PasswordSaltPosition,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	user := &datastruct.User{
+		OrganizationId: organizationID,
+		Username:       imported.Username,
+		Email:          imported.Email,
+		Role:           datastruct.GeneralUser.String(),
+		PasswordHash:   passwordHash,
+	}
+	if err := s.userRepository.CreateUser(ctx, user); err != nil {
+		return nil, mapUserError(err)
+	}
+	return user, nil
+}
+
+// ChangeUserRole changes the role a user holds in the actor's organization and signs them out, so no token
+// keeps the permissions of the old role. Actors can grant a role at or below their own, to users they may
+// manage, see authorizeTarget.
+func (s *AdminUserService) ChangeUserRole(
+	ctx context.Context,
+	actor *Claims,
+	id uint,
+	req dto.ChangeRoleRequest,
+) (*dto.UserResponse, error) {
+	user, actorRole, err := s.authorizeTarget(ctx, actor, id, s.userRepository.FindById)
+	if errors.Is(err, ErrRoleForbidden) {
+		s.auditRoleChange(ctx, actor, id, datastruct.AuditFailure, "", req.Role)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	currentRole, err := s.roleRepository.FindRoleByName(ctx, user.OrganizationRole)
+	if err != nil {
+		return nil, mapRoleError(err)
+	}
+	newRole, err := s.roleRepository.FindRoleByName(ctx, req.Role)
+	if err != nil {
+		return nil, mapRoleError(err)
+	}
+	if !canGrant(actorRole, newRole) {
+		s.auditRoleChange(ctx, actor, user.ID, datastruct.AuditFailure, currentRole.Name, newRole.Name)
+		return nil, ErrRoleForbidden
+	}
+
+	if currentRole.Name == newRole.Name {
+		return dto.NewUserResponse(user), nil
+	}
+
+	err = s.organizationRepository.UpdateMemberRole(ctx, &datastruct.OrganizationMember{
+		OrganizationId: actor.OrgID,
+		UserId:         user.ID,
+		Role:           newRole.Name,
+	}, &datastruct.RoleChange{
+		OrganizationId: actor.OrgID,
+		ActorId:        actor.UserID,
+		UserId:         user.ID,
+		OldRole:        currentRole.Name,
+		NewRole:        newRole.Name,
+	})
+	if err != nil {
+		return nil, mapUserError(err)
+	}
+
+	s.auditRoleChange(ctx, actor, user.ID, datastruct.AuditSuccess, currentRole.Name, newRole.Name)
+	err = revokeGrantSessions(ctx, s.sessionRepository, s.auditRecorder, auditID(actor.UserID), "role_changed", user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	user.OrganizationRole = newRole.Name
+	return dto.NewUserResponse(user), nil
+}
+
+// authorizeTarget finds the user id of the actor's organization with find, after checking the actor may
+// manage them: never themselves, and only users whose role does not outrank the actor's, platform
+// superadmins outranking every organization role. The actor's role is read from the database so a demoted
+// admin holding an old token cannot use it, and returned for further checks.
+func (s *AdminUserService) authorizeTarget(
+	ctx context.Context,
+	actor *Claims,
+	id uint,
+	find func(ctx context.Context, id uint) (*datastruct.User, error),
+) (*datastruct.User, *datastruct.Role, error) {
+	if actor.UserID == id {
+		return nil, nil, ErrRoleForbidden
+	}
+	if actor.OrgID == 0 {
+		return nil, nil, ErrNoOrganization
+	}
+
+	actorRoleName, err := organizationRoleOf(ctx, s.userRepository, s.organizationRepository, actor)
+	if err != nil {
+		return nil, nil, err
+	}
+	user, err := find(tenant.WithOrganization(ctx, actor.OrgID), id)
+	if err != nil {
+		return nil, nil, mapUserError(err)
+	}
+
+	actorRole, err := s.roleRepository.FindRoleByName(ctx, actorRoleName)
+	if err != nil {
+		return nil, nil, mapRoleError(err)
+	}
+	targetRoleName := user.OrganizationRole
+	if user.Role == datastruct.SuperAdmin.String() {
+		targetRoleName = user.Role
+	}
+	targetRole, err := s.roleRepository.FindRoleByName(ctx, targetRoleName)
+	if err != nil {
+		return nil, nil, mapRoleError(err)
+	}
+	if !canGrant(actorRole, targetRole) {
+		return nil, nil, ErrRoleForbidden
+	}
+	return user, actorRole, nil
+}
+
+// requireAccountAdmin fails with ErrNotHomeOrganization unless the actor may change the account of user
+// itself, which is shared by every organization it belongs to: only the admins of its home organization
+// and platform superadmins can. Others are limited to its membership of their organization.
+func (s *AdminUserService) requireAccountAdmin(ctx context.Context, actor *Claims, user *datastruct.User) error {
+	if user.OrganizationId == actor.OrgID {
+		return nil
+	}
+	actorUser, err := s.userRepository.FindById(tenant.WithOrganization(ctx, 0), actor.UserID)
+	if err != nil {
+		return mapUserError(err)
+	}
+	if actorUser.Role != datastruct.SuperAdmin.String() {
+		return ErrNotHomeOrganization
+	}
+	return nil
+}
+
+// auditRoleChange records an attempt of actor to change the role of the user targetID in their organization.
+func (s *AdminUserService) auditRoleChange(
+	ctx context.Context,
+	actor *Claims,
+	targetID uint,
+	outcome string,
+	oldRole string,
+	newRole string,
+) {
+	recordAudit(ctx, s.auditRecorder, &datastruct.AuditEvent{
+		OrganizationId: auditID(actor.OrgID),
+		Type:           datastruct.AuditRoleChanged,
+		Outcome:        outcome,
+		ActorId:        auditID(actor.UserID),
+		TargetId:       auditID(targetID),
+		Details:        map[string]string{"old_role": oldRole, "new_role": newRole},
+	})
+}
+
+// organizationRoleOf returns the role the actor holds in their active organization.
+// Platform superadmins are superadmins in every organization.
+func organizationRoleOf(
+	ctx context.Context,
+	userRepository repository.UserRepositoryInterface,
+	organizationRepository repository.OrganizationRepositoryInterface,
+	actor *Claims,
+) (string, error) {
+	user, err := userRepository.FindById(tenant.WithOrganization(ctx, 0), actor.UserID)
+	if err != nil {
+		return "", mapUserError(err)
+	}
+	if user.Role == datastruct.SuperAdmin.String() {
+		return user.Role, nil
+	}
+
+	member, err := organizationRepository.FindMember(ctx, actor.OrgID, actor.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", ErrRoleForbidden
+	}
+	if err != nil {
+		return "", err
+	}
+	return member.Role, nil
+}
+
+// authorizeGrant checks that the role the actor currently holds in their organization can grant each of roles.
+func authorizeGrant(
+	ctx context.Context,
+	userRepository repository.UserRepositoryInterface,
+	organizationRepository repository.OrganizationRepositoryInterface,
+	roleRepository repository.RoleRepositoryInterface,
+	actor *Claims,
+	roles ...*datastruct.Role,
+) error {
+	actorRoleName, err := organizationRoleOf(ctx, userRepository, organizationRepository, actor)
+	if err != nil {
+		return err
+	}
+	actorRole, err := roleRepository.FindRoleByName(ctx, actorRoleName)
+	if err != nil {
+		return mapRoleError(err)
+	}
+	for _, role := range roles {
+		if !canGrant(actorRole, role) {
+			return ErrRoleForbidden
+		}
+	}
+	return nil
+}
+
+// canGrant applies the SuperAdmin > Admin > GeneralUser hierarchy between built-in roles.
+// Custom roles can be handled by a superadmin or by actors holding every permission the role carries.
+func canGrant(actor *datastruct.Role, role *datastruct.Role) bool {
+	actorRole, actorBuiltIn := datastruct.ParseUserRole(actor.Name)
+	targetRole, targetBuiltIn := datastruct.ParseUserRole(role.Name)
+	if actorBuiltIn && targetBuiltIn {
+		return actorRole.AtLeast(targetRole)
+	}
+	if actorBuiltIn && actorRole == datastruct.SuperAdmin {
+		return true
+	}
+
+	granted := make(map[string]bool, len(actor.Permissions))
+	for _, permission := range actor.Permissions {
+		granted[permission.Name] = true
+	}
+	for _, permission := range role.Permissions {
+		if !granted[permission.Name] {
+			return false
+		}
+	}
+	return true
+}
+
+// mapUserError converts repository errors into the service level errors handlers know how to present.
+func mapUserError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrUserNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return ErrUserConflict
+	default:
+		return err
+	}
+}
@@ -0,0 +1,390 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+	"github.com/fyfirman/auth-management-go/internal/dto"
+	"github.com/fyfirman/auth-management-go/internal/repository"
+	"github.com/fyfirman/auth-management-go/internal/repository/mocks"
+	"github.com/fyfirman/auth-management-go/internal/service"
+	"github.com/fyfirman/auth-management-go/internal/tenant"
+	"github.com/fyfirman/auth-management-go/pkg/password"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestAdminUserService_ListUsers(t *testing.T) {
+	userRepository := new(mocks.UserRepositoryInterface)
+	roleRepository := new(mocks.RoleRepositoryInterface)
+	adminUserService := service.NewAdminUserService(userRepository, roleRepository,
+		new(mocks.OrganizationRepositoryInterface), nil, nil)
+
+	ctx := context.TODO()
+	req := dto.ListUsersRequest{Page: 3, PageSize: 10, Role: "admin", Sort: "email", Order: "desc"}
+	expectedFilter := repository.UserFilter{Role: "admin", SortBy: "email", SortDesc: true, Limit: 10, Offset: 20}
+	users := []datastruct.User{
+		{ID: 1, Username: "alice", Email: "alice@example.com", Role: "admin"},
+		{ID: 2, Username: "bob", Email: "bob@example.com", Role: "admin"},
+	}
+
+	userRepository.Mock.On("ListUsers", ctx, expectedFilter).Return(users, int64(22), nil)
+
+	res, err := adminUserService.ListUsers(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(22), res.Total)
+	assert.Equal(t, 3, res.Page)
+	assert.Len(t, res.Data, 2)
+	assert.Equal(t, "bob@example.com", res.Data[1].Email)
+}
+
+func TestAdminUserService_GetUser_NotFound(t *testing.T) {
+	userRepository := new(mocks.UserRepositoryInterface)
+	roleRepository := new(mocks.RoleRepositoryInterface)
+	adminUserService := service.NewAdminUserService(userRepository, roleRepository,
+		new(mocks.OrganizationRepositoryInterface), nil, nil)
+
+	ctx := context.TODO()
+	userRepository.Mock.On("FindById", ctx, uint(7)).Return(nil, gorm.ErrRecordNotFound)
+
+	res, err := adminUserService.GetUser(ctx, 7)
+
+	assert.Nil(t, res)
+	assert.ErrorIs(t, err, service.ErrUserNotFound)
+}
+
+// newAdminTestService returns a service where the user 1 is an admin of the organization 3, the actor of
+// adminActor.
+func newAdminTestService() (*service.AdminUserService, *mocks.UserRepositoryInterface,
+	*mocks.OrganizationRepositoryInterface) {
+	userRepository := new(mocks.UserRepositoryInterface)
+	roleRepository := new(mocks.RoleRepositoryInterface)
+	organizationRepository := new(mocks.OrganizationRepositoryInterface)
+
+	userRepository.Mock.On("FindById", mock.Anything, uint(1)).
+		Return(&datastruct.User{ID: 1, OrganizationId: 3, Role: datastruct.GeneralUser.String()}, nil)
+	organizationRepository.Mock.On("FindMember", mock.Anything, uint(3), uint(1)).
+		Return(&datastruct.OrganizationMember{OrganizationId: 3, UserId: 1, Role: "admin"}, nil)
+	for _, role := range testRoles {
+		roleRepository.Mock.On("FindRoleByName", mock.Anything, role.Name).Return(role, nil)
+	}
+	return service.NewAdminUserService(userRepository, roleRepository, organizationRepository,
+		revokingSessionRepository(), nil), userRepository, organizationRepository
+}
+
+var adminActor = &service.Claims{UserID: 1, OrgID: 3}
+
+// revokingSessionRepository accepts revoking the sessions of any user.
+func revokingSessionRepository() *mocks.SessionRepositoryInterface {
+	sessionRepository := new(mocks.SessionRepositoryInterface)
+	sessionRepository.Mock.On("RevokeSessionsByUserId", mock.Anything, mock.Anything, "").Return(nil)
+	return sessionRepository
+}
+
+func TestAdminUserService_UpdateUser(t *testing.T) {
+	ctx := context.TODO()
+	newEmail := "new@example.com"
+
+	t.Run("success", func(t *testing.T) {
+		adminUserService, userRepository, _ := newAdminTestService()
+
+		user := &datastruct.User{ID: 2, OrganizationId: 3, Username: "alice", Email: "alice@example.com",
+			OrganizationRole: "general-user"}
+		userRepository.Mock.On("FindById", mock.Anything, uint(2)).Return(user, nil)
+		userRepository.Mock.On("UpdateUser", ctx, user).Return(nil)
+
+		res, err := adminUserService.UpdateUser(ctx, adminActor, 2, dto.UpdateUserRequest{Email: &newEmail})
+
+		assert.NoError(t, err)
+		assert.Equal(t, newEmail, res.Email)
+		assert.Equal(t, "alice", res.Username)
+	})
+
+	t.Run("duplicated email", func(t *testing.T) {
+		adminUserService, userRepository, _ := newAdminTestService()
+
+		user := &datastruct.User{ID: 2, OrganizationId: 3, Username: "alice", Email: "alice@example.com",
+			OrganizationRole: "general-user"}
+		userRepository.Mock.On("FindById", mock.Anything, uint(2)).Return(user, nil)
+		userRepository.Mock.On("UpdateUser", ctx, user).Return(gorm.ErrDuplicatedKey)
+
+		res, err := adminUserService.UpdateUser(ctx, adminActor, 2, dto.UpdateUserRequest{Email: &newEmail})
+
+		assert.Nil(t, res)
+		assert.ErrorIs(t, err, service.ErrUserConflict)
+	})
+}
+
+func TestAdminUserService_SetUserDisabled(t *testing.T) {
+	adminUserService, userRepository, _ := newAdminTestService()
+
+	ctx := context.TODO()
+	user := &datastruct.User{ID: 2, OrganizationId: 3, OrganizationRole: "general-user"}
+	userRepository.Mock.On("FindById", mock.Anything, uint(2)).Return(user, nil)
+	userRepository.Mock.On("UpdateUser", ctx, mock.MatchedBy(func(u *datastruct.User) bool {
+		return u.Disabled
+	})).Return(nil)
+
+	res, err := adminUserService.SetUserDisabled(ctx, adminActor, 2, true)
+
+	assert.NoError(t, err)
+	assert.True(t, res.Disabled)
+	userRepository.AssertExpectations(t)
+}
+
+func TestAdminUserService_DeleteUser(t *testing.T) {
+	adminUserService, userRepository, _ := newAdminTestService()
+
+	ctx := context.TODO()
+	userRepository.Mock.On("FindById", mock.Anything, uint(2)).
+		Return(&datastruct.User{ID: 2, OrganizationId: 3, OrganizationRole: "general-user"}, nil)
+	userRepository.Mock.On("FindById", mock.Anything, uint(4)).Return(nil, gorm.ErrRecordNotFound)
+	userRepository.Mock.On("DeleteUserById", ctx, uint(2)).Return(nil)
+
+	assert.NoError(t, adminUserService.DeleteUser(ctx, adminActor, 2))
+	assert.ErrorIs(t, adminUserService.DeleteUser(ctx, adminActor, 4), service.ErrUserNotFound)
+}
+
+func TestAdminUserService_OtherHomeOrganization(t *testing.T) {
+	ctx := context.TODO()
+	adminUserService, userRepository, organizationRepository := newAdminTestService()
+	user := &datastruct.User{ID: 2, OrganizationId: 4, OrganizationRole: "general-user"}
+	userRepository.Mock.On("FindById", mock.Anything, uint(2)).Return(user, nil)
+	organizationRepository.Mock.On("RemoveMember", ctx, uint(3), uint(2)).Return(nil)
+
+	_, err := adminUserService.UpdateUser(ctx, adminActor, 2, dto.UpdateUserRequest{})
+	assert.ErrorIs(t, err, service.ErrNotHomeOrganization)
+	_, err = adminUserService.SetUserDisabled(ctx, adminActor, 2, true)
+	assert.ErrorIs(t, err, service.ErrNotHomeOrganization)
+
+	// Deleting only removes the membership
+	assert.NoError(t, adminUserService.DeleteUser(ctx, adminActor, 2))
+	organizationRepository.AssertCalled(t, "RemoveMember", ctx, uint(3), uint(2))
+	userRepository.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything)
+	userRepository.AssertNotCalled(t, "DeleteUserById", mock.Anything, mock.Anything)
+}
+
+func TestAdminUserService_AuthorizeTarget(t *testing.T) {
+	ctx := context.TODO()
+
+	cases := []struct {
+		name string
+		id   uint
+		user *datastruct.User
+	}{
+		{"own account", 1, nil},
+		{"organization superadmin", 2, &datastruct.User{ID: 2, OrganizationId: 3, OrganizationRole: "superadmin"}},
+		{"platform superadmin", 2, &datastruct.User{ID: 2, OrganizationId: 3,
+			Role: datastruct.SuperAdmin.String(), OrganizationRole: "general-user"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			adminUserService, userRepository, _ := newAdminTestService()
+			if c.user != nil {
+				userRepository.Mock.On("FindById", mock.Anything, c.id).Return(c.user, nil)
+			}
+
+			_, err := adminUserService.UpdateUser(ctx, adminActor, c.id, dto.UpdateUserRequest{})
+			assert.ErrorIs(t, err, service.ErrRoleForbidden)
+			_, err = adminUserService.SetUserDisabled(ctx, adminActor, c.id, true)
+			assert.ErrorIs(t, err, service.ErrRoleForbidden)
+			assert.ErrorIs(t, adminUserService.DeleteUser(ctx, adminActor, c.id), service.ErrRoleForbidden)
+			_, err = adminUserService.UnlockUser(ctx, adminActor, c.id)
+			assert.ErrorIs(t, err, service.ErrRoleForbidden)
+
+			userRepository.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything)
+			userRepository.AssertNotCalled(t, "DeleteUserById", mock.Anything, mock.Anything)
+			userRepository.AssertNotCalled(t, "ResetFailedLogins", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+var testRoles = []*datastruct.Role{
+	{Name: "superadmin", BuiltIn: true, Permissions: []datastruct.Permission{
+		{Name: datastruct.PermissionUsersRead}, {Name: datastruct.PermissionUsersWrite}, {Name: datastruct.PermissionRolesWrite},
+	}},
+	{Name: "admin", BuiltIn: true, Permissions: []datastruct.Permission{
+		{Name: datastruct.PermissionUsersRead}, {Name: datastruct.PermissionUsersWrite},
+	}},
+	{Name: "general-user", BuiltIn: true},
+	{Name: "support", Permissions: []datastruct.Permission{{Name: datastruct.PermissionUsersRead}}},
+	{Name: "auditor", Permissions: []datastruct.Permission{{Name: datastruct.PermissionRolesWrite}}},
+}
+
+func TestAdminUserService_ChangeUserRole(t *testing.T) {
+	ctx := context.TODO()
+
+	cases := []struct {
+		name        string
+		actorRole   string
+		targetRole  string
+		newRole     string
+		expectedErr error
+	}{
+		{"superadmin promotes to superadmin", "superadmin", "general-user", "superadmin", nil},
+		{"admin promotes to admin", "admin", "general-user", "admin", nil},
+		{"admin cannot grant superadmin", "admin", "general-user", "superadmin", service.ErrRoleForbidden},
+		{"admin cannot demote superadmin", "admin", "superadmin", "general-user", service.ErrRoleForbidden},
+		{"general user cannot grant anything", "general-user", "general-user", "admin", service.ErrRoleForbidden},
+		{"admin grants custom role within own permissions", "admin", "general-user", "support", nil},
+		{"admin cannot grant custom role beyond own permissions", "admin", "general-user", "auditor", service.ErrRoleForbidden},
+		{"superadmin grants any custom role", "superadmin", "support", "auditor", nil},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			userRepository := new(mocks.UserRepositoryInterface)
+			roleRepository := new(mocks.RoleRepositoryInterface)
+			organizationRepository := new(mocks.OrganizationRepositoryInterface)
+			adminUserService := service.NewAdminUserService(userRepository, roleRepository, organizationRepository,
+				revokingSessionRepository(), nil)
+
+			actor := &service.Claims{UserID: 1, OrgID: 3}
+			userRepository.Mock.On("FindById", mock.Anything, uint(1)).
+				Return(&datastruct.User{ID: 1, Role: datastruct.GeneralUser.String()}, nil)
+			organizationRepository.Mock.On("FindMember", ctx, uint(3), uint(1)).
+				Return(&datastruct.OrganizationMember{OrganizationId: 3, UserId: 1, Role: c.actorRole}, nil)
+			userRepository.Mock.On("FindById", mock.Anything, uint(2)).
+				Return(&datastruct.User{ID: 2, Role: datastruct.GeneralUser.String(), OrganizationRole: c.targetRole}, nil)
+			for _, role := range testRoles {
+				roleRepository.Mock.On("FindRoleByName", ctx, role.Name).Return(role, nil)
+			}
+			organizationRepository.Mock.On("UpdateMemberRole", ctx, mock.MatchedBy(
+				func(member *datastruct.OrganizationMember) bool {
+					return member.OrganizationId == 3 && member.UserId == 2 && member.Role == c.newRole
+				},
+			), mock.MatchedBy(
+				func(change *datastruct.RoleChange) bool {
+					return change.OrganizationId == 3 && change.ActorId == 1 && change.UserId == 2 &&
+						change.OldRole == c.targetRole && change.NewRole == c.newRole
+				},
+			)).Return(nil)
+
+			res, err := adminUserService.ChangeUserRole(ctx, actor, 2, dto.ChangeRoleRequest{Role: c.newRole})
+
+			if c.expectedErr != nil {
+				assert.ErrorIs(t, err, c.expectedErr)
+				organizationRepository.Mock.AssertNotCalled(t, "UpdateMemberRole")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.newRole, res.OrganizationRole)
+			organizationRepository.Mock.AssertNumberOfCalls(t, "UpdateMemberRole", 1)
+		})
+	}
+
+	t.Run("signs out the demoted user", func(t *testing.T) {
+		userRepository := new(mocks.UserRepositoryInterface)
+		roleRepository := new(mocks.RoleRepositoryInterface)
+		organizationRepository := new(mocks.OrganizationRepositoryInterface)
+		sessionRepository := new(mocks.SessionRepositoryInterface)
+		adminUserService := service.NewAdminUserService(userRepository, roleRepository, organizationRepository,
+			sessionRepository, nil)
+
+		userRepository.Mock.On("FindById", mock.Anything, uint(1)).
+			Return(&datastruct.User{ID: 1, Role: datastruct.GeneralUser.String()}, nil)
+		organizationRepository.Mock.On("FindMember", ctx, uint(3), uint(1)).
+			Return(&datastruct.OrganizationMember{OrganizationId: 3, UserId: 1, Role: "superadmin"}, nil)
+		userRepository.Mock.On("FindById", mock.Anything, uint(2)).
+			Return(&datastruct.User{ID: 2, Role: datastruct.GeneralUser.String(), OrganizationRole: "admin"}, nil)
+		for _, role := range testRoles {
+			roleRepository.Mock.On("FindRoleByName", ctx, role.Name).Return(role, nil)
+		}
+		organizationRepository.Mock.On("UpdateMemberRole", ctx, mock.Anything, mock.Anything).Return(nil)
+		session := &datastruct.Session{ID: "session", UserId: 2}
+		sessionRepository.Mock.On("FindSessionById", ctx, "session").Return(session, nil)
+		sessionRepository.Mock.On("RevokeSessionsByUserId", ctx, uint(2), "").Run(func(args mock.Arguments) {
+			now := time.Now()
+			session.RevokedAt = &now
+		}).Return(nil)
+
+		_, err := adminUserService.ChangeUserRole(ctx, &service.Claims{UserID: 1, OrgID: 3}, 2,
+			dto.ChangeRoleRequest{Role: "general-user"})
+
+		// The old token, still claiming users:read and the like, no longer passes RequireSession
+		assert.NoError(t, err)
+		claims := &service.Claims{UserID: 2, SessionID: "session"}
+		assert.ErrorIs(t, service.NewSessionService(sessionRepository).CheckSession(ctx, claims), service.ErrSessionRevoked)
+	})
+
+	t.Run("platform superadmin outside the organization", func(t *testing.T) {
+		userRepository := new(mocks.UserRepositoryInterface)
+		roleRepository := new(mocks.RoleRepositoryInterface)
+		organizationRepository := new(mocks.OrganizationRepositoryInterface)
+		adminUserService := service.NewAdminUserService(userRepository, roleRepository, organizationRepository,
+			revokingSessionRepository(), nil)
+
+		userRepository.Mock.On("FindById", mock.Anything, uint(1)).
+			Return(&datastruct.User{ID: 1, Role: datastruct.SuperAdmin.String()}, nil)
+		userRepository.Mock.On("FindById", mock.Anything, uint(2)).
+			Return(&datastruct.User{ID: 2, OrganizationRole: "general-user"}, nil)
+		for _, role := range testRoles {
+			roleRepository.Mock.On("FindRoleByName", ctx, role.Name).Return(role, nil)
+		}
+		organizationRepository.Mock.On("UpdateMemberRole", ctx, mock.Anything, mock.Anything).Return(nil)
+
+		res, err := adminUserService.ChangeUserRole(ctx, &service.Claims{UserID: 1, OrgID: 3}, 2,
+			dto.ChangeRoleRequest{Role: "admin"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "admin", res.OrganizationRole)
+		organizationRepository.Mock.AssertNotCalled(t, "FindMember")
+	})
+
+	t.Run("cannot change own role", func(t *testing.T) {
+		userRepository := new(mocks.UserRepositoryInterface)
+		adminUserService := service.NewAdminUserService(userRepository, new(mocks.RoleRepositoryInterface),
+			new(mocks.OrganizationRepositoryInterface), nil, nil)
+
+		_, err := adminUserService.ChangeUserRole(ctx, &service.Claims{UserID: 1, OrgID: 3}, 1,
+			dto.ChangeRoleRequest{Role: "superadmin"})
+
+		assert.ErrorIs(t, err, service.ErrRoleForbidden)
+	})
+}
+
+func TestAdminUserService_ImportUsers(t *testing.T) {
+	userRepository := new(mocks.UserRepositoryInterface)
+	adminUserService := service.NewAdminUserService(userRepository, new(mocks.RoleRepositoryInterface),
+		new(mocks.OrganizationRepositoryInterface), nil, nil)
+	ctx := tenant.WithOrganization(context.TODO(), 3)
+	django := "pbkdf2_sha256$1000$seasalt$CZukQOiDYgxA1Tk3myA9e6UnfHP2zk40Mh+WbaX0A8o="
+
+	userRepository.On("CreateUser", ctx, mock.MatchedBy(func(user *datastruct.User) bool {
+		return user.Email == "jdoe@example.com"
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*datastruct.User).ID = 11
+	}).Return(nil)
+	userRepository.On("CreateUser", ctx, mock.MatchedBy(func(user *datastruct.User) bool {
+		return user.Email == "taken@example.com"
+	})).Return(gorm.ErrDuplicatedKey)
+
+	resp, err := adminUserService.ImportUsers(ctx, dto.ImportUsersRequest{Users: []dto.ImportUser{
+		{Username: "jdoe", Email: "jdoe@example.com", PasswordHash: django},
+		{Username: "taken", Email: "taken@example.com", PasswordHash: django},
+		{Username: "md5", Email: "md5@example.com", PasswordHash: "5f4dcc3b5aa765d61d8327deb882cf99"},
+		{Username: "costly", Email: "costly@example.com", PasswordHash: "pbkdf2_sha256$900000000$seasalt$" +
+			"CZukQOiDYgxA1Tk3myA9e6UnfHP2zk40Mh+WbaX0A8o="},
+		{Username: "empty", Email: "empty@example.com", PasswordHash: "pbkdf2_sha256$1000$seasalt$"},
+	}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []dto.ImportedUser{{Index: 0, ID: 11}}, resp.Imported)
+	assert.Equal(t, []dto.ImportUserFailure{
+		{Index: 1, Email: "taken@example.com", Error: service.ErrUserConflict.Error()},
+		{Index: 2, Email: "md5@example.com", Error: password.ErrUnknownHashFormat.Error()},
+		{Index: 3, Email: "costly@example.com", Error: "password hash cost exceeds the import limits"},
+		{Index: 4, Email: "empty@example.com", Error: "invalid pbkdf2_sha256 hash"},
+	}, resp.Failed)
+
+	// The hash is stored as is, in the organization of the admin
+	created := userRepository.Calls[0].Arguments.Get(1).(*datastruct.User)
+	assert.Equal(t, django, created.PasswordHash)
+	assert.Equal(t, uint(3), created.OrganizationId)
+	assert.Equal(t, datastruct.GeneralUser.String(), created.Role)
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/fyfirman/auth-management-go/pkg/auditchain"
+	"gorm.io/gorm"
+)
+
+// auditChainBatchSize is how many events VerifyChain reads at once.
+const auditChainBatchSize = 1000
+
+// auditCheckpointKey is loaded once from AUDIT_CHECKPOINT_KEY_FILE or else AUDIT_CHECKPOINT_KEY, the base64
+// of an Ed25519 seed. It is nil when neither is set.
+var auditCheckpointKey = sync.OnceValues(func() (ed25519.PrivateKey, error) {
+	text := os.Getenv("AUDIT_CHECKPOINT_KEY")
+	if path := os.Getenv("AUDIT_CHECKPOINT_KEY_FILE"); path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		text = string(content)
+	}
+	if text == "" {
+		return nil, nil
+	}
+	return auditchain.ParseSigningKey(text)
+})
+
+// auditCheckpointPublicKey reads AUDIT_CHECKPOINT_PUBLIC_KEY, so checkpoints can be verified without the
+// signing key, or else derives it from the signing key.
+func auditCheckpointPublicKey() (ed25519.PublicKey, error) {
+	if text := os.Getenv("AUDIT_CHECKPOINT_PUBLIC_KEY"); text != "" {
+		return auditchain.ParsePublicKey(text)
+	}
+	key, err := auditCheckpointKey()
+	if err != nil || key == nil {
+		return nil, err
+	}
+	return key.Public().(ed25519.PublicKey), nil
+}
+
+// VerifyChain walks the audit chain of every organization from the first event, matching it against
+// checkpoints. Checkpoints need a public key and a valid signature.
+func (s *AuditService) VerifyChain(
+	ctx context.Context,
+	checkpoints []auditchain.Checkpoint,
+) (*auditchain.Report, error) {
+	if len(checkpoints) > 0 {
+		publicKey, err := auditCheckpointPublicKey()
+		if err != nil {
+			return nil, err
+		}
+		if publicKey == nil {
+			return nil, errors.New("AUDIT_CHECKPOINT_PUBLIC_KEY is needed to verify checkpoints")
+		}
+		for i, checkpoint := range checkpoints {
+			if err := checkpoint.Verify(publicKey); err != nil {
+				return nil, fmt.Errorf("checkpoint %d of event %d: %w", i+1, checkpoint.LastID, err)
+			}
+		}
+	}
+
+	verifier := auditchain.NewVerifier(checkpoints...)
+	afterID := uint(0)
+	for {
+		events, err := s.auditRepository.ListAuditChain(ctx, afterID, auditChainBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		for i := range events {
+			verifier.Add(auditchain.Link{
+				ID:       uint64(events[i].ID),
+				PrevHash: events[i].PrevHash,
+				Hash:     events[i].Hash,
+				Payload:  events[i].ChainPayload(),
+			})
+		}
+		if len(events) < auditChainBatchSize {
+			break
+		}
+		afterID = events[len(events)-1].ID
+	}
+
+	report := verifier.Finish()
+	return &report, nil
+}
+
+// CreateCheckpoint signs the head of the audit chain at now with the key of AUDIT_CHECKPOINT_KEY.
+func (s *AuditService) CreateCheckpoint(ctx context.Context, now time.Time) (*auditchain.Checkpoint, error) {
+	key, err := auditCheckpointKey()
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, ErrAuditCheckpointKeyUnset
+	}
+
+	head, err := s.auditRepository.FindLastAuditEvent(ctx)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAuditChainEmpty
+	}
+	if err != nil {
+		return nil, err
+	}
+	if head.Hash == "" {
+		return nil, ErrAuditChainEmpty
+	}
+
+	checkpoint := auditchain.NewCheckpoint(uint64(head.ID), head.Hash, now, key)
+	return &checkpoint, nil
+}
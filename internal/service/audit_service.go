@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"log"
+	"net/netip"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fyfirman/auth-management-go/internal/clientinfo"
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+	"github.com/fyfirman/auth-management-go/internal/dto"
+	"github.com/fyfirman/auth-management-go/internal/repository"
+	"github.com/fyfirman/auth-management-go/internal/tenant"
+	"github.com/fyfirman/auth-management-go/pkg/auditchain"
+	"github.com/fyfirman/auth-management-go/pkg/auditsink"
+)
+
+// Lengths, in characters, of the columns of the client of audit events.
+const (
+	maxAuditIP        = 64
+	maxAuditUserAgent = 512
+)
+
+// AuditRecorder records security audit events. Recording never fails the operation being audited.
+type AuditRecorder interface {
+	Record(ctx context.Context, event *datastruct.AuditEvent)
+}
+
+type AuditServiceInterface interface {
+	AuditRecorder
+	ListEvents(ctx context.Context, req dto.ListAuditEventsRequest) (*dto.ListAuditEventsResponse, error)
+	VerifyChain(ctx context.Context, checkpoints []auditchain.Checkpoint) (*auditchain.Report, error)
+	CreateCheckpoint(ctx context.Context, now time.Time) (*auditchain.Checkpoint, error)
+}
+
+type AuditService struct {
+	auditRepository repository.AuditRepositoryInterface
+	sink            auditsink.Sink
+}
+
+// NewAuditService returns an audit service also streaming the recorded events to sink, which may be nil.
+func NewAuditService(auditRepository repository.AuditRepositoryInterface, sink auditsink.Sink) *AuditService {
+	return &AuditService{auditRepository: auditRepository, sink: sink}
+}
+
+// Record stores event with the client of the request in ctx, and its organization unless the event names
+// one, then hands it to the sink. Failures are logged; the sink reports the events it drops itself.
+func (s *AuditService) Record(ctx context.Context, event *datastruct.AuditEvent) {
+	client := clientinfo.FromContext(ctx)
+	event.IP = client.IP
+	if addr, err := netip.ParseAddr(client.IP); err == nil {
+		event.IP = addr.String()
+	}
+	event.IP = truncateRunes(event.IP, maxAuditIP)
+	event.UserAgent = truncateRunes(client.UserAgent, maxAuditUserAgent)
+	if event.OrganizationId == nil {
+		if organizationID, ok := tenant.OrganizationFromContext(ctx); ok {
+			event.OrganizationId = &organizationID
+		}
+	}
+
+	if err := s.auditRepository.AppendAuditEvent(ctx, event); err != nil {
+		log.Printf("Failed to record the %s audit event: %v", event.Type, err)
+		return
+	}
+
+	if s.sink == nil {
+		return
+	}
+	err := s.sink.Write(auditsink.Event{
+		ID:             uint64(event.ID),
+		Time:           event.CreatedAt,
+		OrganizationID: event.OrganizationId,
+		Type:           event.Type,
+		Outcome:        event.Outcome,
+		ActorID:        event.ActorId,
+		TargetID:       event.TargetId,
+		IP:             event.IP,
+		UserAgent:      event.UserAgent,
+		Details:        event.Details,
+		Hash:           event.Hash,
+	})
+	if err != nil && !errors.Is(err, auditsink.ErrBufferFull) {
+		log.Printf("Failed to stream the %s audit event: %v", event.Type, err)
+	}
+}
+
+// ListEvents returns a page of the audit log of the organization of ctx, newest first.
+func (s *AuditService) ListEvents(
+	ctx context.Context,
+	req dto.ListAuditEventsRequest,
+) (*dto.ListAuditEventsResponse, error) {
+	filter := repository.AuditFilter{
+		Type:     req.Type,
+		Outcome:  req.Outcome,
+		ActorId:  req.ActorID,
+		TargetId: req.TargetID,
+		IP:       req.IP,
+		Since:    req.Since,
+		Until:    req.Until,
+		// One more event than requested tells whether there is a next page
+		Limit: req.Limit + 1,
+	}
+	if req.Cursor != "" {
+		beforeID, err := decodeAuditCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.BeforeId = beforeID
+	}
+
+	events, err := s.auditRepository.ListAuditEvents(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.ListAuditEventsResponse{Data: []dto.AuditEventResponse{}}
+	if len(events) > req.Limit {
+		events = events[:req.Limit]
+		resp.NextCursor = encodeAuditCursor(events[len(events)-1].ID)
+	}
+	for i := range events {
+		resp.Data = append(resp.Data, dto.NewAuditEventResponse(&events[i]))
+	}
+	return resp, nil
+}
+
+// Cursors are the opaque form of the ID of the last event of a page.
+func encodeAuditCursor(id uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(uint64(id), 10)))
+}
+
+func decodeAuditCursor(cursor string) (uint, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	id, err := strconv.ParseUint(string(raw), 10, 64)
+	if err != nil || id == 0 {
+		return 0, ErrInvalidCursor
+	}
+	return uint(id), nil
+}
+
+// recordAudit records event when services are given a recorder, which tests may leave out.
+func recordAudit(ctx context.Context, recorder AuditRecorder, event *datastruct.AuditEvent) {
+	if recorder != nil {
+		recorder.Record(ctx, event)
+	}
+}
+
+// auditID returns a reference to an ID for the organization, actor and target of audit events.
+func auditID(id uint) *uint {
+	return &id
+}
+
+// auditSessionsRevoked records that the sessions of the user targetID were revoked for reason, but
+// keptSessionID when it is set. Only the hash of keptSessionID is recorded, as events leave for the SIEM.
+// actorID is nil when no user revoked them, as for SCIM clients.
+func auditSessionsRevoked(
+	ctx context.Context,
+	recorder AuditRecorder,
+	actorID *uint,
+	targetID uint,
+	reason string,
+	keptSessionID string,
+) {
+	details := map[string]string{"reason": reason}
+	if keptSessionID != "" {
+		details["kept_session_hash"] = hashToken(keptSessionID)
+	}
+	recordAudit(ctx, recorder, &datastruct.AuditEvent{
+		Type:     datastruct.AuditSessionsRevoked,
+		Outcome:  datastruct.AuditSuccess,
+		ActorId:  actorID,
+		TargetId: auditID(targetID),
+		Details:  details,
+	})
+}
+
+// truncateRunes cuts s to at most n characters without splitting one.
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
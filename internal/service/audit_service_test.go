@@ -0,0 +1,198 @@
+package service_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fyfirman/auth-management-go/internal/clientinfo"
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+	"github.com/fyfirman/auth-management-go/internal/dto"
+	"github.com/fyfirman/auth-management-go/internal/repository"
+	"github.com/fyfirman/auth-management-go/internal/repository/mocks"
+	"github.com/fyfirman/auth-management-go/internal/service"
+	"github.com/fyfirman/auth-management-go/internal/tenant"
+	"github.com/fyfirman/auth-management-go/pkg/auditchain"
+	"github.com/fyfirman/auth-management-go/pkg/auditsink"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// recordingSink keeps the events streamed to it.
+type recordingSink struct {
+	events []auditsink.Event
+}
+
+func (s *recordingSink) Write(event auditsink.Event) error {
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *recordingSink) Close() error {
+	return nil
+}
+
+func TestAuditService_Record(t *testing.T) {
+	auditRepository := new(mocks.AuditRepositoryInterface)
+	sink := &recordingSink{}
+	auditService := service.NewAuditService(auditRepository, sink)
+	ctx := clientinfo.WithInfo(tenant.WithOrganization(context.TODO(), 3), clientinfo.Info{
+		IP:        "203.0.113.7",
+		UserAgent: "curl/8.4.0",
+	})
+
+	auditRepository.On("AppendAuditEvent", ctx, mock.MatchedBy(func(event *datastruct.AuditEvent) bool {
+		return event.IP == "203.0.113.7" && event.UserAgent == "curl/8.4.0" &&
+			event.OrganizationId != nil && *event.OrganizationId == 3
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*datastruct.AuditEvent).ID = 12
+	}).Return(nil)
+
+	auditService.Record(ctx, &datastruct.AuditEvent{Type: datastruct.AuditLogin, Outcome: datastruct.AuditSuccess})
+
+	auditRepository.AssertExpectations(t)
+	if assert.Len(t, sink.events, 1) {
+		assert.Equal(t, uint64(12), sink.events[0].ID)
+		assert.Equal(t, "203.0.113.7", sink.events[0].IP)
+	}
+}
+
+func TestAuditService_Record_ClientLimits(t *testing.T) {
+	auditRepository := new(mocks.AuditRepositoryInterface)
+	auditService := service.NewAuditService(auditRepository, nil)
+	ctx := clientinfo.WithInfo(context.TODO(), clientinfo.Info{
+		IP:        strings.Repeat("1.2.3.4, ", 20),
+		UserAgent: strings.Repeat("é", 600),
+	})
+
+	auditRepository.On("AppendAuditEvent", ctx, mock.MatchedBy(func(event *datastruct.AuditEvent) bool {
+		return utf8.RuneCountInString(event.IP) == 64 &&
+			utf8.ValidString(event.UserAgent) && utf8.RuneCountInString(event.UserAgent) == 512
+	})).Return(nil)
+
+	auditService.Record(ctx, &datastruct.AuditEvent{Type: datastruct.AuditLogin, Outcome: datastruct.AuditSuccess})
+
+	auditRepository.AssertExpectations(t)
+}
+
+func TestAuditService_ListEvents(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("pages with a cursor", func(t *testing.T) {
+		auditRepository := new(mocks.AuditRepositoryInterface)
+		auditService := service.NewAuditService(auditRepository, nil)
+		auditRepository.On("ListAuditEvents", ctx, repository.AuditFilter{Outcome: "failure", Limit: 3}).
+			Return([]datastruct.AuditEvent{{ID: 9}, {ID: 8}, {ID: 7}}, nil)
+
+		first, err := auditService.ListEvents(ctx, dto.ListAuditEventsRequest{Outcome: "failure", Limit: 2})
+
+		assert.NoError(t, err)
+		assert.Len(t, first.Data, 2)
+		assert.NotEmpty(t, first.NextCursor)
+
+		auditRepository.On("ListAuditEvents", ctx, repository.AuditFilter{Outcome: "failure", BeforeId: 8, Limit: 3}).
+			Return([]datastruct.AuditEvent{{ID: 7}}, nil)
+
+		second, err := auditService.ListEvents(ctx,
+			dto.ListAuditEventsRequest{Outcome: "failure", Cursor: first.NextCursor, Limit: 2})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), second.Data[0].ID)
+		assert.Empty(t, second.NextCursor)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		auditRepository := new(mocks.AuditRepositoryInterface)
+		auditService := service.NewAuditService(auditRepository, nil)
+
+		_, err := auditService.ListEvents(ctx, dto.ListAuditEventsRequest{Cursor: "not a cursor", Limit: 2})
+
+		assert.ErrorIs(t, err, service.ErrInvalidCursor)
+		auditRepository.AssertNotCalled(t, "ListAuditEvents", mock.Anything, mock.Anything)
+	})
+}
+
+// chainedEvents returns audit events linked as AppendAuditEvent links them.
+func chainedEvents(types ...string) []datastruct.AuditEvent {
+	var events []datastruct.AuditEvent
+	prevHash := ""
+	for i, eventType := range types {
+		event := datastruct.AuditEvent{
+			ID:        uint(i + 1),
+			Type:      eventType,
+			Outcome:   datastruct.AuditSuccess,
+			PrevHash:  prevHash,
+			CreatedAt: time.Date(2026, 10, 20, 12, i, 0, 0, time.UTC),
+		}
+		event.Hash = auditchain.Hash(prevHash, event.ChainPayload())
+		events = append(events, event)
+		prevHash = event.Hash
+	}
+	return events
+}
+
+func TestAuditService_VerifyChain(t *testing.T) {
+	ctx := context.TODO()
+	seed := make([]byte, ed25519.SeedSize)
+	key := ed25519.NewKeyFromSeed(seed)
+	t.Setenv("AUDIT_CHECKPOINT_PUBLIC_KEY", base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)))
+
+	t.Run("reports altered events", func(t *testing.T) {
+		auditRepository := new(mocks.AuditRepositoryInterface)
+		auditService := service.NewAuditService(auditRepository, nil)
+		events := chainedEvents(datastruct.AuditRegister, datastruct.AuditLogin, datastruct.AuditLogin)
+		events[1].Outcome = datastruct.AuditFailure
+		auditRepository.On("ListAuditChain", ctx, uint(0), mock.Anything).Return(events, nil)
+
+		report, err := auditService.VerifyChain(ctx, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, report.Entries)
+		assert.Equal(t, []auditchain.Break{{ID: 2, Reason: "the entry does not match its hash"}}, report.Breaks)
+	})
+
+	t.Run("matches checkpoints", func(t *testing.T) {
+		auditRepository := new(mocks.AuditRepositoryInterface)
+		auditService := service.NewAuditService(auditRepository, nil)
+		events := chainedEvents(datastruct.AuditRegister, datastruct.AuditLogin)
+		auditRepository.On("ListAuditChain", ctx, uint(0), mock.Anything).Return(events, nil)
+		checkpoint := auditchain.NewCheckpoint(2, events[1].Hash, time.Now(), key)
+
+		report, err := auditService.VerifyChain(ctx, []auditchain.Checkpoint{checkpoint})
+
+		assert.NoError(t, err)
+		assert.True(t, report.OK(), report.Breaks)
+		assert.Equal(t, 1, report.Checkpoints)
+	})
+
+	t.Run("rejects forged checkpoints", func(t *testing.T) {
+		auditRepository := new(mocks.AuditRepositoryInterface)
+		auditService := service.NewAuditService(auditRepository, nil)
+		_, otherKey, _ := ed25519.GenerateKey(nil)
+		checkpoint := auditchain.NewCheckpoint(2, "abc", time.Now(), otherKey)
+
+		_, err := auditService.VerifyChain(ctx, []auditchain.Checkpoint{checkpoint})
+
+		assert.ErrorIs(t, err, auditchain.ErrInvalidSignature)
+		auditRepository.AssertNotCalled(t, "ListAuditChain", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestAuditService_CreateCheckpoint(t *testing.T) {
+	ctx := context.TODO()
+	seed := make([]byte, ed25519.SeedSize)
+	t.Setenv("AUDIT_CHECKPOINT_KEY", base64.StdEncoding.EncodeToString(seed))
+	auditRepository := new(mocks.AuditRepositoryInterface)
+	auditService := service.NewAuditService(auditRepository, nil)
+	auditRepository.On("FindLastAuditEvent", ctx).Return(&datastruct.AuditEvent{ID: 9, Hash: "abc"}, nil)
+
+	checkpoint, err := auditService.CreateCheckpoint(ctx, time.Now())
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(9), checkpoint.LastID)
+	assert.NoError(t, checkpoint.Verify(ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey)))
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+	"github.com/fyfirman/auth-management-go/internal/dto"
+	"github.com/fyfirman/auth-management-go/internal/repository"
+	"github.com/fyfirman/auth-management-go/pkg/policy"
+)
+
+type AuthzServiceInterface interface {
+	Check(ctx context.Context, caller *Claims, req dto.AuthzCheckRequest) (*dto.AuthzCheckResponse, error)
+}
+
+type AuthzService struct {
+	engine             *policy.Engine
+	decisionRepository repository.AuthzDecisionRepositoryInterface
+}
+
+func NewAuthzService(
+	engine *policy.Engine,
+	decisionRepository repository.AuthzDecisionRepositoryInterface,
+) *AuthzService {
+	return &AuthzService{engine: engine, decisionRepository: decisionRepository}
+}
+
+// Check evaluates the request against the loaded policies and logs the decision. Checking on behalf of
+// another subject requires the authz:check permission.
+func (s *AuthzService) Check(
+	ctx context.Context,
+	caller *Claims,
+	req dto.AuthzCheckRequest,
+) (*dto.AuthzCheckResponse, error) {
+	subject := req.Subject
+	if subject == nil {
+		subject = subjectFromClaims(caller)
+	} else if !caller.HasPermission(datastruct.PermissionAuthzCheck) {
+		return nil, ErrAuthzForbidden
+	}
+
+	decision := s.engine.Evaluate(policy.Input{
+		Subject:  subject,
+		Action:   req.Action,
+		Resource: req.Resource,
+		Context:  req.Context,
+	})
+
+	resourceType, _ := req.Resource["type"].(string)
+	err := s.decisionRepository.CreateDecision(ctx, &datastruct.AuthzDecision{
+		CallerId:     caller.UserID,
+		Action:       req.Action,
+		ResourceType: resourceType,
+		Subject:      encodeAttributes(subject),
+		Resource:     encodeAttributes(req.Resource),
+		Context:      encodeAttributes(req.Context),
+		Allowed:      decision.Allowed,
+		PolicyId:     decision.PolicyID,
+		Reason:       decision.Reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.AuthzCheckResponse{
+		Allowed:  decision.Allowed,
+		PolicyID: decision.PolicyID,
+		Reason:   decision.Reason,
+	}, nil
+}
+
+func subjectFromClaims(claims *Claims) map[string]interface{} {
+	permissions := make([]interface{}, 0, len(claims.Permissions))
+	for _, permission := range claims.Permissions {
+		permissions = append(permissions, permission)
+	}
+	groups := make([]interface{}, 0, len(claims.Groups))
+	for _, group := range claims.Groups {
+		groups = append(groups, group)
+	}
+	return map[string]interface{}{
+		"id":          float64(claims.UserID),
+		"org_id":      float64(claims.OrgID),
+		"role":        claims.Role,
+		"groups":      groups,
+		"permissions": permissions,
+	}
+}
+
+func encodeAttributes(attributes map[string]interface{}) string {
+	if attributes == nil {
+		return "{}"
+	}
+	encoded, err := json.Marshal(attributes)
+	if err != nil {
+		return "{}"
+	}
+	return string(encoded)
+}
@@ -0,0 +1,83 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+	"github.com/fyfirman/auth-management-go/internal/dto"
+	"github.com/fyfirman/auth-management-go/internal/repository/mocks"
+	"github.com/fyfirman/auth-management-go/internal/service"
+	"github.com/fyfirman/auth-management-go/pkg/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestPolicyEngine(t *testing.T) *policy.Engine {
+	engine, err := policy.NewEngine([]policy.Policy{{
+		ID:        "users-read-themselves",
+		Effect:    policy.Allow,
+		Actions:   []string{"users:read"},
+		Resources: []string{"user"},
+		Condition: "subject.id == resource.id",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return engine
+}
+
+func TestAuthzService_Check(t *testing.T) {
+	ctx := context.TODO()
+	caller := &service.Claims{UserID: 5, Role: "general-user"}
+
+	t.Run("subject defaults to the caller and decision is logged", func(t *testing.T) {
+		decisionRepository := new(mocks.AuthzDecisionRepositoryInterface)
+		authzService := service.NewAuthzService(newTestPolicyEngine(t), decisionRepository)
+
+		decisionRepository.Mock.On("CreateDecision", ctx, mock.MatchedBy(func(d *datastruct.AuthzDecision) bool {
+			return d.CallerId == 5 && d.Allowed && d.PolicyId == "users-read-themselves" && d.ResourceType == "user"
+		})).Return(nil)
+
+		res, err := authzService.Check(ctx, caller, dto.AuthzCheckRequest{
+			Action:   "users:read",
+			Resource: map[string]interface{}{"type": "user", "id": float64(5)},
+		})
+
+		assert.NoError(t, err)
+		assert.True(t, res.Allowed)
+		decisionRepository.AssertExpectations(t)
+	})
+
+	t.Run("denied decisions are logged too", func(t *testing.T) {
+		decisionRepository := new(mocks.AuthzDecisionRepositoryInterface)
+		authzService := service.NewAuthzService(newTestPolicyEngine(t), decisionRepository)
+
+		decisionRepository.Mock.On("CreateDecision", ctx, mock.MatchedBy(func(d *datastruct.AuthzDecision) bool {
+			return !d.Allowed
+		})).Return(nil)
+
+		res, err := authzService.Check(ctx, caller, dto.AuthzCheckRequest{
+			Action:   "users:read",
+			Resource: map[string]interface{}{"type": "user", "id": float64(6)},
+		})
+
+		assert.NoError(t, err)
+		assert.False(t, res.Allowed)
+		decisionRepository.AssertExpectations(t)
+	})
+
+	t.Run("explicit subject requires authz:check", func(t *testing.T) {
+		decisionRepository := new(mocks.AuthzDecisionRepositoryInterface)
+		authzService := service.NewAuthzService(newTestPolicyEngine(t), decisionRepository)
+
+		res, err := authzService.Check(ctx, caller, dto.AuthzCheckRequest{
+			Subject: map[string]interface{}{"id": float64(6)},
+			Action:  "users:read",
+		})
+
+		assert.Nil(t, res)
+		assert.ErrorIs(t, err, service.ErrAuthzForbidden)
+		decisionRepository.Mock.AssertNotCalled(t, "CreateDecision")
+	})
+}
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"time"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+	"github.com/fyfirman/auth-management-go/internal/dto"
+	"github.com/fyfirman/auth-management-go/internal/repository"
+	"github.com/fyfirman/auth-management-go/pkg/mail_server"
+	"gorm.io/gorm"
+)
+
+// DataExportServiceInterface hands users a copy of their personal data. Exports are requested through the
+// API, built in the background by ProcessPendingExports and downloaded with the emailed link.
+type DataExportServiceInterface interface {
+	RequestExport(ctx context.Context, actor *Claims) (*dto.DataExportResponse, error)
+	DownloadExport(ctx context.Context, token string) ([]byte, error)
+	ProcessPendingExports(ctx context.Context) (int, error)
+}
+
+const (
+	dataExportExpiry = 48 * time.Hour
+
+	// dataExportLease is how long an export stays claimed by a job. Exports still processing after that,
+	// because the job crashed, are claimed again.
+	dataExportLease = 15 * time.Minute
+
+	// dataExportBatchSize is how many exports ProcessPendingExports claims at once.
+	dataExportBatchSize = 10
+)
+
+type DataExportService struct {
+	dataExportRepository   repository.DataExportRepositoryInterface
+	userRepository         repository.UserRepositoryInterface
+	organizationRepository repository.OrganizationRepositoryInterface
+	groupRepository        repository.GroupRepositoryInterface
+	sessionRepository      repository.SessionRepositoryInterface
+	auditRepository        repository.AuditRepositoryInterface
+	mailer                 mail_server.MailInterface
+}
+
+func NewDataExportService(
+	dataExportRepository repository.DataExportRepositoryInterface,
+	userRepository repository.UserRepositoryInterface,
+	organizationRepository repository.OrganizationRepositoryInterface,
+	groupRepository repository.GroupRepositoryInterface,
+	sessionRepository repository.SessionRepositoryInterface,
+	auditRepository repository.AuditRepositoryInterface,
+	mailer mail_server.MailInterface,
+) *DataExportService {
+	return &DataExportService{
+		dataExportRepository:   dataExportRepository,
+		userRepository:         userRepository,
+		organizationRepository: organizationRepository,
+		groupRepository:        groupRepository,
+		sessionRepository:      sessionRepository,
+		auditRepository:        auditRepository,
+		mailer:                 mailer,
+	}
+}
+
+// RequestExport queues an export of the actor's data. The archive is built asynchronously. Only one export
+// per user can be pending at a time.
+func (s *DataExportService) RequestExport(ctx context.Context, actor *Claims) (*dto.DataExportResponse, error) {
+	if _, err := s.userRepository.FindById(accountContext(ctx), actor.UserID); err != nil {
+		return nil, mapUserError(err)
+	}
+
+	// The download token is issued once the export is built; until then the hash of a token nobody was
+	// sent holds its place.
+	token, err := generateForgotPasswordToken()
+	if err != nil {
+		return nil, err
+	}
+	export := &datastruct.DataExport{
+		UserId: actor.UserID,
+		Status: datastruct.DataExportPending,
+		Token:  hashToken(token),
+	}
+	err = s.dataExportRepository.CreateDataExport(ctx, export)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return nil, ErrDataExportInProgress
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.DataExportResponse{
+		ID:        int64(export.ID),
+		Status:    export.Status,
+		CreatedAt: export.CreatedAt,
+	}, nil
+}
+
+// DownloadExport returns the archive of a ready export whose link has not expired.
+func (s *DataExportService) DownloadExport(ctx context.Context, token string) ([]byte, error) {
+	export, err := s.dataExportRepository.FindDataExportByToken(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDataExportNotFound
+		}
+		return nil, err
+	}
+	if export.Status != datastruct.DataExportReady || export.ExpiredAt == nil || export.ExpiredAt.Before(time.Now()) {
+		return nil, ErrDataExportNotFound
+	}
+	return export.Archive, nil
+}
+
+// ProcessPendingExports builds the pending exports, emails their download links and drops the expired ones.
+// It returns how many exports were built. An export that cannot be built is marked failed and logged, and
+// dropped once it expires like a ready one.
+func (s *DataExportService) ProcessPendingExports(ctx context.Context) (int, error) {
+	now := time.Now()
+	if err := s.dataExportRepository.DeleteExpiredDataExports(ctx, now); err != nil {
+		return 0, err
+	}
+
+	exports, err := s.dataExportRepository.ClaimPendingDataExports(ctx, dataExportBatchSize, now.Add(-dataExportLease))
+	if err != nil {
+		return 0, err
+	}
+
+	built := 0
+	for i := range exports {
+		export := &exports[i]
+		if err := s.processExport(ctx, export); err != nil {
+			log.Printf("Failed to build data export %d: %v", export.ID, err)
+			expiredAt := time.Now().Add(dataExportExpiry)
+			export.Status = datastruct.DataExportFailed
+			export.ExpiredAt = &expiredAt
+			if err := s.dataExportRepository.SaveDataExport(ctx, export); err != nil {
+				return built, err
+			}
+			continue
+		}
+		built++
+	}
+	return built, nil
+}
+
+func (s *DataExportService) processExport(ctx context.Context, export *datastruct.DataExport) error {
+	user, err := s.userRepository.FindById(accountContext(ctx), export.UserId)
+	if err != nil {
+		return mapUserError(err)
+	}
+
+	archive, err := s.buildArchive(ctx, user)
+	if err != nil {
+		return err
+	}
+	export.Archive, err = json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	token, err := generateForgotPasswordToken()
+	if err != nil {
+		return err
+	}
+	completedAt := time.Now()
+	expiredAt := completedAt.Add(dataExportExpiry)
+	export.Token = hashToken(token)
+	export.Status = datastruct.DataExportReady
+	export.CompletedAt = &completedAt
+	export.ExpiredAt = &expiredAt
+	if err := s.dataExportRepository.SaveDataExport(ctx, export); err != nil {
+		return err
+	}
+
+	if _, err := s.mailer.Send(&mail_server.SendEmailRequest{
+		From:    os.Getenv("EMAIL_SENDER"),
+		To:      []string{user.Email},
+		Subject: "Auth management - Your data export is ready",
+		Html: "<p> Your data export is ready. Download it before " + expiredAt.UTC().Format(time.RFC1123) +
+			" : " + os.Getenv("BASE_URL") + "/exports/" + token + "</p>",
+	}); err != nil {
+		log.Printf("Failed to send the data export link to user %d: %v", user.ID, err)
+	}
+	return nil
+}
+
+func (s *DataExportService) buildArchive(ctx context.Context, user *datastruct.User) (*dto.DataExportArchive, error) {
+	archive := &dto.DataExportArchive{
+		ExportedAt:  time.Now(),
+		Profile:     dto.NewProfileResponse(user),
+		Identities:  []dto.ExportedIdentity{},
+		Memberships: []dto.ExportedMembership{},
+		Sessions:    []dto.ExportedSession{},
+		AuditEvents: []dto.ExportedAuditEvent{},
+		// No consents are recorded yet; the key is kept so the archive layout stays stable.
+		Consents: []map[string]interface{}{},
+	}
+
+	if user.ExternalId != "" {
+		archive.Identities = append(archive.Identities, dto.ExportedIdentity{
+			Provider:       "scim",
+			ExternalID:     user.ExternalId,
+			OrganizationID: int64(user.OrganizationId),
+		})
+	}
+
+	memberships, err := s.organizationRepository.ListMembershipsByUserId(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, membership := range memberships {
+		groups, err := s.groupRepository.ListGroupsByUserId(ctx, membership.OrganizationId, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		exported := dto.ExportedMembership{
+			OrganizationID: int64(membership.OrganizationId),
+			Role:           membership.Role,
+			Groups:         make([]string, 0, len(groups)),
+			JoinedAt:       membership.CreatedAt,
+		}
+		if membership.Organization != nil {
+			exported.OrganizationName = membership.Organization.Name
+		}
+		for _, group := range groups {
+			exported.Groups = append(exported.Groups, group.Name)
+		}
+		archive.Memberships = append(archive.Memberships, exported)
+	}
+
+	sessions, err := s.sessionRepository.ListSessionsByUserId(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, session := range sessions {
+		archive.Sessions = append(archive.Sessions, dto.ExportedSession{
+			CreatedAt: session.CreatedAt,
+			RevokedAt: session.RevokedAt,
+		})
+	}
+
+	changes, err := s.organizationRepository.ListRoleChangesByUserId(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, change := range changes {
+		archive.AuditEvents = append(archive.AuditEvents, dto.ExportedAuditEvent{
+			Type:           "role_change",
+			OrganizationID: int64(change.OrganizationId),
+			ActorID:        int64(change.ActorId),
+			UserID:         int64(change.UserId),
+			Details:        map[string]interface{}{"old_role": change.OldRole, "new_role": change.NewRole},
+			CreatedAt:      change.CreatedAt,
+		})
+	}
+
+	events, err := s.auditRepository.ListAuditEventsByUserId(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range events {
+		// Role changes are exported from their own history above
+		if event.Type == datastruct.AuditRoleChanged {
+			continue
+		}
+		details := map[string]interface{}{"outcome": event.Outcome, "ip": event.IP, "user_agent": event.UserAgent}
+		for key, value := range event.Details {
+			details[key] = value
+		}
+		archive.AuditEvents = append(archive.AuditEvents, dto.ExportedAuditEvent{
+			Type:           event.Type,
+			OrganizationID: exportedID(event.OrganizationId),
+			ActorID:        exportedID(event.ActorId),
+			UserID:         exportedID(event.TargetId),
+			Details:        details,
+			CreatedAt:      event.CreatedAt,
+		})
+	}
+
+	return archive, nil
+}
+
+// exportedID returns the optional ID of an audit event, 0 when it is not set.
+func exportedID(id *uint) int64 {
+	if id == nil {
+		return 0
+	}
+	return int64(*id)
+}
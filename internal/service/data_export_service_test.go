@@ -0,0 +1,194 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+	"github.com/fyfirman/auth-management-go/internal/dto"
+	"github.com/fyfirman/auth-management-go/internal/repository/mocks"
+	"github.com/fyfirman/auth-management-go/internal/service"
+	"github.com/fyfirman/auth-management-go/pkg/mail_server"
+	mailmocks "github.com/fyfirman/auth-management-go/pkg/mail_server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type dataExportMocks struct {
+	exports       *mocks.DataExportRepositoryInterface
+	users         *mocks.UserRepositoryInterface
+	organizations *mocks.OrganizationRepositoryInterface
+	groups        *mocks.GroupRepositoryInterface
+	sessions      *mocks.SessionRepositoryInterface
+	audit         *mocks.AuditRepositoryInterface
+	mailer        *mailmocks.MailInterface
+}
+
+func newDataExportService() (*service.DataExportService, dataExportMocks) {
+	m := dataExportMocks{
+		exports:       new(mocks.DataExportRepositoryInterface),
+		users:         new(mocks.UserRepositoryInterface),
+		organizations: new(mocks.OrganizationRepositoryInterface),
+		groups:        new(mocks.GroupRepositoryInterface),
+		sessions:      new(mocks.SessionRepositoryInterface),
+		audit:         new(mocks.AuditRepositoryInterface),
+		mailer:        new(mailmocks.MailInterface),
+	}
+	exportService := service.NewDataExportService(m.exports, m.users, m.organizations, m.groups, m.sessions, m.audit,
+		m.mailer)
+	return exportService, m
+}
+
+func TestDataExportService_RequestExport(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("queues an export", func(t *testing.T) {
+		exportService, m := newDataExportService()
+		m.users.On("FindById", mock.Anything, uint(7)).Return(&datastruct.User{ID: 7}, nil)
+		m.exports.On("CreateDataExport", ctx, mock.AnythingOfType("*datastruct.DataExport")).Return(nil)
+
+		res, err := exportService.RequestExport(ctx, &service.Claims{UserID: 7, OrgID: 3})
+
+		assert.NoError(t, err)
+		assert.Equal(t, datastruct.DataExportPending, res.Status)
+		export := m.exports.Calls[0].Arguments.Get(1).(*datastruct.DataExport)
+		assert.Equal(t, uint(7), export.UserId)
+		assert.NotEmpty(t, export.Token)
+	})
+
+	t.Run("refuses a second export while one is pending", func(t *testing.T) {
+		exportService, m := newDataExportService()
+		m.users.On("FindById", mock.Anything, uint(7)).Return(&datastruct.User{ID: 7}, nil)
+		m.exports.On("CreateDataExport", ctx, mock.AnythingOfType("*datastruct.DataExport")).
+			Return(gorm.ErrDuplicatedKey)
+
+		_, err := exportService.RequestExport(ctx, &service.Claims{UserID: 7, OrgID: 3})
+
+		assert.ErrorIs(t, err, service.ErrDataExportInProgress)
+	})
+}
+
+func TestDataExportService_ProcessPendingExports(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("builds the archive and emails the link", func(t *testing.T) {
+		exportService, m := newDataExportService()
+		revokedAt := time.Date(2026, 10, 2, 8, 0, 0, 0, time.UTC)
+		m.exports.On("DeleteExpiredDataExports", ctx, mock.Anything).Return(nil)
+		m.exports.On("ClaimPendingDataExports", ctx, mock.Anything, mock.MatchedBy(func(staleBefore time.Time) bool {
+			return time.Since(staleBefore).Round(time.Minute) == 15*time.Minute
+		})).Return([]datastruct.DataExport{
+			{ID: 1, UserId: 7, Status: datastruct.DataExportProcessing, Token: "TOKEN"},
+		}, nil)
+		m.users.On("FindById", mock.Anything, uint(7)).
+			Return(&datastruct.User{ID: 7, OrganizationId: 3, ExternalId: "00u1", Email: "jdoe@example.com"}, nil)
+		m.organizations.On("ListMembershipsByUserId", ctx, uint(7)).Return([]datastruct.OrganizationMember{
+			{OrganizationId: 3, UserId: 7, Role: "admin", Organization: &datastruct.Organization{ID: 3, Name: "Acme"}},
+		}, nil)
+		m.groups.On("ListGroupsByUserId", ctx, uint(3), uint(7)).
+			Return([]datastruct.Group{{ID: 5, Name: "engineering"}}, nil)
+		m.sessions.On("ListSessionsByUserId", ctx, uint(7)).
+			Return([]datastruct.Session{{ID: "secret", UserId: 7, RevokedAt: &revokedAt}}, nil)
+		m.organizations.On("ListRoleChangesByUserId", ctx, uint(7)).Return([]datastruct.RoleChange{
+			{OrganizationId: 3, ActorId: 1, UserId: 7, OldRole: "general-user", NewRole: "admin"},
+		}, nil)
+		userID := uint(7)
+		m.audit.On("ListAuditEventsByUserId", ctx, uint(7)).Return([]datastruct.AuditEvent{
+			{Type: datastruct.AuditRoleChanged, ActorId: &userID, TargetId: &userID},
+			{Type: datastruct.AuditLogin, Outcome: datastruct.AuditSuccess, ActorId: &userID, TargetId: &userID,
+				IP: "203.0.113.7"},
+		}, nil)
+		m.exports.On("SaveDataExport", ctx, mock.AnythingOfType("*datastruct.DataExport")).Return(nil)
+		m.mailer.On("Send", mock.Anything).Return(true, nil)
+
+		built, err := exportService.ProcessPendingExports(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, built)
+
+		export := m.exports.Calls[2].Arguments.Get(1).(*datastruct.DataExport)
+		assert.Equal(t, datastruct.DataExportReady, export.Status)
+		assert.True(t, export.ExpiredAt.After(time.Now()))
+		assert.NotContains(t, string(export.Archive), "secret")
+
+		var archive dto.DataExportArchive
+		assert.NoError(t, json.Unmarshal(export.Archive, &archive))
+		assert.Equal(t, "jdoe@example.com", archive.Profile.Email)
+		assert.Equal(t, "00u1", archive.Identities[0].ExternalID)
+		assert.Equal(t, []string{"engineering"}, archive.Memberships[0].Groups)
+		assert.Equal(t, "Acme", archive.Memberships[0].OrganizationName)
+		assert.Len(t, archive.Sessions, 1)
+		assert.Equal(t, "role_change", archive.AuditEvents[0].Type)
+		assert.Len(t, archive.AuditEvents, 2)
+		assert.Equal(t, datastruct.AuditLogin, archive.AuditEvents[1].Type)
+		assert.Equal(t, "203.0.113.7", archive.AuditEvents[1].Details["ip"])
+		assert.NotNil(t, archive.Consents)
+
+		email := m.mailer.Calls[0].Arguments.Get(0).(*mail_server.SendEmailRequest)
+		assert.Equal(t, []string{"jdoe@example.com"}, email.To)
+		assert.NotEqual(t, "TOKEN", export.Token)
+		assert.Equal(t, export.Token, tokenHash(linkToken(email.Html, "/exports/")))
+	})
+
+	t.Run("marks exports of missing users as failed", func(t *testing.T) {
+		exportService, m := newDataExportService()
+		m.exports.On("DeleteExpiredDataExports", ctx, mock.Anything).Return(nil)
+		m.exports.On("ClaimPendingDataExports", ctx, mock.Anything, mock.Anything).
+			Return([]datastruct.DataExport{{ID: 1, UserId: 7, Status: datastruct.DataExportProcessing}}, nil)
+		m.users.On("FindById", mock.Anything, uint(7)).Return(nil, gorm.ErrRecordNotFound)
+		m.exports.On("SaveDataExport", ctx, mock.MatchedBy(func(export *datastruct.DataExport) bool {
+			return export.Status == datastruct.DataExportFailed && export.ExpiredAt != nil
+		})).Return(nil)
+
+		built, err := exportService.ProcessPendingExports(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, built)
+		m.exports.AssertExpectations(t)
+		m.mailer.AssertNotCalled(t, "Send", mock.Anything)
+	})
+}
+
+func TestDataExportService_DownloadExport(t *testing.T) {
+	ctx := context.TODO()
+	future, past := time.Now().Add(time.Hour), time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name   string
+		export *datastruct.DataExport
+		err    error
+	}{
+		{
+			name:   "ready",
+			export: &datastruct.DataExport{Status: datastruct.DataExportReady, ExpiredAt: &future, Archive: []byte("{}")},
+		},
+		{
+			name:   "expired",
+			export: &datastruct.DataExport{Status: datastruct.DataExportReady, ExpiredAt: &past},
+			err:    service.ErrDataExportNotFound,
+		},
+		{
+			name:   "pending",
+			export: &datastruct.DataExport{Status: datastruct.DataExportPending},
+			err:    service.ErrDataExportNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exportService, m := newDataExportService()
+			m.exports.On("FindDataExportByToken", ctx, tokenHash("TOKEN")).Return(tt.export, nil)
+
+			archive, err := exportService.DownloadExport(ctx, "TOKEN")
+
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, []byte("{}"), archive)
+		})
+	}
+}
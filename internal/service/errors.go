@@ -0,0 +1,53 @@
+package service
+
+import "errors"
+
+var (
+	ErrUserNotFound  = errors.New("user not found")
+	ErrUserConflict  = errors.New("username or email is already taken")
+	ErrRoleForbidden = errors.New("not allowed to assign this role")
+	ErrRoleNotFound  = errors.New("role not found")
+	ErrRoleConflict  = errors.New("role already exists")
+	ErrRoleInUse     = errors.New("role is still assigned to users")
+	ErrRoleBuiltIn   = errors.New("operation not allowed on a built-in role")
+
+	ErrInvalidCredentials    = errors.New("invalid credentials")
+	ErrLoginChallengeInvalid = errors.New("sign in code is invalid or has expired")
+
+	ErrProfileModified        = errors.New("profile was modified since it was read")
+	ErrInvalidCurrentPassword = errors.New("current password is incorrect")
+	ErrPasswordReused         = errors.New("new password must differ from the current password")
+	ErrSessionRevoked         = errors.New("session has been revoked")
+	ErrEmailUnchanged         = errors.New("new email must differ from the current email")
+	ErrEmailChangeInvalid     = errors.New("email change link is invalid or has expired")
+	ErrAccountNotRestorable   = errors.New("account cannot be restored")
+	ErrDataExportNotFound     = errors.New("data export not found or expired")
+	ErrDataExportInProgress   = errors.New("a data export is already in progress")
+
+	ErrPermissionNotFound = errors.New("permission not found")
+	ErrPermissionConflict = errors.New("permission already exists")
+
+	ErrAuthzForbidden = errors.New("not allowed to check decisions for other subjects")
+
+	ErrOrganizationNotFound  = errors.New("organization not found")
+	ErrOrganizationConflict  = errors.New("organization slug is already taken")
+	ErrNoOrganization        = errors.New("user does not belong to any organization")
+	ErrNotOrganizationMember = errors.New("not a member of this organization")
+	ErrAlreadyMember         = errors.New("user is already a member of this organization")
+	ErrNotHomeOrganization   = errors.New("user belongs to another organization and can only be removed from this one")
+
+	ErrGroupNotFound = errors.New("group not found")
+	ErrGroupConflict = errors.New("group name is already taken")
+
+	ErrInvalidSCIMToken  = errors.New("invalid SCIM token")
+	ErrSCIMTokenNotFound = errors.New("SCIM token not found")
+
+	ErrInvitationNotFound     = errors.New("invitation not found")
+	ErrInvitationInvalid      = errors.New("invitation is invalid or has expired")
+	ErrInvitationClosed       = errors.New("invitation has already been accepted or revoked")
+	ErrAccountDetailsRequired = errors.New("username and password are required to create an account")
+
+	ErrInvalidCursor           = errors.New("invalid cursor")
+	ErrAuditChainEmpty         = errors.New("the audit chain has no entries yet")
+	ErrAuditCheckpointKeyUnset = errors.New("AUDIT_CHECKPOINT_KEY is not set")
+)
@@ -0,0 +1,395 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+	"github.com/fyfirman/auth-management-go/internal/dto"
+	"github.com/fyfirman/auth-management-go/internal/repository"
+	"github.com/fyfirman/auth-management-go/internal/tenant"
+	"gorm.io/gorm"
+)
+
+// GroupServiceInterface manages the groups of the actor's active organization. Handing out a group's roles
+// and permissions follows the same rules as ChangeUserRole, so every change to a group or its members requires
+// the actor to be able to grant everything the group carries. Changes to what members hold sign them out, see
+// revokeGrantSessions.
+type GroupServiceInterface interface {
+	ListGroups(ctx context.Context, actor *Claims) ([]dto.GroupResponse, error)
+	GetGroup(ctx context.Context, actor *Claims, id uint) (*dto.GroupResponse, error)
+	CreateGroup(ctx context.Context, actor *Claims, req dto.CreateGroupRequest) (*dto.GroupResponse, error)
+	UpdateGroup(ctx context.Context, actor *Claims, id uint, req dto.UpdateGroupRequest) (*dto.GroupResponse, error)
+	DeleteGroup(ctx context.Context, actor *Claims, id uint) error
+	ListGroupMembers(ctx context.Context, actor *Claims, id uint) ([]dto.UserResponse, error)
+	AddGroupMember(ctx context.Context, actor *Claims, id uint, userID uint) error
+	RemoveGroupMember(ctx context.Context, actor *Claims, id uint, userID uint) error
+	EffectivePermissions(ctx context.Context, actor *Claims, userID uint) (*dto.EffectivePermissionsResponse, error)
+}
+
+type GroupService struct {
+	groupRepository        repository.GroupRepositoryInterface
+	userRepository         repository.UserRepositoryInterface
+	roleRepository         repository.RoleRepositoryInterface
+	organizationRepository repository.OrganizationRepositoryInterface
+	sessionRepository      repository.SessionRepositoryInterface
+	auditRecorder          AuditRecorder
+}
+
+func NewGroupService(
+	groupRepository repository.GroupRepositoryInterface,
+	userRepository repository.UserRepositoryInterface,
+	roleRepository repository.RoleRepositoryInterface,
+	organizationRepository repository.OrganizationRepositoryInterface,
+	sessionRepository repository.SessionRepositoryInterface,
+	auditRecorder AuditRecorder,
+) *GroupService {
+	return &GroupService{
+		groupRepository:        groupRepository,
+		userRepository:         userRepository,
+		roleRepository:         roleRepository,
+		organizationRepository: organizationRepository,
+		sessionRepository:      sessionRepository,
+		auditRecorder:          auditRecorder,
+	}
+}
+
+func (s *GroupService) ListGroups(ctx context.Context, actor *Claims) ([]dto.GroupResponse, error) {
+	if actor.OrgID == 0 {
+		return nil, ErrNoOrganization
+	}
+
+	groups, err := s.groupRepository.ListGroups(ctx, actor.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.GroupResponse, 0, len(groups))
+	for i := range groups {
+		response = append(response, *dto.NewGroupResponse(&groups[i]))
+	}
+	return response, nil
+}
+
+func (s *GroupService) GetGroup(ctx context.Context, actor *Claims, id uint) (*dto.GroupResponse, error) {
+	group, err := s.findGroup(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	return dto.NewGroupResponse(group), nil
+}
+
+func (s *GroupService) CreateGroup(
+	ctx context.Context,
+	actor *Claims,
+	req dto.CreateGroupRequest,
+) (*dto.GroupResponse, error) {
+	if actor.OrgID == 0 {
+		return nil, ErrNoOrganization
+	}
+
+	group := &datastruct.Group{
+		OrganizationId: actor.OrgID,
+		Name:           req.Name,
+		Description:    req.Description,
+	}
+	if err := s.resolveGrants(ctx, group, req.Roles, req.Permissions); err != nil {
+		return nil, err
+	}
+	if err := s.authorizeGroup(ctx, actor, group); err != nil {
+		return nil, err
+	}
+
+	if err := s.groupRepository.CreateGroup(ctx, group); err != nil {
+		return nil, mapGroupError(err)
+	}
+	return dto.NewGroupResponse(group), nil
+}
+
+func (s *GroupService) UpdateGroup(
+	ctx context.Context,
+	actor *Claims,
+	id uint,
+	req dto.UpdateGroupRequest,
+) (*dto.GroupResponse, error) {
+	group, err := s.findGroup(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeGroup(ctx, actor, group); err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		group.Name = *req.Name
+	}
+	if req.Description != nil {
+		group.Description = *req.Description
+	}
+	if req.Roles != nil || req.Permissions != nil {
+		roles, permissions := req.Roles, req.Permissions
+		if roles == nil {
+			roles = group.RoleNames()
+		}
+		if permissions == nil {
+			permissions = group.PermissionNames()
+		}
+		if err := s.resolveGrants(ctx, group, roles, permissions); err != nil {
+			return nil, err
+		}
+		if err := s.authorizeGroup(ctx, actor, group); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.groupRepository.SaveGroup(ctx, group); err != nil {
+		return nil, mapGroupError(err)
+	}
+	if req.Roles != nil || req.Permissions != nil {
+		if err := s.revokeMemberSessions(ctx, actor, group.ID); err != nil {
+			return nil, err
+		}
+	}
+	return dto.NewGroupResponse(group), nil
+}
+
+func (s *GroupService) DeleteGroup(ctx context.Context, actor *Claims, id uint) error {
+	group, err := s.findGroup(ctx, actor, id)
+	if err != nil {
+		return err
+	}
+	if err := s.authorizeGroup(ctx, actor, group); err != nil {
+		return err
+	}
+	members, err := s.groupRepository.ListGroupMembers(ctx, group.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.groupRepository.DeleteGroupById(ctx, actor.OrgID, id); err != nil {
+		return mapGroupError(err)
+	}
+	return revokeGrantSessions(ctx, s.sessionRepository, s.auditRecorder, auditID(actor.UserID), "groups_changed",
+		userIDs(members)...)
+}
+
+func (s *GroupService) ListGroupMembers(ctx context.Context, actor *Claims, id uint) ([]dto.UserResponse, error) {
+	if _, err := s.findGroup(ctx, actor, id); err != nil {
+		return nil, err
+	}
+
+	users, err := s.groupRepository.ListGroupMembers(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.UserResponse, 0, len(users))
+	for i := range users {
+		response = append(response, *dto.NewUserResponse(&users[i]))
+	}
+	return response, nil
+}
+
+// AddGroupMember adds a member of the actor's organization to the group. Adding an existing member is a no-op.
+func (s *GroupService) AddGroupMember(ctx context.Context, actor *Claims, id uint, userID uint) error {
+	group, err := s.findGroup(ctx, actor, id)
+	if err != nil {
+		return err
+	}
+	if err := s.authorizeGroup(ctx, actor, group); err != nil {
+		return err
+	}
+	if _, err := s.userRepository.FindById(tenant.WithOrganization(ctx, actor.OrgID), userID); err != nil {
+		return mapUserError(err)
+	}
+
+	err = s.groupRepository.AddGroupMember(ctx, &datastruct.GroupMember{GroupId: group.ID, UserId: userID})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return revokeGrantSessions(ctx, s.sessionRepository, s.auditRecorder, auditID(actor.UserID), "groups_changed",
+		userID)
+}
+
+func (s *GroupService) RemoveGroupMember(ctx context.Context, actor *Claims, id uint, userID uint) error {
+	group, err := s.findGroup(ctx, actor, id)
+	if err != nil {
+		return err
+	}
+	if err := s.authorizeGroup(ctx, actor, group); err != nil {
+		return err
+	}
+	if err := s.groupRepository.RemoveGroupMember(ctx, group.ID, userID); err != nil {
+		return mapUserError(err)
+	}
+	return revokeGrantSessions(ctx, s.sessionRepository, s.auditRecorder, auditID(actor.UserID), "groups_changed",
+		userID)
+}
+
+func (s *GroupService) EffectivePermissions(
+	ctx context.Context,
+	actor *Claims,
+	userID uint,
+) (*dto.EffectivePermissionsResponse, error) {
+	if actor.OrgID == 0 {
+		return nil, ErrNoOrganization
+	}
+
+	user, err := s.userRepository.FindById(tenant.WithOrganization(ctx, actor.OrgID), userID)
+	if err != nil {
+		return nil, mapUserError(err)
+	}
+
+	grants, err := effectiveGrants(ctx, s.roleRepository, s.groupRepository, user, actor.OrgID, user.OrganizationRole)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.EffectivePermissionsResponse{
+		UserID:         int64(user.ID),
+		OrganizationID: int64(actor.OrgID),
+		Role:           grants.Role,
+		Groups:         grants.Groups,
+		Permissions:    grants.Permissions,
+	}, nil
+}
+
+// revokeMemberSessions signs the members of the group out after what it grants changed.
+func (s *GroupService) revokeMemberSessions(ctx context.Context, actor *Claims, groupID uint) error {
+	members, err := s.groupRepository.ListGroupMembers(ctx, groupID)
+	if err != nil {
+		return err
+	}
+	return revokeGrantSessions(ctx, s.sessionRepository, s.auditRecorder, auditID(actor.UserID), "groups_changed",
+		userIDs(members)...)
+}
+
+// userIDs returns the IDs of users.
+func userIDs(users []datastruct.User) []uint {
+	ids := make([]uint, len(users))
+	for i := range users {
+		ids[i] = users[i].ID
+	}
+	return ids
+}
+
+func (s *GroupService) findGroup(ctx context.Context, actor *Claims, id uint) (*datastruct.Group, error) {
+	if actor.OrgID == 0 {
+		return nil, ErrNoOrganization
+	}
+
+	group, err := s.groupRepository.FindGroupById(ctx, actor.OrgID, id)
+	if err != nil {
+		return nil, mapGroupError(err)
+	}
+	return group, nil
+}
+
+// resolveGrants loads the named roles and permissions onto the group, failing on unknown names.
+func (s *GroupService) resolveGrants(
+	ctx context.Context,
+	group *datastruct.Group,
+	roleNames []string,
+	permissionNames []string,
+) error {
+	roles := make([]datastruct.Role, 0, len(roleNames))
+	for _, name := range roleNames {
+		role, err := s.roleRepository.FindRoleByName(ctx, name)
+		if err != nil {
+			return mapRoleError(err)
+		}
+		roles = append(roles, *role)
+	}
+
+	permissions, err := resolvePermissions(ctx, s.roleRepository, permissionNames)
+	if err != nil {
+		return err
+	}
+
+	group.Roles = roles
+	group.Permissions = permissions
+	return nil
+}
+
+// authorizeGroup checks that the actor could grant each role of the group and its direct permissions.
+func (s *GroupService) authorizeGroup(ctx context.Context, actor *Claims, group *datastruct.Group) error {
+	roles := make([]*datastruct.Role, 0, len(group.Roles)+1)
+	for i := range group.Roles {
+		roles = append(roles, &group.Roles[i])
+	}
+	roles = append(roles, &datastruct.Role{Permissions: group.Permissions})
+	return authorizeGrant(ctx, s.userRepository, s.organizationRepository, s.roleRepository, actor, roles...)
+}
+
+// grants is what a user holds in an organization once group memberships are taken into account.
+type grants struct {
+	Role        string
+	Groups      []string
+	Permissions []string
+}
+
+// effectiveGrants merges the permissions of the organization role with those of the user's groups, both
+// granted through group roles and set directly on the groups. Platform superadmins keep their role in every
+// organization, and are the only ones to get datastruct.PlatformPermissions.
+func effectiveGrants(
+	ctx context.Context,
+	roleRepository repository.RoleRepositoryInterface,
+	groupRepository repository.GroupRepositoryInterface,
+	user *datastruct.User,
+	organizationID uint,
+	organizationRole string,
+) (*grants, error) {
+	roleName := organizationRole
+	if user.Role == datastruct.SuperAdmin.String() {
+		roleName = user.Role
+	}
+
+	role, err := roleRepository.FindRoleByName(ctx, roleName)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := groupRepository.ListGroupsByUserId(ctx, organizationID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &grants{Role: roleName, Groups: make([]string, 0, len(groups))}
+	platform := user.Role == datastruct.SuperAdmin.String()
+	seen := make(map[string]bool)
+	add := func(names []string) {
+		for _, name := range names {
+			if !platform && datastruct.IsPlatformPermission(name) {
+				continue
+			}
+			if !seen[name] {
+				seen[name] = true
+				result.Permissions = append(result.Permissions, name)
+			}
+		}
+	}
+
+	add(role.PermissionNames())
+	for _, group := range groups {
+		result.Groups = append(result.Groups, group.Name)
+		for _, groupRole := range group.Roles {
+			add(groupRole.PermissionNames())
+		}
+		add(group.PermissionNames())
+	}
+	if result.Permissions == nil {
+		result.Permissions = []string{}
+	}
+	return result, nil
+}
+
+func mapGroupError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrGroupNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return ErrGroupConflict
+	default:
+		return err
+	}
+}
@@ -0,0 +1,160 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+	"github.com/fyfirman/auth-management-go/internal/dto"
+	"github.com/fyfirman/auth-management-go/internal/repository/mocks"
+	"github.com/fyfirman/auth-management-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type groupMocks struct {
+	groups        *mocks.GroupRepositoryInterface
+	users         *mocks.UserRepositoryInterface
+	roles         *mocks.RoleRepositoryInterface
+	organizations *mocks.OrganizationRepositoryInterface
+	sessions      *mocks.SessionRepositoryInterface
+}
+
+// newGroupService returns a service whose actor (user 1) is an admin of organization 3.
+func newGroupService() (*service.GroupService, groupMocks) {
+	m := groupMocks{
+		groups:        new(mocks.GroupRepositoryInterface),
+		users:         new(mocks.UserRepositoryInterface),
+		roles:         new(mocks.RoleRepositoryInterface),
+		organizations: new(mocks.OrganizationRepositoryInterface),
+		sessions:      new(mocks.SessionRepositoryInterface),
+	}
+	for _, role := range testRoles {
+		m.roles.Mock.On("FindRoleByName", mock.Anything, role.Name).Return(role, nil)
+	}
+	m.users.Mock.On("FindById", mock.Anything, uint(1)).
+		Return(&datastruct.User{ID: 1, Role: datastruct.GeneralUser.String()}, nil)
+	m.organizations.Mock.On("FindMember", mock.Anything, uint(3), uint(1)).
+		Return(&datastruct.OrganizationMember{OrganizationId: 3, UserId: 1, Role: "admin"}, nil)
+	return service.NewGroupService(m.groups, m.users, m.roles, m.organizations, m.sessions, nil), m
+}
+
+func TestGroupService_CreateGroup(t *testing.T) {
+	ctx := context.TODO()
+	actor := &service.Claims{UserID: 1, OrgID: 3}
+
+	t.Run("creates a group in the actor's organization", func(t *testing.T) {
+		groupService, m := newGroupService()
+		m.roles.Mock.On("FindPermissionsByNames", ctx, []string{datastruct.PermissionUsersRead}).
+			Return([]datastruct.Permission{{ID: 1, Name: datastruct.PermissionUsersRead}}, nil)
+		m.groups.Mock.On("CreateGroup", ctx, mock.AnythingOfType("*datastruct.Group")).Return(nil)
+
+		res, err := groupService.CreateGroup(ctx, actor, dto.CreateGroupRequest{
+			Name:        "support-team",
+			Roles:       []string{"support"},
+			Permissions: []string{datastruct.PermissionUsersRead},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), res.OrganizationID)
+		assert.Equal(t, []string{"support"}, res.Roles)
+		assert.Equal(t, []string{datastruct.PermissionUsersRead}, res.Permissions)
+	})
+
+	t.Run("cannot hand out grants the actor could not grant", func(t *testing.T) {
+		groupService, m := newGroupService()
+
+		res, err := groupService.CreateGroup(ctx, actor, dto.CreateGroupRequest{
+			Name:  "auditors",
+			Roles: []string{"auditor"},
+		})
+
+		assert.Nil(t, res)
+		assert.ErrorIs(t, err, service.ErrRoleForbidden)
+		m.groups.Mock.AssertNotCalled(t, "CreateGroup", mock.Anything, mock.Anything)
+	})
+}
+
+func TestGroupService_AddGroupMember(t *testing.T) {
+	ctx := context.TODO()
+	actor := &service.Claims{UserID: 1, OrgID: 3}
+
+	t.Run("adds a member of the organization", func(t *testing.T) {
+		groupService, m := newGroupService()
+		m.groups.Mock.On("FindGroupById", ctx, uint(3), uint(5)).Return(&datastruct.Group{ID: 5, OrganizationId: 3}, nil)
+		m.users.Mock.On("FindById", mock.Anything, uint(8)).Return(&datastruct.User{ID: 8}, nil)
+		m.groups.Mock.On("AddGroupMember", ctx, &datastruct.GroupMember{GroupId: 5, UserId: 8}).
+			Return(gorm.ErrDuplicatedKey)
+
+		err := groupService.AddGroupMember(ctx, actor, 5, 8)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("signs out a new member", func(t *testing.T) {
+		groupService, m := newGroupService()
+		m.groups.Mock.On("FindGroupById", ctx, uint(3), uint(5)).Return(&datastruct.Group{ID: 5, OrganizationId: 3}, nil)
+		m.users.Mock.On("FindById", mock.Anything, uint(8)).Return(&datastruct.User{ID: 8}, nil)
+		m.groups.Mock.On("AddGroupMember", ctx, &datastruct.GroupMember{GroupId: 5, UserId: 8}).Return(nil)
+		m.sessions.Mock.On("RevokeSessionsByUserId", ctx, uint(8), "").Return(nil)
+
+		err := groupService.AddGroupMember(ctx, actor, 5, 8)
+
+		assert.NoError(t, err)
+		m.sessions.Mock.AssertCalled(t, "RevokeSessionsByUserId", ctx, uint(8), "")
+	})
+
+	t.Run("unknown group", func(t *testing.T) {
+		groupService, m := newGroupService()
+		m.groups.Mock.On("FindGroupById", ctx, uint(3), uint(5)).Return(nil, gorm.ErrRecordNotFound)
+
+		err := groupService.AddGroupMember(ctx, actor, 5, 8)
+
+		assert.ErrorIs(t, err, service.ErrGroupNotFound)
+	})
+}
+
+func TestGroupService_EffectivePermissions(t *testing.T) {
+	ctx := context.TODO()
+	groupService, m := newGroupService()
+	m.users.Mock.On("FindById", mock.Anything, uint(8)).
+		Return(&datastruct.User{ID: 8, Role: "general-user", OrganizationRole: "general-user"}, nil)
+	m.groups.Mock.On("ListGroupsByUserId", ctx, uint(3), uint(8)).Return([]datastruct.Group{
+		{Name: "readers", Roles: []datastruct.Role{*testRoles[3]}},
+		{Name: "role-managers", Permissions: []datastruct.Permission{
+			{Name: datastruct.PermissionGroupsWrite}, {Name: datastruct.PermissionUsersRead},
+			// Platform permissions are never granted through an organization
+			{Name: datastruct.PermissionRolesWrite},
+		}},
+	}, nil)
+
+	res, err := groupService.EffectivePermissions(ctx, &service.Claims{UserID: 1, OrgID: 3}, 8)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "general-user", res.Role)
+	assert.Equal(t, []string{"readers", "role-managers"}, res.Groups)
+	assert.Equal(t, []string{datastruct.PermissionUsersRead, datastruct.PermissionGroupsWrite}, res.Permissions)
+}
+
+func TestGroupService_RemoveGroupMember(t *testing.T) {
+	ctx := context.TODO()
+	actor := &service.Claims{UserID: 1, OrgID: 3}
+	groupService, m := newGroupService()
+	m.groups.Mock.On("FindGroupById", ctx, uint(3), uint(5)).Return(&datastruct.Group{ID: 5, OrganizationId: 3}, nil)
+	m.groups.Mock.On("RemoveGroupMember", ctx, uint(5), uint(8)).Return(nil)
+	session := &datastruct.Session{ID: "session", UserId: 8}
+	m.sessions.Mock.On("FindSessionById", ctx, "session").Return(session, nil)
+	m.sessions.Mock.On("RevokeSessionsByUserId", ctx, uint(8), "").Run(func(args mock.Arguments) {
+		now := time.Now()
+		session.RevokedAt = &now
+	}).Return(nil)
+
+	err := groupService.RemoveGroupMember(ctx, actor, 5, 8)
+
+	// The token still carrying the permissions of the group is rejected
+	assert.NoError(t, err)
+	err = service.NewSessionService(m.sessions).CheckSession(ctx, &service.Claims{UserID: 8, SessionID: "session"})
+	assert.ErrorIs(t, err, service.ErrSessionRevoked)
+}
@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"html"
+	"os"
+	"time"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+	"github.com/fyfirman/auth-management-go/internal/dto"
+	"github.com/fyfirman/auth-management-go/internal/repository"
+	"github.com/fyfirman/auth-management-go/internal/tenant"
+	"github.com/fyfirman/auth-management-go/pkg/mail_server"
+	"gorm.io/gorm"
+)
+
+const invitationExpiry = 7 * 24 * time.Hour
+
+type InvitationServiceInterface interface {
+	CreateInvitation(ctx context.Context, actor *Claims, req dto.CreateInvitationRequest) (*dto.InvitationResponse, error)
+	ListInvitations(ctx context.Context, actor *Claims) ([]dto.InvitationResponse, error)
+	ResendInvitation(ctx context.Context, actor *Claims, id uint) (*dto.InvitationResponse, error)
+	RevokeInvitation(ctx context.Context, actor *Claims, id uint) (*dto.InvitationResponse, error)
+	AcceptInvitation(ctx context.Context, req dto.AcceptInvitationRequest) (*dto.AcceptInvitationResponse, error)
+}
+
+type InvitationService struct {
+	invitationRepository   repository.InvitationRepositoryInterface
+	userRepository         repository.UserRepositoryInterface
+	roleRepository         repository.RoleRepositoryInterface
+	organizationRepository repository.OrganizationRepositoryInterface
+	mailer                 mail_server.MailInterface
+}
+
+func NewInvitationService(
+	invitationRepository repository.InvitationRepositoryInterface,
+	userRepository repository.UserRepositoryInterface,
+	roleRepository repository.RoleRepositoryInterface,
+	organizationRepository repository.OrganizationRepositoryInterface,
+	mailer mail_server.MailInterface,
+) *InvitationService {
+	return &InvitationService{
+		invitationRepository:   invitationRepository,
+		userRepository:         userRepository,
+		roleRepository:         roleRepository,
+		organizationRepository: organizationRepository,
+		mailer:                 mailer,
+	}
+}
+
+// CreateInvitation invites an email address into the actor's organization. The same hierarchy as
+// ChangeUserRole applies, so an actor can only invite with a role at or below their own.
+func (s *InvitationService) CreateInvitation(
+	ctx context.Context,
+	actor *Claims,
+	req dto.CreateInvitationRequest,
+) (*dto.InvitationResponse, error) {
+	if actor.OrgID == 0 {
+		return nil, ErrNoOrganization
+	}
+	if err := s.ensureCanInvite(ctx, actor, req.Role); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepository.FindByEmail(tenant.WithOrganization(ctx, 0), req.Email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if user != nil {
+		if _, err := s.organizationRepository.FindMember(ctx, actor.OrgID, user.ID); err == nil {
+			return nil, ErrAlreadyMember
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+
+	token, err := generateForgotPasswordToken()
+	if err != nil {
+		return nil, err
+	}
+	invitation := &datastruct.Invitation{
+		OrganizationId: actor.OrgID,
+		Email:          req.Email,
+		Role:           req.Role,
+		Token:          hashToken(token),
+		InvitedBy:      actor.UserID,
+		ExpiredAt:      time.Now().Add(invitationExpiry),
+	}
+	if err := s.invitationRepository.CreateInvitation(ctx, invitation); err != nil {
+		return nil, err
+	}
+	if err := s.sendInvitation(ctx, invitation, token); err != nil {
+		return nil, err
+	}
+	return dto.NewInvitationResponse(invitation), nil
+}
+
+func (s *InvitationService) ListInvitations(ctx context.Context, actor *Claims) ([]dto.InvitationResponse, error) {
+	if actor.OrgID == 0 {
+		return nil, ErrNoOrganization
+	}
+
+	invitations, err := s.invitationRepository.ListInvitations(ctx, actor.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.InvitationResponse, 0, len(invitations))
+	for i := range invitations {
+		response = append(response, *dto.NewInvitationResponse(&invitations[i]))
+	}
+	return response, nil
+}
+
+// ResendInvitation issues a fresh token and expiry, so links from earlier emails stop working.
+func (s *InvitationService) ResendInvitation(
+	ctx context.Context,
+	actor *Claims,
+	id uint,
+) (*dto.InvitationResponse, error) {
+	invitation, err := s.findOpenInvitation(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureCanInvite(ctx, actor, invitation.Role); err != nil {
+		return nil, err
+	}
+
+	token, err := generateForgotPasswordToken()
+	if err != nil {
+		return nil, err
+	}
+	invitation.Token = hashToken(token)
+	invitation.ExpiredAt = time.Now().Add(invitationExpiry)
+	if err := s.invitationRepository.UpdateInvitation(ctx, invitation); err != nil {
+		return nil, err
+	}
+	if err := s.sendInvitation(ctx, invitation, token); err != nil {
+		return nil, err
+	}
+	return dto.NewInvitationResponse(invitation), nil
+}
+
+func (s *InvitationService) RevokeInvitation(
+	ctx context.Context,
+	actor *Claims,
+	id uint,
+) (*dto.InvitationResponse, error) {
+	invitation, err := s.findOpenInvitation(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	invitation.RevokedAt = &now
+	if err := s.invitationRepository.UpdateInvitation(ctx, invitation); err != nil {
+		return nil, err
+	}
+	return dto.NewInvitationResponse(invitation), nil
+}
+
+// AcceptInvitation adds the invited email to the organization. Existing accounts become members with the
+// invited role; otherwise an account is created in that organization, which is the only way to join an
+// organization other than the default one without an admin.
+func (s *InvitationService) AcceptInvitation(
+	ctx context.Context,
+	req dto.AcceptInvitationRequest,
+) (*dto.AcceptInvitationResponse, error) {
+	invitation, err := s.invitationRepository.FindInvitationByToken(ctx, hashToken(req.Token))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvitationInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if invitation.Status(time.Now()) != "pending" {
+		return nil, ErrInvitationInvalid
+	}
+
+	response := &dto.AcceptInvitationResponse{
+		OrganizationID: int64(invitation.OrganizationId),
+		Role:           invitation.Role,
+	}
+
+	user, err := s.userRepository.FindByEmail(ctx, invitation.Email)
+	switch {
+	case err == nil:
+		if _, err := s.organizationRepository.FindMember(ctx, invitation.OrganizationId, user.ID); err == nil {
+			return nil, ErrAlreadyMember
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if req.Username == "" || req.Password == "" {
+			return nil, ErrAccountDetailsRequired
+		}
+		if err := checkPassword(ctx, "Password", req.Password, req.Username, invitation.Email); err != nil {
+			return nil, err
+		}
+		hashedPassword, err := hashPassword(req.Password)
+		if err != nil {
+			return nil, err
+		}
+		user = &datastruct.User{
+			OrganizationId:   invitation.OrganizationId,
+			Username:         req.Username,
+			Email:            invitation.Email,
+			Role:             datastruct.GeneralUser.String(),
+			OrganizationRole: invitation.Role,
+			PasswordHash:     hashedPassword,
+		}
+		response.AccountCreated = true
+	default:
+		return nil, err
+	}
+
+	err = s.invitationRepository.AcceptInvitation(ctx, invitation, user)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, ErrInvitationInvalid
+	case errors.Is(err, gorm.ErrDuplicatedKey) && !response.AccountCreated:
+		return nil, ErrAlreadyMember
+	case err != nil:
+		return nil, mapUserError(err)
+	}
+
+	response.UserID = int64(user.ID)
+	return response, nil
+}
+
+// ensureCanInvite checks the actor's current organization role against the invited role.
+func (s *InvitationService) ensureCanInvite(ctx context.Context, actor *Claims, roleName string) error {
+	role, err := s.roleRepository.FindRoleByName(ctx, roleName)
+	if err != nil {
+		return mapRoleError(err)
+	}
+	return authorizeGrant(ctx, s.userRepository, s.organizationRepository, s.roleRepository, actor, role)
+}
+
+func (s *InvitationService) findOpenInvitation(
+	ctx context.Context,
+	actor *Claims,
+	id uint,
+) (*datastruct.Invitation, error) {
+	if actor.OrgID == 0 {
+		return nil, ErrNoOrganization
+	}
+
+	invitation, err := s.invitationRepository.FindInvitationById(ctx, actor.OrgID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvitationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if invitation.AcceptedAt != nil || invitation.RevokedAt != nil {
+		return nil, ErrInvitationClosed
+	}
+	return invitation, nil
+}
+
+// sendInvitation emails the invitation link. Only the hash of token is stored on the invitation.
+func (s *InvitationService) sendInvitation(ctx context.Context, invitation *datastruct.Invitation, token string) error {
+	organization, err := s.organizationRepository.FindOrganizationById(ctx, invitation.OrganizationId)
+	if err != nil {
+		return mapOrganizationError(err)
+	}
+
+	_, err = s.mailer.Send(&mail_server.SendEmailRequest{
+		From:    os.Getenv("EMAIL_SENDER"),
+		To:      []string{invitation.Email},
+		Subject: "Auth management - Invitation to join " + organization.Name,
+		Html: "<p> You have been invited to join " + html.EscapeString(organization.Name) + " as " +
+			html.EscapeString(invitation.Role) + ". Accept the invitation here : " + os.Getenv("BASE_URL") +
+			"/invitations/" + token + "</p>",
+	})
+	return err
+}
@@ -0,0 +1,187 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+	"github.com/fyfirman/auth-management-go/internal/dto"
+	"github.com/fyfirman/auth-management-go/internal/repository/mocks"
+	"github.com/fyfirman/auth-management-go/internal/service"
+	"github.com/fyfirman/auth-management-go/pkg/mail_server"
+	mailmocks "github.com/fyfirman/auth-management-go/pkg/mail_server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type invitationMocks struct {
+	invitations   *mocks.InvitationRepositoryInterface
+	users         *mocks.UserRepositoryInterface
+	roles         *mocks.RoleRepositoryInterface
+	organizations *mocks.OrganizationRepositoryInterface
+	mailer        *mailmocks.MailInterface
+}
+
+func newInvitationService() (*service.InvitationService, invitationMocks) {
+	m := invitationMocks{
+		invitations:   new(mocks.InvitationRepositoryInterface),
+		users:         new(mocks.UserRepositoryInterface),
+		roles:         new(mocks.RoleRepositoryInterface),
+		organizations: new(mocks.OrganizationRepositoryInterface),
+		mailer:        new(mailmocks.MailInterface),
+	}
+	for _, role := range testRoles {
+		m.roles.Mock.On("FindRoleByName", mock.Anything, role.Name).Return(role, nil)
+	}
+	return service.NewInvitationService(m.invitations, m.users, m.roles, m.organizations, m.mailer), m
+}
+
+func TestInvitationService_CreateInvitation(t *testing.T) {
+	ctx := context.TODO()
+	actor := &service.Claims{UserID: 1, OrgID: 3}
+
+	t.Run("invites and emails a new address", func(t *testing.T) {
+		invitationService, m := newInvitationService()
+		m.users.Mock.On("FindById", mock.Anything, uint(1)).
+			Return(&datastruct.User{ID: 1, Role: datastruct.GeneralUser.String()}, nil)
+		m.organizations.Mock.On("FindMember", ctx, uint(3), uint(1)).
+			Return(&datastruct.OrganizationMember{OrganizationId: 3, UserId: 1, Role: "admin"}, nil)
+		m.users.Mock.On("FindByEmail", mock.Anything, "new@example.com").Return(nil, gorm.ErrRecordNotFound)
+		m.invitations.Mock.On("CreateInvitation", ctx, mock.AnythingOfType("*datastruct.Invitation")).Return(nil)
+		m.organizations.Mock.On("FindOrganizationById", ctx, uint(3)).
+			Return(&datastruct.Organization{ID: 3, Name: "<b>Acme</b>"}, nil)
+		m.mailer.Mock.On("Send", mock.AnythingOfType("*mail_server.SendEmailRequest")).Return(true, nil)
+
+		res, err := invitationService.CreateInvitation(ctx, actor,
+			dto.CreateInvitationRequest{Email: "new@example.com", Role: "general-user"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "pending", res.Status)
+		assert.Equal(t, int64(3), res.OrganizationID)
+
+		invitation := m.invitations.Mock.Calls[0].Arguments.Get(1).(*datastruct.Invitation)
+		assert.NotEmpty(t, invitation.Token)
+		assert.Equal(t, uint(1), invitation.InvitedBy)
+		m.mailer.Mock.AssertNumberOfCalls(t, "Send", 1)
+		email := m.mailer.Mock.Calls[0].Arguments.Get(0).(*mail_server.SendEmailRequest)
+		assert.Contains(t, email.Html, "&lt;b&gt;Acme&lt;/b&gt;")
+		assert.Equal(t, invitation.Token, tokenHash(linkToken(email.Html, "/invitations/")))
+	})
+
+	t.Run("cannot invite above own role", func(t *testing.T) {
+		invitationService, m := newInvitationService()
+		m.users.Mock.On("FindById", mock.Anything, uint(1)).
+			Return(&datastruct.User{ID: 1, Role: datastruct.GeneralUser.String()}, nil)
+		m.organizations.Mock.On("FindMember", ctx, uint(3), uint(1)).
+			Return(&datastruct.OrganizationMember{OrganizationId: 3, UserId: 1, Role: "admin"}, nil)
+
+		res, err := invitationService.CreateInvitation(ctx, actor,
+			dto.CreateInvitationRequest{Email: "new@example.com", Role: "superadmin"})
+
+		assert.Nil(t, res)
+		assert.ErrorIs(t, err, service.ErrRoleForbidden)
+		m.invitations.Mock.AssertNotCalled(t, "CreateInvitation", mock.Anything, mock.Anything)
+	})
+}
+
+func TestInvitationService_AcceptInvitation(t *testing.T) {
+	ctx := context.TODO()
+	pending := func() *datastruct.Invitation {
+		return &datastruct.Invitation{
+			ID:             5,
+			OrganizationId: 3,
+			Email:          "new@example.com",
+			Role:           "support",
+			Token:          "TOKEN",
+			ExpiredAt:      time.Now().Add(time.Hour),
+		}
+	}
+
+	t.Run("creates an account in the inviting organization", func(t *testing.T) {
+		invitationService, m := newInvitationService()
+		m.invitations.Mock.On("FindInvitationByToken", ctx, tokenHash("TOKEN")).Return(pending(), nil)
+		m.users.Mock.On("FindByEmail", ctx, "new@example.com").Return(nil, gorm.ErrRecordNotFound)
+		m.invitations.Mock.On("AcceptInvitation", ctx, mock.AnythingOfType("*datastruct.Invitation"),
+			mock.AnythingOfType("*datastruct.User")).Return(nil)
+
+		res, err := invitationService.AcceptInvitation(ctx,
+			dto.AcceptInvitationRequest{Token: "TOKEN", Username: "newbie", Password: "c0rrect-horse"})
+
+		assert.NoError(t, err)
+		assert.True(t, res.AccountCreated)
+
+		user := m.invitations.Mock.Calls[1].Arguments.Get(2).(*datastruct.User)
+		assert.Equal(t, uint(0), user.ID)
+		assert.Equal(t, uint(3), user.OrganizationId)
+		assert.Equal(t, datastruct.GeneralUser.String(), user.Role)
+		assert.Equal(t, "support", user.OrganizationRole)
+		m.users.Mock.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("new accounts need credentials", func(t *testing.T) {
+		invitationService, m := newInvitationService()
+		m.invitations.Mock.On("FindInvitationByToken", ctx, tokenHash("TOKEN")).Return(pending(), nil)
+		m.users.Mock.On("FindByEmail", ctx, "new@example.com").Return(nil, gorm.ErrRecordNotFound)
+
+		res, err := invitationService.AcceptInvitation(ctx, dto.AcceptInvitationRequest{Token: "TOKEN"})
+
+		assert.Nil(t, res)
+		assert.ErrorIs(t, err, service.ErrAccountDetailsRequired)
+	})
+
+	t.Run("attaches an existing user", func(t *testing.T) {
+		invitationService, m := newInvitationService()
+		m.invitations.Mock.On("FindInvitationByToken", ctx, tokenHash("TOKEN")).Return(pending(), nil)
+		m.users.Mock.On("FindByEmail", ctx, "new@example.com").Return(&datastruct.User{ID: 8}, nil)
+		m.organizations.Mock.On("FindMember", ctx, uint(3), uint(8)).Return(nil, gorm.ErrRecordNotFound)
+		m.invitations.Mock.On("AcceptInvitation", ctx, mock.AnythingOfType("*datastruct.Invitation"),
+			&datastruct.User{ID: 8}).Return(nil)
+
+		res, err := invitationService.AcceptInvitation(ctx, dto.AcceptInvitationRequest{Token: "TOKEN"})
+
+		assert.NoError(t, err)
+		assert.False(t, res.AccountCreated)
+		assert.Equal(t, int64(8), res.UserID)
+	})
+
+	t.Run("invitation accepted concurrently is rejected", func(t *testing.T) {
+		invitationService, m := newInvitationService()
+		m.invitations.Mock.On("FindInvitationByToken", ctx, tokenHash("TOKEN")).Return(pending(), nil)
+		m.users.Mock.On("FindByEmail", ctx, "new@example.com").Return(&datastruct.User{ID: 8}, nil)
+		m.organizations.Mock.On("FindMember", ctx, uint(3), uint(8)).Return(nil, gorm.ErrRecordNotFound)
+		m.invitations.Mock.On("AcceptInvitation", ctx, mock.AnythingOfType("*datastruct.Invitation"),
+			&datastruct.User{ID: 8}).Return(gorm.ErrRecordNotFound)
+
+		res, err := invitationService.AcceptInvitation(ctx, dto.AcceptInvitationRequest{Token: "TOKEN"})
+
+		assert.Nil(t, res)
+		assert.ErrorIs(t, err, service.ErrInvitationInvalid)
+	})
+
+	t.Run("expired invitation is rejected", func(t *testing.T) {
+		invitationService, m := newInvitationService()
+		invitation := pending()
+		invitation.ExpiredAt = time.Now().Add(-time.Minute)
+		m.invitations.Mock.On("FindInvitationByToken", ctx, tokenHash("TOKEN")).Return(invitation, nil)
+
+		res, err := invitationService.AcceptInvitation(ctx, dto.AcceptInvitationRequest{Token: "TOKEN"})
+
+		assert.Nil(t, res)
+		assert.ErrorIs(t, err, service.ErrInvitationInvalid)
+	})
+}
+
+func TestInvitationService_RevokeInvitation_Closed(t *testing.T) {
+	ctx := context.TODO()
+	invitationService, m := newInvitationService()
+	acceptedAt := time.Now()
+	m.invitations.Mock.On("FindInvitationById", ctx, uint(3), uint(5)).
+		Return(&datastruct.Invitation{ID: 5, OrganizationId: 3, AcceptedAt: &acceptedAt}, nil)
+
+	res, err := invitationService.RevokeInvitation(ctx, &service.Claims{UserID: 1, OrgID: 3}, 5)
+
+	assert.Nil(t, res)
+	assert.ErrorIs(t, err, service.ErrInvitationClosed)
+}
@@ -0,0 +1,421 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fyfirman/auth-management-go/internal/clientinfo"
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+	"github.com/fyfirman/auth-management-go/internal/dto"
+	"github.com/fyfirman/auth-management-go/pkg/geoip"
+	"github.com/fyfirman/auth-management-go/pkg/loginrisk"
+	"github.com/fyfirman/auth-management-go/pkg/mail_server"
+	"gorm.io/gorm"
+)
+
+const (
+	// loginChallengeTTL is how long the code of a risky login can be entered.
+	loginChallengeTTL = 10 * time.Minute
+
+	// maxLoginChallengeAttempts is how many codes can be tried before the challenge is dropped.
+	maxLoginChallengeAttempts = 5
+
+	// maxFarDistanceKm bounds LOGIN_RISK_FAR_DISTANCE_KM to half the circumference of the Earth.
+	maxFarDistanceKm = 20_038
+)
+
+// GeoDatabase is loaded once from GEOIP_DATABASE_PATH, a DB-IP "IP to City Lite" CSV file. It is nil when
+// unset, and logins are then only told apart by device. main loads it at startup so a missing or broken
+// file stops the server.
+var GeoDatabase = sync.OnceValues(func() (*geoip.Database, error) {
+	path := os.Getenv("GEOIP_DATABASE_PATH")
+	if path == "" {
+		return nil, nil
+	}
+	return geoip.Open(path)
+})
+
+// loginDevice is the device a login comes from, where it is located and how risky the login is.
+type loginDevice struct {
+	fingerprint string
+	name        string
+	ip          string
+	location    *geoip.Location
+	assessment  loginrisk.Assessment
+}
+
+// LoginRiskSettings returns loginrisk.DefaultScorer adjusted by LOGIN_RISK_FAR_DISTANCE_KM, and
+// LOGIN_RISK_MFA_THRESHOLD, the score from which logins need a code emailed to the user, 0 to never ask.
+// main reads them at startup so a bad setting stops the server instead of failing every login.
+func LoginRiskSettings() (loginrisk.Scorer, int, error) {
+	scorer := loginrisk.DefaultScorer()
+	if value := os.Getenv("LOGIN_RISK_FAR_DISTANCE_KM"); value != "" {
+		distance, err := strconv.ParseFloat(value, 64)
+		if err == nil && !(distance > 0 && distance <= maxFarDistanceKm) {
+			err = fmt.Errorf("must be positive and at most %d, got %s", maxFarDistanceKm, value)
+		}
+		if err != nil {
+			return scorer, 0, fmt.Errorf("LOGIN_RISK_FAR_DISTANCE_KM: %w", err)
+		}
+		scorer.FarDistanceKm = distance
+	}
+
+	threshold, err := envInt("LOGIN_RISK_MFA_THRESHOLD", 0)
+	if err != nil {
+		return scorer, 0, err
+	}
+	if threshold < 0 {
+		return scorer, 0, fmt.Errorf("LOGIN_RISK_MFA_THRESHOLD: must not be negative, got %d", threshold)
+	}
+	return scorer, threshold, nil
+}
+
+// assessLogin scores a login of user from the device identified by deviceID and the client of ctx against
+// the devices they used before. It returns nil when the service keeps no devices.
+func (s *UserService) assessLogin(
+	ctx context.Context,
+	user *datastruct.User,
+	deviceID string,
+	recentFailures int,
+) (*loginDevice, error) {
+	if s.loginDeviceRepository == nil {
+		return nil, nil
+	}
+	scorer, _, err := LoginRiskSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	client := clientinfo.FromContext(ctx)
+	device := &loginDevice{
+		name: deviceName(client.UserAgent),
+		ip:   client.IP,
+	}
+	device.fingerprint = deviceFingerprint(deviceID, device.name)
+	device.location = locateIP(client.IP)
+
+	known, err := s.loginDeviceRepository.ListLoginDevices(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	history := make([]loginrisk.Sighting, len(known))
+	for i, d := range known {
+		history[i] = loginrisk.Sighting{Fingerprint: d.Fingerprint, SeenAt: d.LastSeenAt}
+		if d.Country != "" {
+			history[i].Location = &geoip.Location{
+				Country:   d.Country,
+				Region:    d.Region,
+				City:      d.City,
+				Latitude:  d.Latitude,
+				Longitude: d.Longitude,
+			}
+		}
+	}
+
+	device.assessment = scorer.Assess(loginrisk.Attempt{
+		Fingerprint:    device.fingerprint,
+		Location:       device.location,
+		At:             time.Now(),
+		RecentFailures: recentFailures,
+	}, history)
+	return device, nil
+}
+
+// locateIP looks ip up in GeoDatabase, nil when there is no database or the address is not in it. A
+// database that fails to load is logged rather than failing logins.
+func locateIP(ip string) *geoip.Location {
+	database, err := GeoDatabase()
+	if err != nil {
+		log.Printf("Failed to load the GeoIP database: %v", err)
+		return nil
+	}
+	if database == nil || ip == "" {
+		return nil
+	}
+	location, ok := database.Lookup(ip)
+	if !ok {
+		return nil
+	}
+	return &location
+}
+
+// challengeRequired reports whether the login from device is risky enough to need a code.
+func challengeRequired(device *loginDevice) (bool, error) {
+	if device == nil {
+		return false, nil
+	}
+	_, threshold, err := LoginRiskSettings()
+	if err != nil {
+		return false, err
+	}
+	return threshold > 0 && device.assessment.Score >= threshold, nil
+}
+
+// challengeLogin holds back the login of user from device and emails them a one-time code to complete it
+// with VerifyLoginChallenge from the same device. It replaces the previous challenge of the user, so only
+// the latest code works.
+func (s *UserService) challengeLogin(
+	ctx context.Context,
+	user *datastruct.User,
+	device *loginDevice,
+) (*dto.LoginResponse, error) {
+	now := time.Now()
+	if _, err := s.loginDeviceRepository.DeleteExpiredLoginChallenges(ctx, now); err != nil {
+		log.Printf("Failed to delete expired login challenges: %v", err)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(secret)
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return nil, err
+	}
+	code := fmt.Sprintf("%06d", n.Int64())
+
+	challenge := &datastruct.LoginChallenge{
+		TokenHash:   hashToken(token),
+		UserId:      user.ID,
+		CodeHash:    hashLoginCode(token, code),
+		Fingerprint: device.fingerprint,
+		ExpiredAt:   now.Add(loginChallengeTTL),
+	}
+	if err := s.loginDeviceRepository.CreateLoginChallenge(ctx, challenge); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.mailer.Send(&mail_server.SendEmailRequest{
+		From:    os.Getenv("EMAIL_SENDER"),
+		To:      []string{user.Email},
+		Subject: "Auth management - Your sign in code",
+		Html: "<p> Your sign in code is " + code + ". It expires in " +
+			strconv.Itoa(int(loginChallengeTTL.Minutes())) + " minutes. Someone signed in to your account " +
+			describeLogin(device) + ". If this was not you, reset your password : " + os.Getenv("BASE_URL") +
+			"/forgot-password</p>",
+	}); err != nil {
+		return nil, err
+	}
+	recordAudit(ctx, s.auditRecorder, &datastruct.AuditEvent{
+		OrganizationId: auditID(user.OrganizationId),
+		Type:           datastruct.AuditLoginChallenged,
+		Outcome:        datastruct.AuditSuccess,
+		TargetId:       auditID(user.ID),
+		Details:        loginRiskDetails(device),
+	})
+
+	return &dto.LoginResponse{MFARequired: true, MFAToken: token}, nil
+}
+
+// VerifyLoginChallenge completes a login held back by challengeLogin when the code is right and entered
+// from the device the login came from. Wrong codes count as failed logins towards the lockout.
+func (s *UserService) VerifyLoginChallenge(
+	ctx context.Context,
+	req dto.VerifyLoginRequest,
+) (*dto.LoginResponse, error) {
+	challenge, err := s.loginDeviceRepository.FindLoginChallengeByTokenHash(ctx, hashToken(req.Token))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrLoginChallengeInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if challenge.ExpiredAt.Before(time.Now()) {
+		s.dropLoginChallenge(ctx, challenge)
+		return nil, ErrLoginChallengeInvalid
+	}
+
+	attempts, err := s.loginDeviceRepository.RecordLoginChallengeAttempt(ctx, challenge.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrLoginChallengeInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepository.FindById(ctx, challenge.UserId)
+	if err != nil {
+		return nil, mapUserError(err)
+	}
+	if attempts > maxLoginChallengeAttempts {
+		s.dropLoginChallenge(ctx, challenge)
+		s.auditLoginFailure(ctx, user.Email, user, "too_many_codes")
+		return nil, ErrLoginChallengeInvalid
+	}
+
+	device, err := s.assessLogin(ctx, user, req.DeviceID, 0)
+	if err != nil {
+		return nil, err
+	}
+	codeHash := hashLoginCode(req.Token, req.Code)
+	if subtle.ConstantTimeCompare([]byte(codeHash), []byte(challenge.CodeHash)) != 1 ||
+		device.fingerprint != challenge.Fingerprint {
+		s.auditLoginFailure(ctx, user.Email, user, "invalid_code")
+		if err := recordFailedLogin(ctx, s.userRepository, s.mailer, user); err != nil {
+			return nil, err
+		}
+		return nil, ErrLoginChallengeInvalid
+	}
+	s.dropLoginChallenge(ctx, challenge)
+
+	if user.Locked(time.Now()) {
+		s.auditLoginFailure(ctx, user.Email, user, "locked")
+		return nil, ErrInvalidCredentials
+	}
+	if user.Disabled {
+		s.auditLoginFailure(ctx, user.Email, user, "disabled")
+		return nil, ErrInvalidCredentials
+	}
+	return s.completeLogin(ctx, user, device)
+}
+
+// dropLoginChallenge deletes a challenge that can no longer be completed. Failures are logged: expired
+// challenges are purged anyway.
+func (s *UserService) dropLoginChallenge(ctx context.Context, challenge *datastruct.LoginChallenge) {
+	if err := s.loginDeviceRepository.DeleteLoginChallenge(ctx, challenge.ID); err != nil {
+		log.Printf("Failed to delete login challenge %d: %v", challenge.ID, err)
+	}
+}
+
+// rememberDevice records that user logged in from device and alerts them when it is a new device or far
+// from where they logged in before. Failures are logged: the login itself succeeded.
+func (s *UserService) rememberDevice(ctx context.Context, user *datastruct.User, device *loginDevice) {
+	if device == nil {
+		return
+	}
+
+	saved := &datastruct.LoginDevice{
+		UserId:      user.ID,
+		Fingerprint: device.fingerprint,
+		Name:        device.name,
+		IP:          device.ip,
+		LastSeenAt:  time.Now(),
+	}
+	if device.location != nil {
+		saved.Country = device.location.Country
+		saved.Region = device.location.Region
+		saved.City = device.location.City
+		saved.Latitude = device.location.Latitude
+		saved.Longitude = device.location.Longitude
+	}
+	if err := s.loginDeviceRepository.SaveLoginDevice(ctx, saved); err != nil {
+		log.Printf("Failed to save the login device of user %d: %v", user.ID, err)
+	}
+
+	if !device.assessment.NewDevice && !device.assessment.FarLocation {
+		return
+	}
+	subject := "Auth management - New sign in to your account"
+	if !device.assessment.NewDevice {
+		subject = "Auth management - Sign in to your account from a new location"
+	}
+	if _, err := s.mailer.Send(&mail_server.SendEmailRequest{
+		From:    os.Getenv("EMAIL_SENDER"),
+		To:      []string{user.Email},
+		Subject: subject,
+		Html: "<p> Someone signed in to your account " + describeLogin(device) + ". If this was not you, " +
+			"reset your password : " + os.Getenv("BASE_URL") + "/forgot-password</p>",
+	}); err != nil {
+		log.Printf("Failed to send the login alert to user %d: %v", user.ID, err)
+	}
+}
+
+// describeLogin tells the user where a login came from, such as "from Firefox on Windows near Lyon,
+// Auvergne-Rhone-Alpes, FR (IP 203.0.113.7) on Mon, 02 Jan 2006 15:04:05 UTC", escaped for the HTML of emails.
+func describeLogin(device *loginDevice) string {
+	description := "from " + device.name
+	if device.location != nil {
+		description += " near " + device.location.String()
+	}
+	if device.ip != "" {
+		description += " (IP " + device.ip + ")"
+	}
+	return html.EscapeString(description + " on " + time.Now().UTC().Format(time.RFC1123))
+}
+
+// loginRiskDetails are the audit details of the risk of a login from device, none without a device.
+func loginRiskDetails(device *loginDevice) map[string]string {
+	if device == nil {
+		return nil
+	}
+	details := map[string]string{
+		"device":     device.name,
+		"risk_score": strconv.Itoa(device.assessment.Score),
+	}
+	if len(device.assessment.Reasons) > 0 {
+		details["risk_reasons"] = strings.Join(device.assessment.Reasons, ",")
+	}
+	if device.location != nil {
+		details["location"] = device.location.String()
+	}
+	return details
+}
+
+// deviceFingerprint identifies a device by the ID of its device cookie and the browser and system it
+// runs, so a stolen cookie replayed from another kind of device does not pass for it.
+func deviceFingerprint(deviceID string, name string) string {
+	sum := sha256.Sum256([]byte(deviceID + "\n" + name))
+	return hex.EncodeToString(sum[:])
+}
+
+// hashLoginCode hashes the code of a challenge together with its token, so equal codes hash differently.
+func hashLoginCode(token string, code string) string {
+	sum := sha256.Sum256([]byte(token + "\n" + code))
+	return hex.EncodeToString(sum[:])
+}
+
+// deviceName names the browser and operating system of userAgent, such as "Chrome on macOS". Versions are
+// left out so updates do not make a device look new.
+func deviceName(userAgent string) string {
+	if userAgent == "" {
+		return "Unknown device"
+	}
+
+	browser := "Unknown browser"
+	for _, candidate := range []struct{ token, name string }{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"CriOS/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"curl/", "curl"},
+	} {
+		if strings.Contains(userAgent, candidate.token) {
+			browser = candidate.name
+			break
+		}
+	}
+
+	system := "unknown system"
+	for _, candidate := range []struct{ token, name string }{
+		{"Windows", "Windows"},
+		{"Android", "Android"},
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"Mac OS X", "macOS"},
+		{"CrOS", "ChromeOS"},
+		{"Linux", "Linux"},
+	} {
+		if strings.Contains(userAgent, candidate.token) {
+			system = candidate.name
+			break
+		}
+	}
+	return browser + " on " + system
+}
@@ -6,6 +6,7 @@ import (
 	context "context"
 
 	dto "github.com/fyfirman/auth-management-go/internal/dto"
+	service "github.com/fyfirman/auth-management-go/internal/service"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -134,6 +135,66 @@ func (_m *UserServiceInterface) ResetPassword(ctx context.Context, req dto.Reset
 	return r0, r1
 }
 
+// SwitchOrganization provides a mock function with given fields: ctx, claims, organizationID
+func (_m *UserServiceInterface) SwitchOrganization(ctx context.Context, claims *service.Claims, organizationID uint) (*dto.LoginResponse, error) {
+	ret := _m.Called(ctx, claims, organizationID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SwitchOrganization")
+	}
+
+	var r0 *dto.LoginResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *service.Claims, uint) (*dto.LoginResponse, error)); ok {
+		return rf(ctx, claims, organizationID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *service.Claims, uint) *dto.LoginResponse); ok {
+		r0 = rf(ctx, claims, organizationID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.LoginResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *service.Claims, uint) error); ok {
+		r1 = rf(ctx, claims, organizationID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifyLoginChallenge provides a mock function with given fields: ctx, req
+func (_m *UserServiceInterface) VerifyLoginChallenge(ctx context.Context, req dto.VerifyLoginRequest) (*dto.LoginResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyLoginChallenge")
+	}
+
+	var r0 *dto.LoginResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.VerifyLoginRequest) (*dto.LoginResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.VerifyLoginRequest) *dto.LoginResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.LoginResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.VerifyLoginRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewUserServiceInterface creates a new instance of UserServiceInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserServiceInterface(t interface {
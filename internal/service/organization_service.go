@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+	"github.com/fyfirman/auth-management-go/internal/dto"
+	"github.com/fyfirman/auth-management-go/internal/repository"
+	"gorm.io/gorm"
+)
+
+type OrganizationServiceInterface interface {
+	CreateOrganization(ctx context.Context, req dto.CreateOrganizationRequest) (*dto.OrganizationResponse, error)
+	ListOrganizations(ctx context.Context) ([]dto.OrganizationResponse, error)
+	ListMemberships(ctx context.Context, claims *Claims) ([]dto.MembershipResponse, error)
+}
+
+type OrganizationService struct {
+	organizationRepository repository.OrganizationRepositoryInterface
+}
+
+func NewOrganizationService(organizationRepository repository.OrganizationRepositoryInterface) *OrganizationService {
+	return &OrganizationService{organizationRepository: organizationRepository}
+}
+
+func (s *OrganizationService) CreateOrganization(
+	ctx context.Context,
+	req dto.CreateOrganizationRequest,
+) (*dto.OrganizationResponse, error) {
+	organization := &datastruct.Organization{Name: req.Name, Slug: req.Slug}
+	if err := s.organizationRepository.CreateOrganization(ctx, organization); err != nil {
+		return nil, mapOrganizationError(err)
+	}
+	return dto.NewOrganizationResponse(organization), nil
+}
+
+func (s *OrganizationService) ListOrganizations(ctx context.Context) ([]dto.OrganizationResponse, error) {
+	organizations, err := s.organizationRepository.ListOrganizations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.OrganizationResponse, 0, len(organizations))
+	for i := range organizations {
+		response = append(response, *dto.NewOrganizationResponse(&organizations[i]))
+	}
+	return response, nil
+}
+
+// ListMemberships returns the organizations the caller belongs to, flagging the one the token is scoped to.
+func (s *OrganizationService) ListMemberships(ctx context.Context, claims *Claims) ([]dto.MembershipResponse, error) {
+	members, err := s.organizationRepository.ListMembershipsByUserId(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.MembershipResponse, 0, len(members))
+	for _, member := range members {
+		if member.Organization == nil {
+			continue
+		}
+		response = append(response, dto.MembershipResponse{
+			Organization: *dto.NewOrganizationResponse(member.Organization),
+			Role:         member.Role,
+			Active:       member.OrganizationId == claims.OrgID,
+		})
+	}
+	return response, nil
+}
+
+func mapOrganizationError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrOrganizationNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return ErrOrganizationConflict
+	default:
+		return err
+	}
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"encoding/base64"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/fyfirman/auth-management-go/pkg/password"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// passwordHashers is loaded once from PASSWORD_HASHER, "argon2id" (the default), "bcrypt" or "scrypt", and
+// the parameters of that algorithm. Hashes of the other algorithms, or with other parameters, still verify
+// and are replaced on the next successful login, as are the imported Django, salted SHA and, when
+// FIREBASE_SCRYPT_SIGNER_KEY is set, Firebase hashes. New hashes are peppered with the first of
+// passwordPeppers, if any.
+var passwordHashers = sync.OnceValues(func() (*password.Hashers, error) {
+	bcryptCost, err := envInt("PASSWORD_BCRYPT_COST", bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+	argon2Memory, err := envInt("PASSWORD_ARGON2_MEMORY", 19*1024)
+	if err != nil {
+		return nil, err
+	}
+	argon2Iterations, err := envInt("PASSWORD_ARGON2_ITERATIONS", 2)
+	if err != nil {
+		return nil, err
+	}
+	argon2Parallelism, err := envInt("PASSWORD_ARGON2_PARALLELISM", 1)
+	if err != nil {
+		return nil, err
+	}
+	scryptLogN, err := envInt("PASSWORD_SCRYPT_LOG_N", 15)
+	if err != nil {
+		return nil, err
+	}
+	scryptBlockSize, err := envInt("PASSWORD_SCRYPT_BLOCK_SIZE", 8)
+	if err != nil {
+		return nil, err
+	}
+	scryptParallelism, err := envInt("PASSWORD_SCRYPT_PARALLELISM", 1)
+	if err != nil {
+		return nil, err
+	}
+
+	hashers := map[string]password.Hasher{
+		"argon2id": password.Argon2id{
+			Memory:      uint32(argon2Memory),
+			Iterations:  uint32(argon2Iterations),
+			Parallelism: uint8(argon2Parallelism),
+		},
+		"bcrypt": password.Bcrypt{Cost: bcryptCost},
+		"scrypt": password.Scrypt{LogN: scryptLogN, BlockSize: scryptBlockSize, Parallelism: scryptParallelism},
+	}
+
+	name := os.Getenv("PASSWORD_HASHER")
+	if name == "" {
+		name = "argon2id"
+	}
+	preferred, ok := hashers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown PASSWORD_HASHER %q", name)
+	}
+
+	others := []password.Verifier{password.PBKDF2SHA256{}, password.SaltedSHA{}}
+	for other, hasher := range hashers {
+		if other != name {
+			others = append(others, hasher)
+		}
+	}
+	firebase, err := firebaseScrypt()
+	if err != nil {
+		return nil, err
+	}
+	if firebase != nil {
+		others = append(others, *firebase)
+	}
+	peppers, err := passwordPeppers()
+	if err != nil {
+		return nil, err
+	}
+	return password.NewHashers(preferred, others...).WithPeppers(peppers...), nil
+})
+
+// passwordPeppers reads the peppers from the file named by PASSWORD_PEPPERS_FILE, or else PASSWORD_PEPPERS,
+// as "<version>:<base64 key>" entries separated by newlines or commas. The first one peppers new hashes.
+func passwordPeppers() ([]password.Pepper, error) {
+	if path := os.Getenv("PASSWORD_PEPPERS_FILE"); path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		peppers, err := password.ParsePeppers(string(content))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return peppers, nil
+	}
+
+	peppers, err := password.ParsePeppers(os.Getenv("PASSWORD_PEPPERS"))
+	if err != nil {
+		return nil, fmt.Errorf("PASSWORD_PEPPERS: %w", err)
+	}
+	return peppers, nil
+}
+
+// firebaseScrypt reads the hash parameters of the Firebase project users are imported from, nil when
+// FIREBASE_SCRYPT_SIGNER_KEY is not set.
+func firebaseScrypt() (*password.FirebaseScrypt, error) {
+	encodedSignerKey := os.Getenv("FIREBASE_SCRYPT_SIGNER_KEY")
+	if encodedSignerKey == "" {
+		return nil, nil
+	}
+	signerKey, err := base64.StdEncoding.DecodeString(encodedSignerKey)
+	if err != nil {
+		return nil, fmt.Errorf("FIREBASE_SCRYPT_SIGNER_KEY: %w", err)
+	}
+	saltSeparator, err := base64.StdEncoding.DecodeString(os.Getenv("FIREBASE_SCRYPT_SALT_SEPARATOR"))
+	if err != nil {
+		return nil, fmt.Errorf("FIREBASE_SCRYPT_SALT_SEPARATOR: %w", err)
+	}
+	rounds, err := envInt("FIREBASE_SCRYPT_ROUNDS", 8)
+	if err != nil {
+		return nil, err
+	}
+	memCost, err := envInt("FIREBASE_SCRYPT_MEM_COST", 14)
+	if err != nil {
+		return nil, err
+	}
+	return &password.FirebaseScrypt{
+		SignerKey:     signerKey,
+		SaltSeparator: saltSeparator,
+		Rounds:        rounds,
+		MemCost:       memCost,
+	}, nil
+}
+
+// dummyPasswordHash hashes no account's password, compared against for unknown emails so they take as long
+// as known ones.
+var dummyPasswordHash = sync.OnceValue(func() string {
+	plain, err := generateForgotPasswordToken()
+	if err != nil {
+		return ""
+	}
+	hash, _ := hashPassword(plain)
+	return hash
+})
+
+func hashPassword(plain string) (string, error) {
+	hashers, err := passwordHashers()
+	if err != nil {
+		return "", err
+	}
+	return hashers.Hash(plain)
+}
+
+// verifyPassword reports whether plain matches the encoded hash, and whether the hash should be replaced
+// with one of the configured hasher. Hashes that cannot be verified never match.
+func verifyPassword(encoded string, plain string) (match bool, rehash bool) {
+	hashers, err := passwordHashers()
+	if err != nil {
+		log.Printf("Failed to load the password hashers: %v", err)
+		return false, false
+	}
+
+	match, rehash, err = hashers.Verify(plain, encoded)
+	if err != nil {
+		log.Printf("Failed to verify a password hash: %v", err)
+	}
+	return match, rehash
+}
+
+func envInt(name string, fallback int) (int, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", name, err)
+	}
+	return parsed, nil
+}
+
+// importPasswordHash encodes a hash exported from another system so verifyPassword checks it, refusing hashes
+// that fail Hashers.Check.
+func importPasswordHash(foreign password.ForeignHash) (string, error) {
+	hashers, err := passwordHashers()
+	if err != nil {
+		return "", err
+	}
+
+	encoded, err := foreign.Encode()
+	if err != nil {
+		return "", err
+	}
+	if err := hashers.Check(encoded); err != nil {
+		return "", err
+	}
+	return encoded, nil
+}
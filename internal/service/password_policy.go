@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+	"github.com/fyfirman/auth-management-go/internal/repository"
+	"github.com/fyfirman/auth-management-go/pkg"
+	"github.com/fyfirman/auth-management-go/pkg/password"
+)
+
+// breachChecker is loaded once from PWNED_PASSWORDS_PATH, a local copy of the Pwned Passwords corpus, or
+// else PWNED_PASSWORDS_API_URL, a range API such as https://api.pwnedpasswords.com/range/. It is nil when
+// neither is set.
+var breachChecker = sync.OnceValues(func() (password.BreachChecker, error) {
+	if path := os.Getenv("PWNED_PASSWORDS_PATH"); path != "" {
+		return password.OpenRangeFile(path)
+	}
+	if url := os.Getenv("PWNED_PASSWORDS_API_URL"); url != "" {
+		return password.NewRangeClient(url), nil
+	}
+	return nil, nil
+})
+
+// PasswordPolicy is password.DefaultPolicy adjusted by PASSWORD_MIN_LENGTH, PASSWORD_MIN_CHARACTER_CLASSES
+// and PASSWORD_MIN_ENTROPY_BITS, screening breached passwords with breachChecker. main loads it at startup
+// so a bad setting or corpus stops the server instead of failing every password change.
+func PasswordPolicy() (password.Policy, error) {
+	policy := password.DefaultPolicy()
+	breaches, err := breachChecker()
+	if err != nil {
+		return policy, err
+	}
+	policy.Breaches = breaches
+
+	if value := os.Getenv("PASSWORD_MIN_LENGTH"); value != "" {
+		minLength, err := strconv.Atoi(value)
+		if err != nil {
+			return policy, err
+		}
+		policy.MinLength = minLength
+	}
+	if value := os.Getenv("PASSWORD_MIN_CHARACTER_CLASSES"); value != "" {
+		classes, err := strconv.Atoi(value)
+		if err != nil {
+			return policy, err
+		}
+		policy.MinCharacterClasses = classes
+	}
+	if value := os.Getenv("PASSWORD_MIN_ENTROPY_BITS"); value != "" {
+		bits, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return policy, err
+		}
+		policy.MinEntropyBits = bits
+	}
+	return policy, nil
+}
+
+// checkPassword applies the password policy to a new password sent in the request field of that name. The
+// rules it breaks are returned as pkg.FieldErrors. identifiers are the username and emails of the account.
+// A failed breach lookup is logged and does not block the password.
+func checkPassword(ctx context.Context, field string, newPassword string, identifiers ...string) error {
+	policy, err := PasswordPolicy()
+	if err != nil {
+		return err
+	}
+
+	violations, err := policy.Check(ctx, newPassword, identifiers...)
+	if err != nil {
+		log.Printf("Failed to screen a password against data breaches: %v", err)
+	}
+
+	var fieldErrors pkg.FieldErrors
+	for _, violation := range violations {
+		fieldErrors = append(fieldErrors, pkg.FieldError{Field: field, Message: field + " " + violation.Message})
+	}
+	if len(fieldErrors) > 0 {
+		return fieldErrors
+	}
+	return nil
+}
+
+// passwordHistorySize reads PASSWORD_HISTORY, how many of their last passwords users cannot choose again.
+// 0, the default, keeps no history.
+func passwordHistorySize() (int, error) {
+	return envInt("PASSWORD_HISTORY", 0)
+}
+
+// passwordMaxAge reads PASSWORD_MAX_AGE_DAYS, after how many days a password must be changed. 0, the
+// default, never expires passwords.
+func passwordMaxAge() (time.Duration, error) {
+	days, err := envInt("PASSWORD_MAX_AGE_DAYS", 0)
+	return time.Duration(days) * 24 * time.Hour, err
+}
+
+// passwordExpired reports whether the password of user is older than the maximum age at now.
+func passwordExpired(user *datastruct.User, now time.Time) (bool, error) {
+	maxAge, err := passwordMaxAge()
+	if err != nil || maxAge <= 0 {
+		return false, err
+	}
+	return now.Sub(user.PasswordChangedAt) > maxAge, nil
+}
+
+// setPassword applies the password policy and the password history to a password the user chose in the
+// request field of that name, then stores it. The current password counts as part of the history, which
+// accounts changed before the history was kept do not have yet.
+func setPassword(
+	ctx context.Context,
+	userRepository repository.UserRepositoryInterface,
+	user *datastruct.User,
+	field string,
+	newPassword string,
+) error {
+	if err := checkPassword(ctx, field, newPassword, user.Username, user.Email); err != nil {
+		return err
+	}
+
+	historySize, err := passwordHistorySize()
+	if err != nil {
+		return err
+	}
+	if historySize > 0 {
+		history, err := userRepository.ListPasswordHistory(ctx, user.ID, historySize)
+		if err != nil {
+			return err
+		}
+		hashes := []string{user.PasswordHash}
+		for _, previous := range history {
+			hashes = append(hashes, previous.PasswordHash)
+		}
+		for _, hash := range hashes {
+			if match, _ := verifyPassword(hash, newPassword); match {
+				return pkg.FieldErrors{{
+					Field:   field,
+					Message: fmt.Sprintf("%s must differ from your last %d passwords", field, historySize),
+				}}
+			}
+		}
+	}
+
+	hashedPassword, err := hashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	return userRepository.ChangePasswordById(ctx, user.ID, hashedPassword, historySize)
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+	"github.com/fyfirman/auth-management-go/internal/dto"
+	"github.com/fyfirman/auth-management-go/internal/repository"
+	"gorm.io/gorm"
+)
+
+type RoleServiceInterface interface {
+	ListRoles(ctx context.Context) ([]dto.RoleResponse, error)
+	GetRole(ctx context.Context, id uint) (*dto.RoleResponse, error)
+	CreateRole(ctx context.Context, req dto.CreateRoleRequest) (*dto.RoleResponse, error)
+	UpdateRole(ctx context.Context, id uint, req dto.UpdateRoleRequest) (*dto.RoleResponse, error)
+	DeleteRole(ctx context.Context, id uint) error
+	ListPermissions(ctx context.Context) ([]dto.PermissionResponse, error)
+	CreatePermission(ctx context.Context, req dto.CreatePermissionRequest) (*dto.PermissionResponse, error)
+}
+
+type RoleService struct {
+	roleRepository repository.RoleRepositoryInterface
+}
+
+func NewRoleService(roleRepository repository.RoleRepositoryInterface) *RoleService {
+	return &RoleService{roleRepository: roleRepository}
+}
+
+func (s *RoleService) ListRoles(ctx context.Context) ([]dto.RoleResponse, error) {
+	roles, err := s.roleRepository.ListRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.RoleResponse, 0, len(roles))
+	for i := range roles {
+		response = append(response, *dto.NewRoleResponse(&roles[i]))
+	}
+	return response, nil
+}
+
+func (s *RoleService) GetRole(ctx context.Context, id uint) (*dto.RoleResponse, error) {
+	role, err := s.roleRepository.FindRoleById(ctx, id)
+	if err != nil {
+		return nil, mapRoleError(err)
+	}
+	return dto.NewRoleResponse(role), nil
+}
+
+func (s *RoleService) CreateRole(ctx context.Context, req dto.CreateRoleRequest) (*dto.RoleResponse, error) {
+	permissions, err := resolvePermissions(ctx, s.roleRepository, req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &datastruct.Role{
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: permissions,
+	}
+	if err := s.roleRepository.CreateRole(ctx, role); err != nil {
+		return nil, mapRoleError(err)
+	}
+	return dto.NewRoleResponse(role), nil
+}
+
+// UpdateRole changes the description and permission set of a role. The superadmin role keeps every permission,
+// so only its description can be edited.
+func (s *RoleService) UpdateRole(ctx context.Context, id uint, req dto.UpdateRoleRequest) (*dto.RoleResponse, error) {
+	role, err := s.roleRepository.FindRoleById(ctx, id)
+	if err != nil {
+		return nil, mapRoleError(err)
+	}
+
+	if req.Description != nil {
+		role.Description = *req.Description
+	}
+	if req.Permissions != nil {
+		if role.Name == datastruct.SuperAdmin.String() {
+			return nil, ErrRoleBuiltIn
+		}
+		if role.Permissions, err = resolvePermissions(ctx, s.roleRepository, req.Permissions); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.roleRepository.SaveRole(ctx, role); err != nil {
+		return nil, mapRoleError(err)
+	}
+	return dto.NewRoleResponse(role), nil
+}
+
+func (s *RoleService) DeleteRole(ctx context.Context, id uint) error {
+	role, err := s.roleRepository.FindRoleById(ctx, id)
+	if err != nil {
+		return mapRoleError(err)
+	}
+	if role.BuiltIn {
+		return ErrRoleBuiltIn
+	}
+	return mapRoleError(s.roleRepository.DeleteRoleById(ctx, id))
+}
+
+func (s *RoleService) ListPermissions(ctx context.Context) ([]dto.PermissionResponse, error) {
+	permissions, err := s.roleRepository.ListPermissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.PermissionResponse, 0, len(permissions))
+	for _, permission := range permissions {
+		response = append(response, dto.PermissionResponse{
+			ID:          int64(permission.ID),
+			Name:        permission.Name,
+			Description: permission.Description,
+		})
+	}
+	return response, nil
+}
+
+func (s *RoleService) CreatePermission(
+	ctx context.Context,
+	req dto.CreatePermissionRequest,
+) (*dto.PermissionResponse, error) {
+	permission := &datastruct.Permission{Name: req.Name, Description: req.Description}
+	if err := s.roleRepository.CreatePermission(ctx, permission); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrPermissionConflict
+		}
+		return nil, err
+	}
+
+	return &dto.PermissionResponse{
+		ID:          int64(permission.ID),
+		Name:        permission.Name,
+		Description: permission.Description,
+	}, nil
+}
+
+// resolvePermissions loads the permissions by name and fails when any of them is unknown.
+func resolvePermissions(
+	ctx context.Context,
+	roleRepository repository.RoleRepositoryInterface,
+	names []string,
+) ([]datastruct.Permission, error) {
+	if len(names) == 0 {
+		return []datastruct.Permission{}, nil
+	}
+
+	permissions, err := roleRepository.FindPermissionsByNames(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+
+	unique := make(map[string]bool, len(names))
+	for _, name := range names {
+		unique[name] = true
+	}
+	if len(permissions) != len(unique) {
+		return nil, ErrPermissionNotFound
+	}
+	return permissions, nil
+}
+
+func mapRoleError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrRoleNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return ErrRoleConflict
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return ErrRoleInUse
+	default:
+		return err
+	}
+}
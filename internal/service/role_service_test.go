@@ -0,0 +1,96 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+	"github.com/fyfirman/auth-management-go/internal/dto"
+	"github.com/fyfirman/auth-management-go/internal/repository/mocks"
+	"github.com/fyfirman/auth-management-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestRoleService_CreateRole(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("success", func(t *testing.T) {
+		roleRepository := new(mocks.RoleRepositoryInterface)
+		roleService := service.NewRoleService(roleRepository)
+
+		names := []string{datastruct.PermissionUsersRead}
+		roleRepository.Mock.On("FindPermissionsByNames", ctx, names).
+			Return([]datastruct.Permission{{ID: 1, Name: datastruct.PermissionUsersRead}}, nil)
+		roleRepository.Mock.On("CreateRole", ctx, mock.AnythingOfType("*datastruct.Role")).Return(nil)
+
+		res, err := roleService.CreateRole(ctx, dto.CreateRoleRequest{Name: "support", Permissions: names})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "support", res.Name)
+		assert.Equal(t, names, res.Permissions)
+	})
+
+	t.Run("unknown permission", func(t *testing.T) {
+		roleRepository := new(mocks.RoleRepositoryInterface)
+		roleService := service.NewRoleService(roleRepository)
+
+		names := []string{datastruct.PermissionUsersRead, "billing:read"}
+		roleRepository.Mock.On("FindPermissionsByNames", ctx, names).
+			Return([]datastruct.Permission{{ID: 1, Name: datastruct.PermissionUsersRead}}, nil)
+
+		res, err := roleService.CreateRole(ctx, dto.CreateRoleRequest{Name: "support", Permissions: names})
+
+		assert.Nil(t, res)
+		assert.ErrorIs(t, err, service.ErrPermissionNotFound)
+		roleRepository.Mock.AssertNotCalled(t, "CreateRole")
+	})
+}
+
+func TestRoleService_UpdateRole_SuperAdminPermissionsAreFixed(t *testing.T) {
+	roleRepository := new(mocks.RoleRepositoryInterface)
+	roleService := service.NewRoleService(roleRepository)
+
+	ctx := context.TODO()
+	roleRepository.Mock.On("FindRoleById", ctx, uint(1)).
+		Return(&datastruct.Role{ID: 1, Name: "superadmin", BuiltIn: true}, nil)
+
+	res, err := roleService.UpdateRole(ctx, 1, dto.UpdateRoleRequest{Permissions: []string{}})
+
+	assert.Nil(t, res)
+	assert.ErrorIs(t, err, service.ErrRoleBuiltIn)
+}
+
+func TestRoleService_DeleteRole(t *testing.T) {
+	ctx := context.TODO()
+
+	cases := []struct {
+		name        string
+		role        *datastruct.Role
+		deleteErr   error
+		expectedErr error
+	}{
+		{"custom role", &datastruct.Role{ID: 4, Name: "support"}, nil, nil},
+		{"built-in role", &datastruct.Role{ID: 2, Name: "admin", BuiltIn: true}, nil, service.ErrRoleBuiltIn},
+		{"role in use", &datastruct.Role{ID: 4, Name: "support"}, gorm.ErrForeignKeyViolated, service.ErrRoleInUse},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			roleRepository := new(mocks.RoleRepositoryInterface)
+			roleService := service.NewRoleService(roleRepository)
+
+			roleRepository.Mock.On("FindRoleById", ctx, c.role.ID).Return(c.role, nil)
+			roleRepository.Mock.On("DeleteRoleById", ctx, c.role.ID).Return(c.deleteErr)
+
+			err := roleService.DeleteRole(ctx, c.role.ID)
+
+			if c.expectedErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, c.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,725 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+	"github.com/fyfirman/auth-management-go/internal/dto"
+	"github.com/fyfirman/auth-management-go/internal/repository"
+	"github.com/fyfirman/auth-management-go/internal/tenant"
+	"github.com/fyfirman/auth-management-go/pkg"
+	"github.com/fyfirman/auth-management-go/pkg/scim"
+	"gorm.io/gorm"
+)
+
+// SCIMMaxResults caps the page size of SCIM list requests.
+const SCIMMaxResults = 100
+
+// SCIMServiceInterface serves the SCIM resources of the organization ctx is scoped to, as set by the SCIM
+// bearer token. Provisioned users join the organization as general users; roles come from the groups
+// admins configure.
+type SCIMServiceInterface interface {
+	Authenticate(ctx context.Context, token string) (uint, error)
+	CreateToken(ctx context.Context, actor *Claims, req dto.CreateSCIMTokenRequest) (*dto.SCIMTokenResponse, error)
+	ListTokens(ctx context.Context, actor *Claims) ([]dto.SCIMTokenResponse, error)
+	DeleteToken(ctx context.Context, actor *Claims, id uint) error
+
+	ListUsers(ctx context.Context, query dto.SCIMListQuery) (*scim.ListResponse, error)
+	GetUser(ctx context.Context, id string) (*dto.SCIMUser, error)
+	CreateUser(ctx context.Context, resource dto.SCIMUser) (*dto.SCIMUser, error)
+	ReplaceUser(ctx context.Context, id string, resource dto.SCIMUser) (*dto.SCIMUser, error)
+	PatchUser(ctx context.Context, id string, req scim.PatchRequest) (*dto.SCIMUser, error)
+	DeleteUser(ctx context.Context, id string) error
+
+	ListGroups(ctx context.Context, query dto.SCIMListQuery) (*scim.ListResponse, error)
+	GetGroup(ctx context.Context, id string) (*dto.SCIMGroup, error)
+	CreateGroup(ctx context.Context, resource dto.SCIMGroup) (*dto.SCIMGroup, error)
+	ReplaceGroup(ctx context.Context, id string, resource dto.SCIMGroup) (*dto.SCIMGroup, error)
+	PatchGroup(ctx context.Context, id string, req scim.PatchRequest) (*dto.SCIMGroup, error)
+	DeleteGroup(ctx context.Context, id string) error
+}
+
+type SCIMService struct {
+	scimTokenRepository    repository.ScimTokenRepositoryInterface
+	userRepository         repository.UserRepositoryInterface
+	groupRepository        repository.GroupRepositoryInterface
+	organizationRepository repository.OrganizationRepositoryInterface
+	sessionRepository      repository.SessionRepositoryInterface
+	auditRecorder          AuditRecorder
+}
+
+func NewSCIMService(
+	scimTokenRepository repository.ScimTokenRepositoryInterface,
+	userRepository repository.UserRepositoryInterface,
+	groupRepository repository.GroupRepositoryInterface,
+	organizationRepository repository.OrganizationRepositoryInterface,
+	sessionRepository repository.SessionRepositoryInterface,
+	auditRecorder AuditRecorder,
+) *SCIMService {
+	return &SCIMService{
+		scimTokenRepository:    scimTokenRepository,
+		userRepository:         userRepository,
+		groupRepository:        groupRepository,
+		organizationRepository: organizationRepository,
+		sessionRepository:      sessionRepository,
+		auditRecorder:          auditRecorder,
+	}
+}
+
+// Authenticate returns the organization a SCIM bearer token belongs to.
+func (s *SCIMService) Authenticate(ctx context.Context, token string) (uint, error) {
+	scimToken, err := s.scimTokenRepository.FindScimTokenByHash(ctx, hashToken(token))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, ErrInvalidSCIMToken
+	}
+	if err != nil {
+		return 0, err
+	}
+	return scimToken.OrganizationId, nil
+}
+
+// CreateToken issues a SCIM token for the actor's organization. The token is only returned here.
+func (s *SCIMService) CreateToken(
+	ctx context.Context,
+	actor *Claims,
+	req dto.CreateSCIMTokenRequest,
+) (*dto.SCIMTokenResponse, error) {
+	if actor.OrgID == 0 {
+		return nil, ErrNoOrganization
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	token := "scim_" + base64.RawURLEncoding.EncodeToString(secret)
+
+	scimToken := &datastruct.ScimToken{
+		OrganizationId: actor.OrgID,
+		Description:    req.Description,
+		TokenHash:      hashToken(token),
+		CreatedBy:      actor.UserID,
+	}
+	if err := s.scimTokenRepository.CreateScimToken(ctx, scimToken); err != nil {
+		return nil, err
+	}
+
+	response := newSCIMTokenResponse(scimToken)
+	response.Token = token
+	return response, nil
+}
+
+func (s *SCIMService) ListTokens(ctx context.Context, actor *Claims) ([]dto.SCIMTokenResponse, error) {
+	if actor.OrgID == 0 {
+		return nil, ErrNoOrganization
+	}
+
+	tokens, err := s.scimTokenRepository.ListScimTokens(ctx, actor.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.SCIMTokenResponse, 0, len(tokens))
+	for i := range tokens {
+		response = append(response, *newSCIMTokenResponse(&tokens[i]))
+	}
+	return response, nil
+}
+
+func (s *SCIMService) DeleteToken(ctx context.Context, actor *Claims, id uint) error {
+	if actor.OrgID == 0 {
+		return ErrNoOrganization
+	}
+
+	err := s.scimTokenRepository.DeleteScimTokenById(ctx, actor.OrgID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrSCIMTokenNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.auditRecorder, &datastruct.AuditEvent{
+		OrganizationId: auditID(actor.OrgID),
+		Type:           datastruct.AuditScimTokenRevoked,
+		Outcome:        datastruct.AuditSuccess,
+		ActorId:        auditID(actor.UserID),
+		Details:        map[string]string{"scim_token_id": strconv.FormatUint(uint64(id), 10)},
+	})
+	return nil
+}
+
+func (s *SCIMService) ListUsers(ctx context.Context, query dto.SCIMListQuery) (*scim.ListResponse, error) {
+	if _, err := scimOrganization(ctx); err != nil {
+		return nil, err
+	}
+	filter, startIndex, count, err := parseSCIMListQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	users, total, err := s.userRepository.ListUsers(ctx, repository.UserFilter{
+		SCIM:   filter,
+		Limit:  max(count, 1),
+		Offset: startIndex - 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]dto.SCIMUser, 0, len(users))
+	for i := 0; i < len(users) && i < count; i++ {
+		resource, err := s.userResource(ctx, &users[i])
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, *resource)
+	}
+	return newSCIMListResponse(total, startIndex, len(resources), resources), nil
+}
+
+func (s *SCIMService) GetUser(ctx context.Context, id string) (*dto.SCIMUser, error) {
+	user, err := s.findUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.userResource(ctx, user)
+}
+
+func (s *SCIMService) CreateUser(ctx context.Context, resource dto.SCIMUser) (*dto.SCIMUser, error) {
+	organizationID, err := scimOrganization(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &datastruct.User{OrganizationId: organizationID, Role: datastruct.GeneralUser.String()}
+	if err := applySCIMUser(ctx, user, resource); err != nil {
+		return nil, err
+	}
+	if resource.Password == "" {
+		// Provisioned users sign in through their identity provider until they set a password.
+		placeholder, err := generateForgotPasswordToken()
+		if err != nil {
+			return nil, err
+		}
+		if user.PasswordHash, err = hashPassword(placeholder); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.userRepository.CreateUser(ctx, user); err != nil {
+		return nil, mapUserError(err)
+	}
+	return s.userResource(ctx, user)
+}
+
+// ReplaceUser overwrites the user with resource. An omitted active attribute keeps the current state.
+func (s *SCIMService) ReplaceUser(ctx context.Context, id string, resource dto.SCIMUser) (*dto.SCIMUser, error) {
+	user, err := s.findUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.updateUser(ctx, user, resource); err != nil {
+		return nil, err
+	}
+	return s.userResource(ctx, user)
+}
+
+func (s *SCIMService) PatchUser(ctx context.Context, id string, req scim.PatchRequest) (*dto.SCIMUser, error) {
+	user, err := s.findUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.userResource(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	var patched dto.SCIMUser
+	if err := patchResource(current, req, &patched); err != nil {
+		return nil, err
+	}
+	if err := s.updateUser(ctx, user, patched); err != nil {
+		return nil, err
+	}
+	return s.userResource(ctx, user)
+}
+
+// DeleteUser deletes users whose home is the token's organization. Members from other organizations only
+// lose their membership of it.
+func (s *SCIMService) DeleteUser(ctx context.Context, id string) error {
+	user, err := s.findUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	organizationID, err := scimOrganization(ctx)
+	if err != nil {
+		return err
+	}
+	if user.OrganizationId != organizationID {
+		if err := s.organizationRepository.RemoveMember(ctx, organizationID, user.ID); err != nil {
+			return mapUserError(err)
+		}
+		if err := s.sessionRepository.RevokeSessionsByUserId(ctx, user.ID, ""); err != nil {
+			return err
+		}
+		auditSessionsRevoked(ctx, s.auditRecorder, nil, user.ID, "membership_removed", "")
+		return nil
+	}
+	if err := s.userRepository.DeleteUserById(ctx, user.ID); err != nil {
+		return mapUserError(err)
+	}
+	auditSessionsRevoked(ctx, s.auditRecorder, nil, user.ID, "account_deletion", "")
+	return nil
+}
+
+func (s *SCIMService) ListGroups(ctx context.Context, query dto.SCIMListQuery) (*scim.ListResponse, error) {
+	organizationID, err := scimOrganization(ctx)
+	if err != nil {
+		return nil, err
+	}
+	filter, startIndex, count, err := parseSCIMListQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, total, err := s.groupRepository.FindGroups(ctx, repository.GroupFilter{
+		OrganizationID: organizationID,
+		SCIM:           filter,
+		Limit:          max(count, 1),
+		Offset:         startIndex - 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]dto.SCIMGroup, 0, len(groups))
+	for i := 0; i < len(groups) && i < count; i++ {
+		resource, err := s.groupResource(ctx, &groups[i])
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, *resource)
+	}
+	return newSCIMListResponse(total, startIndex, len(resources), resources), nil
+}
+
+func (s *SCIMService) GetGroup(ctx context.Context, id string) (*dto.SCIMGroup, error) {
+	group, err := s.findGroup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.groupResource(ctx, group)
+}
+
+func (s *SCIMService) CreateGroup(ctx context.Context, resource dto.SCIMGroup) (*dto.SCIMGroup, error) {
+	organizationID, err := scimOrganization(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if resource.DisplayName == "" {
+		return nil, fmt.Errorf("%w: displayName is required", scim.ErrInvalidValue)
+	}
+
+	group := &datastruct.Group{
+		OrganizationId: organizationID,
+		ExternalId:     resource.ExternalID,
+		Name:           resource.DisplayName,
+	}
+	if err := s.groupRepository.CreateGroup(ctx, group); err != nil {
+		return nil, mapGroupError(err)
+	}
+	if err := s.syncGroupMembers(ctx, group, nil, resource.Members); err != nil {
+		return nil, err
+	}
+	return s.groupResource(ctx, group)
+}
+
+func (s *SCIMService) ReplaceGroup(ctx context.Context, id string, resource dto.SCIMGroup) (*dto.SCIMGroup, error) {
+	group, err := s.findGroup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.groupRepository.ListGroupMembers(ctx, group.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.updateGroup(ctx, group, members, resource); err != nil {
+		return nil, err
+	}
+	return s.groupResource(ctx, group)
+}
+
+func (s *SCIMService) PatchGroup(ctx context.Context, id string, req scim.PatchRequest) (*dto.SCIMGroup, error) {
+	group, err := s.findGroup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.groupRepository.ListGroupMembers(ctx, group.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var patched dto.SCIMGroup
+	if err := patchResource(newSCIMGroup(group, members), req, &patched); err != nil {
+		return nil, err
+	}
+	if err := s.updateGroup(ctx, group, members, patched); err != nil {
+		return nil, err
+	}
+	return s.groupResource(ctx, group)
+}
+
+func (s *SCIMService) DeleteGroup(ctx context.Context, id string) error {
+	group, err := s.findGroup(ctx, id)
+	if err != nil {
+		return err
+	}
+	members, err := s.groupRepository.ListGroupMembers(ctx, group.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.groupRepository.DeleteGroupById(ctx, group.OrganizationId, group.ID); err != nil {
+		return mapGroupError(err)
+	}
+	return revokeGrantSessions(ctx, s.sessionRepository, s.auditRecorder, nil, "groups_changed", userIDs(members)...)
+}
+
+func (s *SCIMService) findUser(ctx context.Context, id string) (*datastruct.User, error) {
+	if _, err := scimOrganization(ctx); err != nil {
+		return nil, err
+	}
+	userID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	user, err := s.userRepository.FindById(ctx, uint(userID))
+	if err != nil {
+		return nil, mapUserError(err)
+	}
+	return user, nil
+}
+
+func (s *SCIMService) findGroup(ctx context.Context, id string) (*datastruct.Group, error) {
+	organizationID, err := scimOrganization(ctx)
+	if err != nil {
+		return nil, err
+	}
+	groupID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, ErrGroupNotFound
+	}
+
+	group, err := s.groupRepository.FindGroupById(ctx, organizationID, uint(groupID))
+	if err != nil {
+		return nil, mapGroupError(err)
+	}
+	return group, nil
+}
+
+func (s *SCIMService) userResource(ctx context.Context, user *datastruct.User) (*dto.SCIMUser, error) {
+	organizationID, err := scimOrganization(ctx)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := s.groupRepository.ListGroupsByUserId(ctx, organizationID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	id := strconv.FormatUint(uint64(user.ID), 10)
+	active := scim.Boolean(!user.Disabled)
+	resource := &dto.SCIMUser{
+		Schemas:    []string{scim.SchemaUser},
+		ID:         id,
+		ExternalID: user.ExternalId,
+		UserName:   user.Username,
+		Emails:     []dto.SCIMEmail{{Value: user.Email, Type: "work", Primary: true}},
+		Active:     &active,
+		Meta:       newSCIMMeta("User", "/scim/v2/Users/"+id, user.CreatedAt, user.UpdatedAt),
+	}
+	for _, group := range groups {
+		groupID := strconv.FormatUint(uint64(group.ID), 10)
+		resource.Groups = append(resource.Groups, dto.SCIMMember{
+			Value:   groupID,
+			Display: group.Name,
+			Ref:     scimLocation("/scim/v2/Groups/" + groupID),
+		})
+	}
+	return resource, nil
+}
+
+func (s *SCIMService) groupResource(ctx context.Context, group *datastruct.Group) (*dto.SCIMGroup, error) {
+	members, err := s.groupRepository.ListGroupMembers(ctx, group.ID)
+	if err != nil {
+		return nil, err
+	}
+	return newSCIMGroup(group, members), nil
+}
+
+// updateUser applies resource to user and saves it. The account is shared by every organization the user
+// belongs to, so only the token of its home organization may change it; for members from other
+// organizations a resource that changes anything fails with ErrNotHomeOrganization. Deactivating a user
+// signs them out of every session.
+func (s *SCIMService) updateUser(ctx context.Context, user *datastruct.User, resource dto.SCIMUser) error {
+	organizationID, err := scimOrganization(ctx)
+	if err != nil {
+		return err
+	}
+
+	updated := *user
+	if err := applySCIMUser(ctx, &updated, resource); err != nil {
+		return err
+	}
+	if user.OrganizationId != organizationID {
+		if updated.Username != user.Username || updated.Email != user.Email || updated.ExternalId != user.ExternalId ||
+			updated.Disabled != user.Disabled || updated.PasswordHash != user.PasswordHash {
+			return ErrNotHomeOrganization
+		}
+		return nil
+	}
+
+	if err := s.userRepository.UpdateUser(ctx, &updated); err != nil {
+		return mapUserError(err)
+	}
+	if updated.Disabled && !user.Disabled {
+		if err := s.sessionRepository.RevokeSessionsByUserId(ctx, user.ID, ""); err != nil {
+			return err
+		}
+		auditSessionsRevoked(ctx, s.auditRecorder, nil, user.ID, "account_disabled", "")
+	}
+	*user = updated
+	return nil
+}
+
+// updateGroup saves the name and external id of resource and makes its members the members of the group.
+func (s *SCIMService) updateGroup(
+	ctx context.Context,
+	group *datastruct.Group,
+	members []datastruct.User,
+	resource dto.SCIMGroup,
+) error {
+	if resource.DisplayName == "" {
+		return fmt.Errorf("%w: displayName is required", scim.ErrInvalidValue)
+	}
+
+	if group.Name != resource.DisplayName || group.ExternalId != resource.ExternalID {
+		group.Name = resource.DisplayName
+		group.ExternalId = resource.ExternalID
+		if err := s.groupRepository.SaveGroup(ctx, group); err != nil {
+			return mapGroupError(err)
+		}
+	}
+	return s.syncGroupMembers(ctx, group, members, resource.Members)
+}
+
+// syncGroupMembers adds and removes members so the group holds exactly desired, signing out the members it
+// adds or removes. Members must belong to the organization.
+func (s *SCIMService) syncGroupMembers(
+	ctx context.Context,
+	group *datastruct.Group,
+	current []datastruct.User,
+	desired []dto.SCIMMember,
+) error {
+	wanted := make(map[uint]bool, len(desired))
+	for _, member := range desired {
+		userID, err := strconv.ParseUint(member.Value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: unknown member %q", scim.ErrInvalidValue, member.Value)
+		}
+		wanted[uint(userID)] = true
+	}
+
+	var changed []uint
+	for _, user := range current {
+		if wanted[user.ID] {
+			delete(wanted, user.ID)
+			continue
+		}
+		err := s.groupRepository.RemoveGroupMember(ctx, group.ID, user.ID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		changed = append(changed, user.ID)
+	}
+
+	for userID := range wanted {
+		if _, err := s.userRepository.FindById(ctx, userID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: unknown member %q", scim.ErrInvalidValue, strconv.FormatUint(uint64(userID), 10))
+			}
+			return err
+		}
+		err := s.groupRepository.AddGroupMember(ctx, &datastruct.GroupMember{GroupId: group.ID, UserId: userID})
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		changed = append(changed, userID)
+	}
+	return revokeGrantSessions(ctx, s.sessionRepository, s.auditRecorder, nil, "groups_changed", changed...)
+}
+
+// applySCIMUser copies the writable attributes of resource onto user. The primary email, or the first one,
+// becomes the account email. Passwords must satisfy the password policy.
+func applySCIMUser(ctx context.Context, user *datastruct.User, resource dto.SCIMUser) error {
+	if resource.UserName == "" || len(resource.UserName) > 255 {
+		return fmt.Errorf("%w: userName is required and at most 255 characters", scim.ErrInvalidValue)
+	}
+	if len(resource.Emails) == 0 {
+		return fmt.Errorf("%w: an email is required", scim.ErrInvalidValue)
+	}
+
+	email := resource.Emails[0].Value
+	for _, candidate := range resource.Emails {
+		if candidate.Primary {
+			email = candidate.Value
+			break
+		}
+	}
+	if _, err := mail.ParseAddress(email); err != nil {
+		return fmt.Errorf("%w: invalid email %q", scim.ErrInvalidValue, email)
+	}
+
+	user.Username = resource.UserName
+	user.Email = email
+	user.ExternalId = resource.ExternalID
+	if resource.Active != nil {
+		user.Disabled = !bool(*resource.Active)
+	}
+	if resource.Password != "" {
+		err := checkPassword(ctx, "password", resource.Password, resource.UserName, email)
+		var violations pkg.FieldErrors
+		if errors.As(err, &violations) {
+			return fmt.Errorf("%w: %s", scim.ErrInvalidValue, violations.Error())
+		}
+		if err != nil {
+			return err
+		}
+		hashedPassword, err := hashPassword(resource.Password)
+		if err != nil {
+			return err
+		}
+		user.PasswordHash = hashedPassword
+	}
+	return nil
+}
+
+// patchResource applies req to the JSON form of resource and decodes the result into patched.
+func patchResource(resource interface{}, req scim.PatchRequest, patched interface{}) error {
+	encoded, err := json.Marshal(resource)
+	if err != nil {
+		return err
+	}
+	var document map[string]interface{}
+	if err := json.Unmarshal(encoded, &document); err != nil {
+		return err
+	}
+
+	if err := req.Apply(document); err != nil {
+		return err
+	}
+
+	encoded, err = json.Marshal(document)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(encoded, patched); err != nil {
+		return fmt.Errorf("%w: %s", scim.ErrInvalidValue, err.Error())
+	}
+	return nil
+}
+
+// parseSCIMListQuery applies the defaults of RFC 7644: startIndex starts at 1 and count is capped at
+// SCIMMaxResults.
+func parseSCIMListQuery(query dto.SCIMListQuery) (scim.Filter, int, int, error) {
+	var filter scim.Filter
+	if query.Filter != "" {
+		var err error
+		if filter, err = scim.ParseFilter(query.Filter); err != nil {
+			return nil, 0, 0, err
+		}
+	}
+
+	startIndex := max(query.StartIndex, 1)
+	count := query.Count
+	if count < 0 {
+		count = 0
+	}
+	if count > SCIMMaxResults {
+		count = SCIMMaxResults
+	}
+	return filter, startIndex, count, nil
+}
+
+func scimOrganization(ctx context.Context) (uint, error) {
+	organizationID, ok := tenant.OrganizationFromContext(ctx)
+	if !ok {
+		return 0, ErrNoOrganization
+	}
+	return organizationID, nil
+}
+
+func newSCIMGroup(group *datastruct.Group, members []datastruct.User) *dto.SCIMGroup {
+	id := strconv.FormatUint(uint64(group.ID), 10)
+	resource := &dto.SCIMGroup{
+		Schemas:     []string{scim.SchemaGroup},
+		ID:          id,
+		ExternalID:  group.ExternalId,
+		DisplayName: group.Name,
+		Meta:        newSCIMMeta("Group", "/scim/v2/Groups/"+id, group.CreatedAt, group.UpdatedAt),
+	}
+	for _, member := range members {
+		userID := strconv.FormatUint(uint64(member.ID), 10)
+		resource.Members = append(resource.Members, dto.SCIMMember{
+			Value:   userID,
+			Display: member.Username,
+			Ref:     scimLocation("/scim/v2/Users/" + userID),
+		})
+	}
+	return resource
+}
+
+func newSCIMListResponse(total int64, startIndex int, itemsPerPage int, resources interface{}) *scim.ListResponse {
+	return &scim.ListResponse{
+		Schemas:      []string{scim.SchemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: itemsPerPage,
+		Resources:    resources,
+	}
+}
+
+func newSCIMMeta(resourceType string, path string, created time.Time, lastModified time.Time) *scim.Meta {
+	return &scim.Meta{
+		ResourceType: resourceType,
+		Created:      created.UTC().Format(time.RFC3339),
+		LastModified: lastModified.UTC().Format(time.RFC3339),
+		Location:     scimLocation(path),
+	}
+}
+
+func scimLocation(path string) string {
+	return os.Getenv("BASE_URL") + path
+}
+
+func newSCIMTokenResponse(token *datastruct.ScimToken) *dto.SCIMTokenResponse {
+	return &dto.SCIMTokenResponse{
+		ID:          int64(token.ID),
+		Description: token.Description,
+		CreatedBy:   int64(token.CreatedBy),
+		CreatedAt:   token.CreatedAt,
+	}
+}
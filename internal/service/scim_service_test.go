@@ -0,0 +1,291 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fyfirman/auth-management-go/internal/datastruct"
+	"github.com/fyfirman/auth-management-go/internal/dto"
+	"github.com/fyfirman/auth-management-go/internal/repository"
+	"github.com/fyfirman/auth-management-go/internal/repository/mocks"
+	"github.com/fyfirman/auth-management-go/internal/service"
+	servicemocks "github.com/fyfirman/auth-management-go/internal/service/mocks"
+	"github.com/fyfirman/auth-management-go/internal/tenant"
+	"github.com/fyfirman/auth-management-go/pkg/scim"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type scimMocks struct {
+	tokens        *mocks.ScimTokenRepositoryInterface
+	users         *mocks.UserRepositoryInterface
+	groups        *mocks.GroupRepositoryInterface
+	organizations *mocks.OrganizationRepositoryInterface
+	sessions      *mocks.SessionRepositoryInterface
+}
+
+func newSCIMService() (*service.SCIMService, scimMocks) {
+	m := scimMocks{
+		tokens:        new(mocks.ScimTokenRepositoryInterface),
+		users:         new(mocks.UserRepositoryInterface),
+		groups:        new(mocks.GroupRepositoryInterface),
+		organizations: new(mocks.OrganizationRepositoryInterface),
+		sessions:      new(mocks.SessionRepositoryInterface),
+	}
+	return service.NewSCIMService(m.tokens, m.users, m.groups, m.organizations, m.sessions, nil), m
+}
+
+func TestSCIMService_Authenticate(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("resolves the organization of an issued token", func(t *testing.T) {
+		scimService, m := newSCIMService()
+		var stored *datastruct.ScimToken
+		m.tokens.Mock.On("CreateScimToken", ctx, mock.AnythingOfType("*datastruct.ScimToken")).
+			Run(func(args mock.Arguments) { stored = args.Get(1).(*datastruct.ScimToken) }).
+			Return(nil)
+
+		created, err := scimService.CreateToken(ctx, &service.Claims{UserID: 1, OrgID: 3},
+			dto.CreateSCIMTokenRequest{Description: "okta"})
+		assert.NoError(t, err)
+		assert.NotEqual(t, created.Token, stored.TokenHash)
+
+		m.tokens.Mock.On("FindScimTokenByHash", ctx, stored.TokenHash).Return(stored, nil)
+		organizationID, err := scimService.Authenticate(ctx, created.Token)
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(3), organizationID)
+	})
+
+	t.Run("rejects unknown tokens", func(t *testing.T) {
+		scimService, m := newSCIMService()
+		m.tokens.Mock.On("FindScimTokenByHash", ctx, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := scimService.Authenticate(ctx, "scim_unknown")
+
+		assert.ErrorIs(t, err, service.ErrInvalidSCIMToken)
+	})
+}
+
+func TestSCIMService_CreateUser(t *testing.T) {
+	ctx := tenant.WithOrganization(context.TODO(), 3)
+
+	t.Run("provisions a general user in the token's organization", func(t *testing.T) {
+		scimService, m := newSCIMService()
+		m.users.Mock.On("CreateUser", ctx, mock.AnythingOfType("*datastruct.User")).
+			Run(func(args mock.Arguments) { args.Get(1).(*datastruct.User).ID = 7 }).
+			Return(nil)
+		m.groups.Mock.On("ListGroupsByUserId", ctx, uint(3), uint(7)).Return([]datastruct.Group{}, nil)
+
+		res, err := scimService.CreateUser(ctx, dto.SCIMUser{
+			UserName:   "jdoe",
+			ExternalID: "00u1",
+			Emails: []dto.SCIMEmail{
+				{Value: "home@example.com"},
+				{Value: "jdoe@example.com", Primary: true},
+			},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "7", res.ID)
+		assert.Equal(t, "jdoe@example.com", res.Emails[0].Value)
+		assert.True(t, bool(*res.Active))
+
+		user := m.users.Mock.Calls[0].Arguments.Get(1).(*datastruct.User)
+		assert.Equal(t, uint(3), user.OrganizationId)
+		assert.Equal(t, datastruct.GeneralUser.String(), user.Role)
+		assert.Equal(t, "00u1", user.ExternalId)
+		assert.NotEmpty(t, user.PasswordHash)
+	})
+
+	t.Run("maps duplicates to a conflict", func(t *testing.T) {
+		scimService, m := newSCIMService()
+		m.users.Mock.On("CreateUser", ctx, mock.Anything).Return(gorm.ErrDuplicatedKey)
+
+		_, err := scimService.CreateUser(ctx, dto.SCIMUser{
+			UserName: "jdoe",
+			Emails:   []dto.SCIMEmail{{Value: "jdoe@example.com"}},
+		})
+
+		assert.ErrorIs(t, err, service.ErrUserConflict)
+	})
+
+	t.Run("requires an organization", func(t *testing.T) {
+		scimService, _ := newSCIMService()
+
+		_, err := scimService.CreateUser(context.TODO(), dto.SCIMUser{UserName: "jdoe"})
+
+		assert.ErrorIs(t, err, service.ErrNoOrganization)
+	})
+}
+
+func TestSCIMService_PatchUser(t *testing.T) {
+	ctx := tenant.WithOrganization(context.TODO(), 3)
+
+	t.Run("updates users of the token's organization", func(t *testing.T) {
+		scimService, m := newSCIMService()
+		m.users.Mock.On("FindById", ctx, uint(7)).
+			Return(&datastruct.User{ID: 7, OrganizationId: 3, Username: "jdoe", Email: "jdoe@example.com"}, nil)
+		m.groups.Mock.On("ListGroupsByUserId", ctx, uint(3), uint(7)).Return([]datastruct.Group{}, nil)
+		m.users.Mock.On("UpdateUser", ctx, mock.AnythingOfType("*datastruct.User")).Return(nil)
+		session := &datastruct.Session{ID: "session", UserId: 7}
+		m.sessions.Mock.On("FindSessionById", ctx, "session").Return(session, nil)
+		m.sessions.Mock.On("RevokeSessionsByUserId", ctx, uint(7), "").Run(func(args mock.Arguments) {
+			now := time.Now()
+			session.RevokedAt = &now
+		}).Return(nil)
+		sessionService := service.NewSessionService(m.sessions)
+		claims := &service.Claims{UserID: 7, SessionID: "session"}
+		assert.NoError(t, sessionService.CheckSession(ctx, claims))
+
+		// Azure AD sends booleans as strings.
+		res, err := scimService.PatchUser(ctx, "7", scim.PatchRequest{
+			Operations: []scim.PatchOperation{{Op: "Replace", Path: "active", Value: "False"}},
+		})
+
+		assert.NoError(t, err)
+		assert.False(t, bool(*res.Active))
+		user := m.users.Mock.Calls[1].Arguments.Get(1).(*datastruct.User)
+		assert.True(t, user.Disabled)
+		// Deprovisioned users are signed out, so RequireSession rejects their tokens
+		assert.ErrorIs(t, sessionService.CheckSession(ctx, claims), service.ErrSessionRevoked)
+	})
+
+	t.Run("cannot change members from another organization", func(t *testing.T) {
+		scimService, m := newSCIMService()
+		m.users.Mock.On("FindById", ctx, uint(7)).
+			Return(&datastruct.User{ID: 7, OrganizationId: 4, Username: "jdoe", Email: "jdoe@example.com"}, nil)
+		m.groups.Mock.On("ListGroupsByUserId", ctx, uint(3), uint(7)).Return([]datastruct.Group{}, nil)
+
+		_, err := scimService.PatchUser(ctx, "7", scim.PatchRequest{
+			Operations: []scim.PatchOperation{{Op: "Replace", Path: "emails[type eq \"work\"].value",
+				Value: "attacker@example.com"}},
+		})
+
+		assert.ErrorIs(t, err, service.ErrNotHomeOrganization)
+		m.users.Mock.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything)
+	})
+}
+
+func TestSCIMService_ReplaceUser(t *testing.T) {
+	ctx := tenant.WithOrganization(context.TODO(), 3)
+
+	t.Run("screens passwords against the password policy", func(t *testing.T) {
+		scimService, m := newSCIMService()
+		m.users.Mock.On("FindById", ctx, uint(7)).
+			Return(&datastruct.User{ID: 7, OrganizationId: 3, Username: "jdoe", Email: "jdoe@example.com"}, nil)
+
+		_, err := scimService.ReplaceUser(ctx, "7", dto.SCIMUser{
+			UserName: "jdoe",
+			Emails:   []dto.SCIMEmail{{Value: "jdoe@example.com"}},
+			Password: "short",
+		})
+
+		assert.ErrorIs(t, err, scim.ErrInvalidValue)
+		m.users.Mock.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("accepts an unchanged member from another organization", func(t *testing.T) {
+		scimService, m := newSCIMService()
+		m.users.Mock.On("FindById", ctx, uint(7)).
+			Return(&datastruct.User{ID: 7, OrganizationId: 4, Username: "jdoe", Email: "jdoe@example.com"}, nil)
+		m.groups.Mock.On("ListGroupsByUserId", ctx, uint(3), uint(7)).Return([]datastruct.Group{}, nil)
+
+		res, err := scimService.ReplaceUser(ctx, "7", dto.SCIMUser{
+			UserName: "jdoe",
+			Emails:   []dto.SCIMEmail{{Value: "jdoe@example.com"}},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "7", res.ID)
+		m.users.Mock.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything)
+	})
+}
+
+func TestSCIMService_DeleteUser(t *testing.T) {
+	ctx := tenant.WithOrganization(context.TODO(), 3)
+
+	t.Run("deletes users of the token's organization", func(t *testing.T) {
+		scimService, m := newSCIMService()
+		m.users.Mock.On("FindById", ctx, uint(7)).Return(&datastruct.User{ID: 7, OrganizationId: 3}, nil)
+		m.users.Mock.On("DeleteUserById", ctx, uint(7)).Return(nil)
+
+		assert.NoError(t, scimService.DeleteUser(ctx, "7"))
+	})
+
+	t.Run("only removes members from another organization", func(t *testing.T) {
+		_, m := newSCIMService()
+		auditRecorder := new(servicemocks.AuditRecorder)
+		scimService := service.NewSCIMService(m.tokens, m.users, m.groups, m.organizations, m.sessions, auditRecorder)
+		m.users.Mock.On("FindById", ctx, uint(7)).Return(&datastruct.User{ID: 7, OrganizationId: 4}, nil)
+		m.organizations.Mock.On("RemoveMember", ctx, uint(3), uint(7)).Return(nil)
+		m.sessions.Mock.On("RevokeSessionsByUserId", ctx, uint(7), "").Return(nil)
+		auditRecorder.On("Record", ctx, mock.MatchedBy(func(event *datastruct.AuditEvent) bool {
+			return event.Type == datastruct.AuditSessionsRevoked && event.ActorId == nil && *event.TargetId == 7 &&
+				event.Details["reason"] == "membership_removed"
+		})).Return()
+
+		assert.NoError(t, scimService.DeleteUser(ctx, "7"))
+		m.users.Mock.AssertNotCalled(t, "DeleteUserById", mock.Anything, mock.Anything)
+		auditRecorder.AssertExpectations(t)
+	})
+}
+
+func TestSCIMService_ListUsers(t *testing.T) {
+	ctx := tenant.WithOrganization(context.TODO(), 3)
+
+	t.Run("pages through filtered users", func(t *testing.T) {
+		scimService, m := newSCIMService()
+		m.users.Mock.On("ListUsers", ctx, mock.MatchedBy(func(filter repository.UserFilter) bool {
+			return filter.SCIM != nil && filter.Limit == 10 && filter.Offset == 0
+		})).Return([]datastruct.User{{ID: 7, Username: "jdoe", Email: "jdoe@example.com"}}, int64(1), nil)
+		m.groups.Mock.On("ListGroupsByUserId", ctx, uint(3), uint(7)).Return([]datastruct.Group{}, nil)
+
+		res, err := scimService.ListUsers(ctx, dto.SCIMListQuery{Filter: `userName eq "jdoe"`, Count: 10})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), res.TotalResults)
+		assert.Equal(t, 1, res.StartIndex)
+		assert.Len(t, res.Resources, 1)
+	})
+
+	t.Run("rejects invalid filters", func(t *testing.T) {
+		scimService, _ := newSCIMService()
+
+		_, err := scimService.ListUsers(ctx, dto.SCIMListQuery{Filter: `userName eq`})
+
+		assert.ErrorIs(t, err, scim.ErrInvalidFilter)
+	})
+}
+
+func TestSCIMService_PatchGroup(t *testing.T) {
+	ctx := tenant.WithOrganization(context.TODO(), 3)
+
+	scimService, m := newSCIMService()
+	group := &datastruct.Group{ID: 5, OrganizationId: 3, Name: "engineering"}
+	m.groups.Mock.On("FindGroupById", ctx, uint(3), uint(5)).Return(group, nil)
+	m.groups.Mock.On("ListGroupMembers", ctx, uint(5)).Return([]datastruct.User{{ID: 7}, {ID: 8}}, nil).Once()
+	m.groups.Mock.On("RemoveGroupMember", ctx, uint(5), uint(8)).Return(nil)
+	m.users.Mock.On("FindById", ctx, uint(9)).Return(&datastruct.User{ID: 9}, nil)
+	m.groups.Mock.On("AddGroupMember", ctx, &datastruct.GroupMember{GroupId: 5, UserId: 9}).Return(nil)
+	m.groups.Mock.On("ListGroupMembers", ctx, uint(5)).Return([]datastruct.User{{ID: 7}, {ID: 9}}, nil)
+	m.sessions.Mock.On("RevokeSessionsByUserId", ctx, mock.Anything, "").Return(nil)
+
+	res, err := scimService.PatchGroup(ctx, "5", scim.PatchRequest{
+		Operations: []scim.PatchOperation{
+			{Op: "add", Path: "members", Value: []interface{}{map[string]interface{}{"value": "9"}}},
+			{Op: "remove", Path: `members[value eq "8"]`},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, res.Members, 2)
+	m.groups.Mock.AssertNotCalled(t, "SaveGroup", mock.Anything, mock.Anything)
+	m.groups.Mock.AssertCalled(t, "RemoveGroupMember", ctx, uint(5), uint(8))
+	// Both changed members are signed out, the unchanged one is not
+	m.sessions.Mock.AssertCalled(t, "RevokeSessionsByUserId", ctx, uint(8), "")
+	m.sessions.Mock.AssertCalled(t, "RevokeSessionsByUserId", ctx, uint(9), "")
+	m.sessions.Mock.AssertNotCalled(t, "RevokeSessionsByUserId", ctx, uint(7), "")
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fyfirman/auth-management-go/internal/repository"
+	"gorm.io/gorm"
+)
+
+// SessionServiceInterface checks that the session a token was issued for is still active.
+type SessionServiceInterface interface {
+	CheckSession(ctx context.Context, claims *Claims) error
+}
+
+type SessionService struct {
+	sessionRepository repository.SessionRepositoryInterface
+}
+
+func NewSessionService(sessionRepository repository.SessionRepositoryInterface) *SessionService {
+	return &SessionService{sessionRepository: sessionRepository}
+}
+
+// CheckSession fails with ErrSessionRevoked for revoked or unknown sessions, including tokens issued
+// before sessions existed.
+func (s *SessionService) CheckSession(ctx context.Context, claims *Claims) error {
+	if claims.SessionID == "" {
+		return ErrSessionRevoked
+	}
+
+	session, err := s.sessionRepository.FindSessionById(ctx, claims.SessionID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrSessionRevoked
+	}
+	if err != nil {
+		return err
+	}
+	if session.UserId != claims.UserID || session.RevokedAt != nil {
+		return ErrSessionRevoked
+	}
+	return nil
+}
+
+// revokeGrantSessions signs the users out of every session after their role or groups changed. Tokens carry
+// the permissions they were issued with, so they would otherwise keep what was taken away until they expire.
+func revokeGrantSessions(
+	ctx context.Context,
+	sessionRepository repository.SessionRepositoryInterface,
+	recorder AuditRecorder,
+	actorID *uint,
+	reason string,
+	userIDs ...uint,
+) error {
+	for _, userID := range userIDs {
+		if err := sessionRepository.RevokeSessionsByUserId(ctx, userID, ""); err != nil {
+			return err
+		}
+		auditSessionsRevoked(ctx, recorder, actorID, userID, reason, "")
+	}
+	return nil
+}
@@ -3,8 +3,12 @@ package service
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"log"
 	"os"
 	"strconv"
 	"time"
@@ -12,9 +16,10 @@ import (
 	"github.com/fyfirman/auth-management-go/internal/datastruct"
 	"github.com/fyfirman/auth-management-go/internal/dto"
 	"github.com/fyfirman/auth-management-go/internal/repository"
+	"github.com/fyfirman/auth-management-go/internal/tenant"
 	"github.com/fyfirman/auth-management-go/pkg/mail_server"
 	"github.com/golang-jwt/jwt/v4"
-	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 type UserServiceInterface interface {
@@ -22,37 +27,90 @@ type UserServiceInterface interface {
 	Login(ctx context.Context, req dto.LoginRequest) (*dto.LoginResponse, error)
 	ForgotPassword(ctx context.Context, req dto.ForgotPasswordRequest) (*dto.ForgotPasswordResponse, error)
 	ResetPassword(ctx context.Context, req dto.ResetPasswordRequest) (*dto.ResetPasswordResponse, error)
+	SwitchOrganization(ctx context.Context, claims *Claims, organizationID uint) (*dto.LoginResponse, error)
+	VerifyLoginChallenge(ctx context.Context, req dto.VerifyLoginRequest) (*dto.LoginResponse, error)
 }
 
+const (
+	// defaultLoginLockoutThreshold and defaultLoginLockout apply when LOGIN_LOCKOUT_THRESHOLD and
+	// LOGIN_LOCKOUT_DURATION are not set.
+	defaultLoginLockoutThreshold = 5
+	defaultLoginLockout          = 15 * time.Minute
+
+	// maxLoginLockout caps the escalation of consecutive locks.
+	maxLoginLockout = 24 * time.Hour
+)
+
 type UserService struct {
-	userRepository  repository.UserRepositoryInterface
-	tokenRepository repository.TokenRepositoryInterface
+	userRepository         repository.UserRepositoryInterface
+	tokenRepository        repository.TokenRepositoryInterface
+	roleRepository         repository.RoleRepositoryInterface
+	organizationRepository repository.OrganizationRepositoryInterface
+	groupRepository        repository.GroupRepositoryInterface
+	sessionRepository      repository.SessionRepositoryInterface
+	mailer                 mail_server.MailInterface
+	auditRecorder          AuditRecorder
+	loginDeviceRepository  repository.LoginDeviceRepositoryInterface
 }
 
 func NewUserService(
 	userRepository repository.UserRepositoryInterface,
 	tokenRepository repository.TokenRepositoryInterface,
+	roleRepository repository.RoleRepositoryInterface,
+	organizationRepository repository.OrganizationRepositoryInterface,
+	groupRepository repository.GroupRepositoryInterface,
+	sessionRepository repository.SessionRepositoryInterface,
+	mailer mail_server.MailInterface,
+	auditRecorder AuditRecorder,
+	loginDeviceRepository repository.LoginDeviceRepositoryInterface,
 ) *UserService {
-	return &UserService{userRepository: userRepository, tokenRepository: tokenRepository}
+	return &UserService{
+		userRepository:         userRepository,
+		tokenRepository:        tokenRepository,
+		roleRepository:         roleRepository,
+		organizationRepository: organizationRepository,
+		groupRepository:        groupRepository,
+		sessionRepository:      sessionRepository,
+		mailer:                 mailer,
+		auditRecorder:          auditRecorder,
+		loginDeviceRepository:  loginDeviceRepository,
+	}
 }
 
 func (s *UserService) RegisterUser(ctx context.Context, req *dto.RegisterRequest) (*dto.RegisterResponse, error) {
+	if err := checkPassword(ctx, "Password", req.Password, req.Username, req.Email); err != nil {
+		return nil, err
+	}
+
 	hashedPassword, err := hashPassword(req.Password)
 	if err != nil {
 		return nil, err
 	}
 
+	organization, err := s.organizationRepository.FindOrganizationBySlug(ctx, datastruct.DefaultOrganizationSlug)
+	if err != nil {
+		return nil, err
+	}
+
 	user := &datastruct.User{
-		Username:     req.Username,
-		Email:        req.Email,
-		Role:         req.Role,
-		PasswordHash: hashedPassword,
+		OrganizationId: organization.ID,
+		Username:       req.Username,
+		Email:          req.Email,
+		Role:           datastruct.GeneralUser.String(),
+		PasswordHash:   hashedPassword,
 	}
 
 	err = s.userRepository.CreateUser(ctx, user)
 	if err != nil {
-		return nil, err
+		return nil, mapUserError(err)
 	}
+	recordAudit(ctx, s.auditRecorder, &datastruct.AuditEvent{
+		OrganizationId: auditID(user.OrganizationId),
+		Type:           datastruct.AuditRegister,
+		Outcome:        datastruct.AuditSuccess,
+		ActorId:        auditID(user.ID),
+		TargetId:       auditID(user.ID),
+	})
 
 	response := &dto.RegisterResponse{
 		ID:        int64(user.ID),
@@ -65,18 +123,222 @@ func (s *UserService) RegisterUser(ctx context.Context, req *dto.RegisterRequest
 	return response, nil
 }
 
+// Login fails with ErrInvalidCredentials for unknown emails, wrong passwords and locked accounts alike, so
+// the response does not reveal whether an account exists. After loginLockoutThreshold consecutive failures
+// the account is locked and its owner notified. When the password is past its maximum age the token only
+// grants changing it. Logins scoring LOGIN_RISK_MFA_THRESHOLD or more are held back until the code emailed
+// to the user is entered, see VerifyLoginChallenge.
 func (s *UserService) Login(ctx context.Context, req dto.LoginRequest) (*dto.LoginResponse, error) {
 	user, err := s.userRepository.FindByEmail(ctx, req.Email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		verifyPassword(dummyPasswordHash(), req.Password)
+		s.auditLoginFailure(ctx, req.Email, nil, "unknown_email")
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	match, rehash := verifyPassword(user.PasswordHash, req.Password)
+	if user.Locked(time.Now()) {
+		s.auditLoginFailure(ctx, req.Email, user, "locked")
+		return nil, ErrInvalidCredentials
+	}
+	if !match {
+		s.auditLoginFailure(ctx, req.Email, user, "invalid_password")
+		if err := recordFailedLogin(ctx, s.userRepository, s.mailer, user); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidCredentials
+	}
+
+	if user.Disabled {
+		s.auditLoginFailure(ctx, req.Email, user, "disabled")
+		return nil, ErrInvalidCredentials
+	}
+
+	if rehash {
+		s.rehashPassword(ctx, user, req.Password)
+	}
+
+	device, err := s.assessLogin(ctx, user, req.DeviceID, user.FailedLoginAttempts)
+	if err != nil {
+		return nil, err
+	}
+	challenge, err := challengeRequired(device)
+	if err != nil {
+		return nil, err
+	}
+	if challenge {
+		return s.challengeLogin(ctx, user, device)
+	}
+	return s.completeLogin(ctx, user, device)
+}
+
+// completeLogin starts a session for user logging in from device, nil when devices are not kept, and
+// issues its token. The failed logins are only cleared here, so a login held back for a code does not
+// reset the lockout.
+func (s *UserService) completeLogin(
+	ctx context.Context,
+	user *datastruct.User,
+	device *loginDevice,
+) (*dto.LoginResponse, error) {
+	if user.FailedLoginAttempts > 0 || user.LockoutCount > 0 || user.LockedUntil != nil {
+		if err := s.userRepository.ResetFailedLogins(ctx, user.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	member, err := s.homeMembership(ctx, user)
 	if err != nil {
 		return nil, err
 	}
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password))
+	session, err := s.createSession(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	expired, err := passwordExpired(user, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	details := map[string]string{
+		"session_hash":     hashToken(session.ID),
+		"password_expired": strconv.FormatBool(expired),
+	}
+	for key, value := range loginRiskDetails(device) {
+		details[key] = value
+	}
+	recordAudit(ctx, s.auditRecorder, &datastruct.AuditEvent{
+		OrganizationId: auditID(member.OrganizationId),
+		Type:           datastruct.AuditLogin,
+		Outcome:        datastruct.AuditSuccess,
+		ActorId:        auditID(user.ID),
+		TargetId:       auditID(user.ID),
+		Details:        details,
+	})
+	s.rememberDevice(ctx, user, device)
+
+	if expired {
+		token, err := generateJWT(user, session.ID, member.OrganizationId, member.Role, nil, nil, true)
+		if err != nil {
+			return nil, err
+		}
+		return &dto.LoginResponse{Token: token, PasswordChangeRequired: true}, nil
+	}
+
+	token, err := s.issueToken(ctx, user, session.ID, member.OrganizationId, member.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.LoginResponse{Token: token}, nil
+}
+
+// auditLoginFailure records a failed login with email, by user when the account exists.
+func (s *UserService) auditLoginFailure(ctx context.Context, email string, user *datastruct.User, reason string) {
+	event := &datastruct.AuditEvent{
+		Type:    datastruct.AuditLogin,
+		Outcome: datastruct.AuditFailure,
+		Details: map[string]string{"email": email, "reason": reason},
+	}
+	if user != nil {
+		event.OrganizationId = auditID(user.OrganizationId)
+		event.TargetId = auditID(user.ID)
+	}
+	recordAudit(ctx, s.auditRecorder, event)
+}
+
+// rehashPassword replaces a hash made with another algorithm or outdated parameters, now that the password
+// is known. Failures are logged: the old hash keeps working.
+func (s *UserService) rehashPassword(ctx context.Context, user *datastruct.User, plain string) {
+	hashedPassword, err := hashPassword(plain)
+	if err == nil {
+		_, err = s.userRepository.UpdatePasswordById(ctx, user.ID, hashedPassword)
+	}
+	if err != nil {
+		log.Printf("Failed to rehash the password of user %d: %v", user.ID, err)
+	}
+}
+
+// recordFailedLogin counts a failed login and locks the account once the threshold is reached. Each
+// consecutive lock lasts twice as long as the previous one, up to maxLoginLockout.
+func recordFailedLogin(
+	ctx context.Context,
+	userRepository repository.UserRepositoryInterface,
+	mailer mail_server.MailInterface,
+	user *datastruct.User,
+) error {
+	threshold, baseLockout, err := loginLockoutSettings()
+	if err != nil {
+		return err
+	}
+
+	failures, err := userRepository.RecordFailedLogin(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if failures < threshold {
+		return nil
+	}
+
+	lockout := baseLockout
+	for i := 0; i < user.LockoutCount && lockout < maxLoginLockout; i++ {
+		lockout *= 2
+	}
+	lockout = min(lockout, maxLoginLockout)
+	until := time.Now().Add(lockout)
+	if err := userRepository.LockUserById(ctx, user.ID, until); err != nil {
+		return err
+	}
+
+	if _, err := mailer.Send(&mail_server.SendEmailRequest{
+		From:    os.Getenv("EMAIL_SENDER"),
+		To:      []string{user.Email},
+		Subject: "Auth management - Your account has been locked",
+		Html: "<p> Your account was locked after " + strconv.Itoa(failures) + " failed sign in attempts and can " +
+			"be used again after " + until.UTC().Format(time.RFC1123) + ". If this was not you, reset your " +
+			"password : " + os.Getenv("BASE_URL") + "/forgot-password</p>",
+	}); err != nil {
+		log.Printf("Failed to send the lockout notification to user %d: %v", user.ID, err)
+	}
+	return nil
+}
+
+// SwitchOrganization issues a token for another organization the user belongs to.
+// Platform superadmins can switch to any organization.
+func (s *UserService) SwitchOrganization(
+	ctx context.Context,
+	claims *Claims,
+	organizationID uint,
+) (*dto.LoginResponse, error) {
+	user, err := s.userRepository.FindById(tenant.WithOrganization(ctx, 0), claims.UserID)
 	if err != nil {
-		return nil, errors.New("invalid credentials")
+		return nil, mapUserError(err)
+	}
+	if user.Disabled {
+		s.auditLoginFailure(ctx, user.Email, user, "disabled")
+		return nil, ErrInvalidCredentials
 	}
 
-	token, err := generateJWT(user)
+	organizationRole := user.Role
+	if user.Role == datastruct.SuperAdmin.String() {
+		if _, err := s.organizationRepository.FindOrganizationById(ctx, organizationID); err != nil {
+			return nil, mapOrganizationError(err)
+		}
+	} else {
+		member, err := s.organizationRepository.FindMember(ctx, organizationID, user.ID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotOrganizationMember
+		}
+		if err != nil {
+			return nil, err
+		}
+		organizationRole = member.Role
+	}
+
+	token, err := s.issueToken(ctx, user, claims.SessionID, organizationID, organizationRole)
 	if err != nil {
 		return nil, err
 	}
@@ -84,6 +346,61 @@ func (s *UserService) Login(ctx context.Context, req dto.LoginRequest) (*dto.Log
 	return &dto.LoginResponse{Token: token}, nil
 }
 
+// homeMembership returns the membership a login starts in: the organization the account was created in,
+// or the first organization the user still belongs to.
+func (s *UserService) homeMembership(
+	ctx context.Context,
+	user *datastruct.User,
+) (*datastruct.OrganizationMember, error) {
+	member, err := s.organizationRepository.FindMember(ctx, user.OrganizationId, user.ID)
+	if err == nil {
+		return member, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	members, err := s.organizationRepository.ListMembershipsByUserId(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(members) == 0 {
+		return nil, ErrNoOrganization
+	}
+	return &members[0], nil
+}
+
+// createSession starts the session the tokens of a login belong to.
+func (s *UserService) createSession(ctx context.Context, user *datastruct.User) (*datastruct.Session, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	session := &datastruct.Session{ID: base64.RawURLEncoding.EncodeToString(secret), UserId: user.ID}
+	if err := s.sessionRepository.CreateSession(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// issueToken signs a JWT for the session carrying the user's effective permissions in the organization and
+// the names of the groups they came from.
+func (s *UserService) issueToken(
+	ctx context.Context,
+	user *datastruct.User,
+	sessionID string,
+	organizationID uint,
+	organizationRole string,
+) (string, error) {
+	grants, err := effectiveGrants(ctx, s.roleRepository, s.groupRepository, user, organizationID, organizationRole)
+	if err != nil {
+		return "", err
+	}
+
+	return generateJWT(user, sessionID, organizationID, grants.Role, grants.Groups, grants.Permissions, false)
+}
+
 func (s *UserService) ForgotPassword(
 	ctx context.Context,
 	req dto.ForgotPasswordRequest,
@@ -95,7 +412,10 @@ func (s *UserService) ForgotPassword(
 
 	expiryTimeInSeconds := 60 * 60
 
-	token := generateForgotPasswordToken()
+	token, err := generateForgotPasswordToken()
+	if err != nil {
+		return nil, err
+	}
 
 	err = s.tokenRepository.CreateToken(ctx, &datastruct.Token{
 		Token:     token,
@@ -106,9 +426,14 @@ func (s *UserService) ForgotPassword(
 	if err != nil {
 		return nil, err
 	}
+	recordAudit(ctx, s.auditRecorder, &datastruct.AuditEvent{
+		OrganizationId: auditID(user.OrganizationId),
+		Type:           datastruct.AuditPasswordResetRequested,
+		Outcome:        datastruct.AuditSuccess,
+		TargetId:       auditID(user.ID),
+	})
 
-	mailClient := mail_server.New()
-	_, err = mailClient.Send(&mail_server.SendEmailRequest{
+	_, err = s.mailer.Send(&mail_server.SendEmailRequest{
 		From:    os.Getenv("EMAIL_SENDER"),
 		To:      []string{user.Email},
 		Subject: "Auth management - ForgotPassword Password Request",
@@ -128,39 +453,62 @@ func (s *UserService) ResetPassword(
 	token, err := s.tokenRepository.FindByToken(ctx, req.Token)
 
 	if err != nil {
+		s.auditPasswordReset(ctx, nil, datastruct.AuditFailure, "invalid_token")
 		return nil, err
 	}
 
+	user, err := s.userRepository.FindById(ctx, token.UserId)
+	if err != nil {
+		return nil, mapUserError(err)
+	}
+
 	if token.ExpiredAt.Before(time.Now()) {
+		s.auditPasswordReset(ctx, user, datastruct.AuditFailure, "expired_token")
 		return nil, errors.New("Token is already expired")
 	}
 
-	hashedPassword, err := hashPassword(req.NewPassword)
-
-	if err != nil {
+	if err := setPassword(ctx, s.userRepository, user, "NewPassword", req.NewPassword); err != nil {
 		return nil, err
 	}
+	if err := s.tokenRepository.DeleteToken(ctx, token.Token); err != nil {
+		return nil, err
+	}
+	s.auditPasswordReset(ctx, user, datastruct.AuditSuccess, "")
 
-	user, err := s.userRepository.UpdatePasswordById(ctx, token.UserId, hashedPassword)
-
-	if err != nil {
+	if err := s.sessionRepository.RevokeSessionsByUserId(ctx, user.ID, ""); err != nil {
 		return nil, err
 	}
+	auditSessionsRevoked(ctx, s.auditRecorder, auditID(user.ID), user.ID, "password_reset", "")
 
 	return &dto.ResetPasswordResponse{
 		Message: user.Email + " successfully updated",
 	}, nil
 }
 
-func hashPassword(password string) (string, error) {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
-		return "", err
+// auditPasswordReset records the outcome of a password reset of user, unknown for invalid tokens. reason
+// explains failures.
+func (s *UserService) auditPasswordReset(ctx context.Context, user *datastruct.User, outcome string, reason string) {
+	event := &datastruct.AuditEvent{Type: datastruct.AuditPasswordResetCompleted, Outcome: outcome}
+	if user != nil {
+		event.OrganizationId = auditID(user.OrganizationId)
+		event.ActorId = auditID(user.ID)
+		event.TargetId = auditID(user.ID)
+	}
+	if reason != "" {
+		event.Details = map[string]string{"reason": reason}
 	}
-	return string(hashedPassword), nil
+	recordAudit(ctx, s.auditRecorder, event)
 }
 
-func generateJWT(user *datastruct.User) (string, error) {
+func generateJWT(
+	user *datastruct.User,
+	sessionID string,
+	organizationID uint,
+	role string,
+	groups []string,
+	permissions []string,
+	passwordChangeRequired bool,
+) (string, error) {
 	var jwtSecretKey = []byte(os.Getenv("JWT_SECRET"))
 	expiryTimeInSecondsStr := os.Getenv("JWT_EXPIRY_TIME")
 	expiryTimeInSeconds, err := strconv.Atoi(expiryTimeInSecondsStr)
@@ -170,9 +518,14 @@ func generateJWT(user *datastruct.User) (string, error) {
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"exp":       time.Now().Add(time.Duration(expiryTimeInSeconds) * time.Second).Unix(),
-		"user_id":   user.ID,
-		"user_role": user.Role,
+		"exp":         time.Now().Add(time.Duration(expiryTimeInSeconds) * time.Second).Unix(),
+		"sid":         sessionID,
+		"user_id":     user.ID,
+		"user_role":   role,
+		"org_id":      organizationID,
+		"groups":      groups,
+		"permissions": permissions,
+		"pwd_change":  passwordChangeRequired,
 	})
 
 	tokenString, err := token.SignedString(jwtSecretKey)
@@ -183,10 +536,114 @@ func generateJWT(user *datastruct.User) (string, error) {
 	return tokenString, nil
 }
 
-func generateForgotPasswordToken() string {
+// Claims is the subset of the JWT payload the HTTP layer relies on to authorize requests.
+type Claims struct {
+	SessionID   string
+	UserID      uint
+	OrgID       uint
+	Role        string
+	Groups      []string
+	Permissions []string
+	// PasswordChangeRequired restricts the token to changing an expired password.
+	PasswordChangeRequired bool
+}
+
+func (c *Claims) HasPermission(permission string) bool {
+	for _, granted := range c.Permissions {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+func ParseJWT(tokenString string) (*Claims, error) {
+	var jwtSecretKey = []byte(os.Getenv("JWT_SECRET"))
+
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return jwtSecretKey, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	mapClaims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	userID, ok := mapClaims["user_id"].(float64)
+	if !ok {
+		return nil, errors.New("invalid token: missing user_id")
+	}
+	role, ok := mapClaims["user_role"].(string)
+	if !ok {
+		return nil, errors.New("invalid token: missing user_role")
+	}
+
+	orgID, _ := mapClaims["org_id"].(float64)
+	sessionID, _ := mapClaims["sid"].(string)
+	passwordChangeRequired, _ := mapClaims["pwd_change"].(bool)
+
+	return &Claims{
+		SessionID:              sessionID,
+		UserID:                 uint(userID),
+		OrgID:                  uint(orgID),
+		Role:                   role,
+		Groups:                 stringsClaim(mapClaims, "groups"),
+		Permissions:            stringsClaim(mapClaims, "permissions"),
+		PasswordChangeRequired: passwordChangeRequired,
+	}, nil
+}
+
+func stringsClaim(mapClaims jwt.MapClaims, name string) []string {
+	var values []string
+	if rawValues, ok := mapClaims[name].([]interface{}); ok {
+		for _, rawValue := range rawValues {
+			if value, ok := rawValue.(string); ok {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+func generateForgotPasswordToken() (string, error) {
 	bytes := make([]byte, 15)
-	rand.Read(bytes)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
 	token := base32.StdEncoding.EncodeToString(bytes)
 
-	return token
+	return token, nil
+}
+
+// hashToken hashes a random token before it is stored, so the stored hashes cannot be used as tokens.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// loginLockoutSettings reads LOGIN_LOCKOUT_THRESHOLD, the number of consecutive failed logins locking an
+// account, and LOGIN_LOCKOUT_DURATION, the Go duration of the first lock.
+func loginLockoutSettings() (int, time.Duration, error) {
+	threshold, lockout := defaultLoginLockoutThreshold, defaultLoginLockout
+	if value := os.Getenv("LOGIN_LOCKOUT_THRESHOLD"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, 0, err
+		}
+		threshold = parsed
+	}
+	if value := os.Getenv("LOGIN_LOCKOUT_DURATION"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, 0, err
+		}
+		lockout = parsed
+	}
+	return threshold, lockout, nil
 }
@@ -3,29 +3,44 @@ package service_test
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/fyfirman/auth-management-go/internal/clientinfo"
 	"github.com/fyfirman/auth-management-go/internal/datastruct"
 	"github.com/fyfirman/auth-management-go/internal/dto"
 	"github.com/fyfirman/auth-management-go/internal/repository/mocks"
 	"github.com/fyfirman/auth-management-go/internal/service"
+	servicemocks "github.com/fyfirman/auth-management-go/internal/service/mocks"
+	"github.com/fyfirman/auth-management-go/pkg"
+	"github.com/fyfirman/auth-management-go/pkg/mail_server"
+	mailmocks "github.com/fyfirman/auth-management-go/pkg/mail_server/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 func TestUserService_RegisterUser(t *testing.T) {
 	userRepository := new(mocks.UserRepositoryInterface)
 	tokenRepository := new(mocks.TokenRepositoryInterface)
-	userService := service.NewUserService(userRepository, tokenRepository)
+	roleRepository := new(mocks.RoleRepositoryInterface)
+	organizationRepository := new(mocks.OrganizationRepositoryInterface)
+	userService := service.NewUserService(userRepository, tokenRepository, roleRepository, organizationRepository,
+		new(mocks.GroupRepositoryInterface), new(mocks.SessionRepositoryInterface), nil, nil, nil)
 
 	ctx := context.TODO()
 	req := &dto.RegisterRequest{
 		Username: "testuser",
 		Email:    "test@example.com",
-		Password: "password",
+		Password: "c0rrect-horse",
 	}
 
+	// Mock the FindOrganizationBySlug method in OrganizationRepository
+	organizationRepository.Mock.On("FindOrganizationBySlug", ctx, datastruct.DefaultOrganizationSlug).
+		Return(&datastruct.Organization{ID: 1, Slug: datastruct.DefaultOrganizationSlug}, nil)
+
 	// Mock the CreateUser method in UserRepository
 	userRepository.Mock.On("CreateUser", ctx, mock.AnythingOfType("*datastruct.User")).Return(nil)
 
@@ -40,6 +55,30 @@ func TestUserService_RegisterUser(t *testing.T) {
 
 	// Assert that the CreateUser method was called with the correct arguments
 	userRepository.Mock.AssertCalled(t, "CreateUser", ctx, mock.AnythingOfType("*datastruct.User"))
+
+	// Public registration must never grant more than the general user role, in the default organization
+	createdUser := userRepository.Mock.Calls[0].Arguments.Get(1).(*datastruct.User)
+	assert.Equal(t, datastruct.GeneralUser.String(), createdUser.Role)
+	assert.Equal(t, uint(1), createdUser.OrganizationId)
+}
+
+func TestUserService_RegisterUser_PasswordPolicy(t *testing.T) {
+	userRepository := new(mocks.UserRepositoryInterface)
+	userService := service.NewUserService(userRepository, new(mocks.TokenRepositoryInterface),
+		new(mocks.RoleRepositoryInterface), new(mocks.OrganizationRepositoryInterface),
+		new(mocks.GroupRepositoryInterface), new(mocks.SessionRepositoryInterface), nil, nil, nil)
+
+	_, err := userService.RegisterUser(context.TODO(), &dto.RegisterRequest{
+		Username: "testuser",
+		Email:    "test@example.com",
+		Password: "testuser1",
+	})
+
+	var fieldErrors pkg.FieldErrors
+	assert.ErrorAs(t, err, &fieldErrors)
+	assert.Equal(t, pkg.FieldError{Field: "Password", Message: "Password must not contain the username or email"},
+		fieldErrors[0])
+	userRepository.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
 }
 
 func TestUserService_Login(t *testing.T) {
@@ -47,8 +86,13 @@ func TestUserService_Login(t *testing.T) {
 	t.Setenv("JWT_EXPIRY_TIME", "100000")
 	userRepository := new(mocks.UserRepositoryInterface)
 	tokenRepository := new(mocks.TokenRepositoryInterface)
+	roleRepository := new(mocks.RoleRepositoryInterface)
+	organizationRepository := new(mocks.OrganizationRepositoryInterface)
+	groupRepository := new(mocks.GroupRepositoryInterface)
+	sessionRepository := new(mocks.SessionRepositoryInterface)
 
-	userService := service.NewUserService(userRepository, tokenRepository)
+	userService := service.NewUserService(userRepository, tokenRepository, roleRepository, organizationRepository,
+		groupRepository, sessionRepository, nil, nil, nil)
 
 	ctx := context.TODO()
 	email := "test@example.com"
@@ -57,10 +101,34 @@ func TestUserService_Login(t *testing.T) {
 	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	// Mock the FindByEmail method in UserRepository
 	user := &datastruct.User{
-		Email:        email,
-		PasswordHash: string(hashedPassword),
+		ID:             9,
+		OrganizationId: 2,
+		Email:          email,
+		Role:           datastruct.GeneralUser.String(),
+		PasswordHash:   string(hashedPassword),
 	}
 	userRepository.Mock.On("FindByEmail", ctx, email).Return(user, nil)
+	organizationRepository.Mock.On("FindMember", ctx, uint(2), uint(9)).Return(&datastruct.OrganizationMember{
+		OrganizationId: 2,
+		UserId:         9,
+		Role:           datastruct.Admin.String(),
+	}, nil)
+	roleRepository.Mock.On("FindRoleByName", ctx, datastruct.Admin.String()).Return(&datastruct.Role{
+		Name:        datastruct.Admin.String(),
+		Permissions: []datastruct.Permission{{Name: datastruct.PermissionUsersRead}},
+	}, nil)
+	groupRepository.Mock.On("ListGroupsByUserId", ctx, uint(2), uint(9)).Return([]datastruct.Group{{
+		Name:        "editors",
+		Roles:       []datastruct.Role{{Name: "support", Permissions: []datastruct.Permission{{Name: datastruct.PermissionUsersRead}}}},
+		Permissions: []datastruct.Permission{{Name: datastruct.PermissionGroupsRead}},
+	}}, nil)
+	sessionRepository.Mock.On("CreateSession", ctx, mock.MatchedBy(func(session *datastruct.Session) bool {
+		return session.UserId == 9 && session.ID != ""
+	})).Return(nil)
+	// The bcrypt hash is replaced by one of the default hasher
+	userRepository.Mock.On("UpdatePasswordById", ctx, uint(9), mock.MatchedBy(func(hash string) bool {
+		return strings.HasPrefix(hash, "$argon2id$")
+	})).Return(user, nil)
 
 	// Call the Login method
 	req := dto.LoginRequest{
@@ -76,16 +144,34 @@ func TestUserService_Login(t *testing.T) {
 
 	// Assert that the FindByEmail method was called with the correct arguments
 	userRepository.Mock.AssertCalled(t, "FindByEmail", ctx, email)
+	userRepository.Mock.AssertCalled(t, "UpdatePasswordById", ctx, uint(9), mock.Anything)
 
 	// Assert that bcrypt.CompareHashAndPassword was called with the correct arguments
 	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password))
 	assert.NoError(t, err)
+
+	// Assert that the organization role and its permissions are carried by the token
+	claims, err := service.ParseJWT(res.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), claims.OrgID)
+	assert.Equal(t, datastruct.Admin.String(), claims.Role)
+	assert.Equal(t, sessionRepository.Mock.Calls[0].Arguments.Get(1).(*datastruct.Session).ID, claims.SessionID)
+	assert.True(t, claims.HasPermission(datastruct.PermissionUsersRead))
+	assert.False(t, claims.HasPermission(datastruct.PermissionRolesWrite))
+
+	// Group grants are merged into the permissions without duplicates, and the groups are named in the token
+	assert.Equal(t, []string{datastruct.PermissionUsersRead, datastruct.PermissionGroupsRead}, claims.Permissions)
+	assert.Equal(t, []string{"editors"}, claims.Groups)
 }
 
 func TestUserService_Login_InvalidCredentials(t *testing.T) {
 	userRepository := new(mocks.UserRepositoryInterface)
 	mockTokenRepo := new(mocks.TokenRepositoryInterface)
-	userService := service.NewUserService(userRepository, mockTokenRepo)
+	mockRoleRepo := new(mocks.RoleRepositoryInterface)
+	auditRecorder := new(servicemocks.AuditRecorder)
+	userService := service.NewUserService(userRepository, mockTokenRepo, mockRoleRepo,
+		new(mocks.OrganizationRepositoryInterface), new(mocks.GroupRepositoryInterface),
+		new(mocks.SessionRepositoryInterface), nil, auditRecorder, nil)
 
 	ctx := context.TODO()
 	email := "test@example.com"
@@ -97,6 +183,11 @@ func TestUserService_Login_InvalidCredentials(t *testing.T) {
 		PasswordHash: "$2a$10$4vY5z7j8k9l0m1n2o3p4q5r6s7t8u9v0w1x2y3z4a5b6c7d8e9f0g",
 	}
 	userRepository.Mock.On("FindByEmail", ctx, email).Return(user, nil)
+	userRepository.Mock.On("RecordFailedLogin", ctx, user.ID).Return(1, nil)
+	auditRecorder.On("Record", ctx, mock.MatchedBy(func(event *datastruct.AuditEvent) bool {
+		return event.Type == datastruct.AuditLogin && event.Outcome == datastruct.AuditFailure &&
+			event.Details["reason"] == "invalid_password" && event.Details["email"] == email
+	})).Return()
 
 	// Call the Login method with invalid credentials
 	req := dto.LoginRequest{
@@ -112,6 +203,7 @@ func TestUserService_Login_InvalidCredentials(t *testing.T) {
 
 	// Assert that the FindByEmail method was called with the correct arguments
 	userRepository.Mock.AssertCalled(t, "FindByEmail", ctx, email)
+	auditRecorder.AssertExpectations(t)
 
 	// Assert that bcrypt.CompareHashAndPassword was called with the correct arguments
 	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password))
@@ -120,9 +212,6 @@ func TestUserService_Login_InvalidCredentials(t *testing.T) {
 }
 
 func TestForgotPassword(t *testing.T) {
-
-	t.Skip("Skipped. Need to extract `mail_server` to DI")
-
 	ctx := context.Background()
 
 	user := &datastruct.User{ID: 1, Email: "test@example.com"}
@@ -130,10 +219,15 @@ func TestForgotPassword(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockUserRepo := new(mocks.UserRepositoryInterface)
 		mockTokenRepo := new(mocks.TokenRepositoryInterface)
-		userService := service.NewUserService(mockUserRepo, mockTokenRepo)
+		mockRoleRepo := new(mocks.RoleRepositoryInterface)
+		mailer := new(mailmocks.MailInterface)
+		userService := service.NewUserService(mockUserRepo, mockTokenRepo, mockRoleRepo,
+			new(mocks.OrganizationRepositoryInterface), new(mocks.GroupRepositoryInterface),
+			new(mocks.SessionRepositoryInterface), mailer, nil, nil)
 
 		mockUserRepo.On("FindByEmail", ctx, user.Email).Return(user, nil)
 		mockTokenRepo.On("CreateToken", ctx, mock.AnythingOfType("*datastruct.Token")).Return(nil)
+		mailer.On("Send", mock.Anything).Return(true, nil)
 
 		resp, err := userService.ForgotPassword(ctx, dto.ForgotPasswordRequest{Email: user.Email})
 
@@ -142,12 +236,19 @@ func TestForgotPassword(t *testing.T) {
 		assert.NotEmpty(t, resp.Token)
 		mockUserRepo.AssertExpectations(t)
 		mockTokenRepo.AssertExpectations(t)
+		email := mailer.Calls[0].Arguments.Get(0).(*mail_server.SendEmailRequest)
+		assert.Equal(t, []string{user.Email}, email.To)
+		assert.Contains(t, email.Html, resp.Token)
 	})
 
 	t.Run("FindByEmail error", func(t *testing.T) {
 		mockUserRepo := new(mocks.UserRepositoryInterface)
 		mockTokenRepo := new(mocks.TokenRepositoryInterface)
-		userService := service.NewUserService(mockUserRepo, mockTokenRepo)
+		mockRoleRepo := new(mocks.RoleRepositoryInterface)
+		mailer := new(mailmocks.MailInterface)
+		userService := service.NewUserService(mockUserRepo, mockTokenRepo, mockRoleRepo,
+			new(mocks.OrganizationRepositoryInterface), new(mocks.GroupRepositoryInterface),
+			new(mocks.SessionRepositoryInterface), mailer, nil, nil)
 
 		mockUserRepo.On("FindByEmail", ctx, user.Email).Return(nil, errors.New("user not found"))
 
@@ -161,7 +262,11 @@ func TestForgotPassword(t *testing.T) {
 	t.Run("CreateToken error", func(t *testing.T) {
 		mockUserRepo := new(mocks.UserRepositoryInterface)
 		mockTokenRepo := new(mocks.TokenRepositoryInterface)
-		userService := service.NewUserService(mockUserRepo, mockTokenRepo)
+		mockRoleRepo := new(mocks.RoleRepositoryInterface)
+		mailer := new(mailmocks.MailInterface)
+		userService := service.NewUserService(mockUserRepo, mockTokenRepo, mockRoleRepo,
+			new(mocks.OrganizationRepositoryInterface), new(mocks.GroupRepositoryInterface),
+			new(mocks.SessionRepositoryInterface), mailer, nil, nil)
 
 		mockUserRepo.On("FindByEmail", ctx, user.Email).Return(user, nil)
 		mockTokenRepo.On("CreateToken", ctx, mock.AnythingOfType("*datastruct.Token")).Return(errors.New("db error"))
@@ -174,3 +279,411 @@ func TestForgotPassword(t *testing.T) {
 		mockTokenRepo.AssertExpectations(t)
 	})
 }
+
+func TestUserService_ResetPassword(t *testing.T) {
+	ctx := context.TODO()
+
+	newUserService := func() (*service.UserService, *mocks.UserRepositoryInterface,
+		*mocks.TokenRepositoryInterface, *mocks.SessionRepositoryInterface) {
+		userRepository := new(mocks.UserRepositoryInterface)
+		tokenRepository := new(mocks.TokenRepositoryInterface)
+		sessionRepository := new(mocks.SessionRepositoryInterface)
+		userService := service.NewUserService(userRepository, tokenRepository, new(mocks.RoleRepositoryInterface),
+			new(mocks.OrganizationRepositoryInterface), new(mocks.GroupRepositoryInterface), sessionRepository,
+			nil, nil, nil)
+		return userService, userRepository, tokenRepository, sessionRepository
+	}
+
+	t.Run("uses up the token and signs out every session", func(t *testing.T) {
+		userService, userRepository, tokenRepository, sessionRepository := newUserService()
+		tokenRepository.On("FindByToken", ctx, "TOKEN").
+			Return(&datastruct.Token{Token: "TOKEN", UserId: 7, ExpiredAt: time.Now().Add(time.Hour)}, nil)
+		userRepository.On("FindById", ctx, uint(7)).
+			Return(&datastruct.User{ID: 7, Username: "jdoe", Email: "jdoe@example.com"}, nil)
+		userRepository.On("ChangePasswordById", ctx, uint(7), mock.Anything, 0).Return(nil)
+		tokenRepository.On("DeleteToken", ctx, "TOKEN").Return(nil)
+		session := &datastruct.Session{ID: "session", UserId: 7}
+		sessionRepository.On("FindSessionById", ctx, "session").Return(session, nil)
+		sessionRepository.On("RevokeSessionsByUserId", ctx, uint(7), "").Run(func(args mock.Arguments) {
+			now := time.Now()
+			session.RevokedAt = &now
+		}).Return(nil)
+
+		_, err := userService.ResetPassword(ctx, dto.ResetPasswordRequest{Token: "TOKEN", NewPassword: "c0rrect-horse"})
+
+		assert.NoError(t, err)
+		tokenRepository.AssertExpectations(t)
+		claims := &service.Claims{UserID: 7, SessionID: "session"}
+		assert.ErrorIs(t, service.NewSessionService(sessionRepository).CheckSession(ctx, claims), service.ErrSessionRevoked)
+	})
+
+	t.Run("keeps the token when the password is refused", func(t *testing.T) {
+		userService, userRepository, tokenRepository, sessionRepository := newUserService()
+		tokenRepository.On("FindByToken", ctx, "TOKEN").
+			Return(&datastruct.Token{Token: "TOKEN", UserId: 7, ExpiredAt: time.Now().Add(time.Hour)}, nil)
+		userRepository.On("FindById", ctx, uint(7)).
+			Return(&datastruct.User{ID: 7, Username: "jdoe", Email: "jdoe@example.com"}, nil)
+
+		_, err := userService.ResetPassword(ctx, dto.ResetPasswordRequest{Token: "TOKEN", NewPassword: "short"})
+
+		assert.Error(t, err)
+		tokenRepository.AssertNotCalled(t, "DeleteToken", mock.Anything, mock.Anything)
+		sessionRepository.AssertNotCalled(t, "RevokeSessionsByUserId", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestUserService_SwitchOrganization(t *testing.T) {
+	t.Setenv("JWT_SECRET", "secret_jwt")
+	t.Setenv("JWT_EXPIRY_TIME", "100000")
+	ctx := context.TODO()
+
+	t.Run("member switches organization", func(t *testing.T) {
+		userRepository := new(mocks.UserRepositoryInterface)
+		roleRepository := new(mocks.RoleRepositoryInterface)
+		organizationRepository := new(mocks.OrganizationRepositoryInterface)
+		groupRepository := new(mocks.GroupRepositoryInterface)
+		userService := service.NewUserService(userRepository, new(mocks.TokenRepositoryInterface), roleRepository,
+			organizationRepository, groupRepository, new(mocks.SessionRepositoryInterface), nil, nil, nil)
+
+		userRepository.Mock.On("FindById", mock.Anything, uint(9)).
+			Return(&datastruct.User{ID: 9, Role: datastruct.GeneralUser.String()}, nil)
+		organizationRepository.Mock.On("FindMember", ctx, uint(4), uint(9)).
+			Return(&datastruct.OrganizationMember{OrganizationId: 4, UserId: 9, Role: datastruct.Admin.String()}, nil)
+		roleRepository.Mock.On("FindRoleByName", ctx, datastruct.Admin.String()).
+			Return(&datastruct.Role{Name: datastruct.Admin.String()}, nil)
+		groupRepository.Mock.On("ListGroupsByUserId", ctx, uint(4), uint(9)).Return([]datastruct.Group{}, nil)
+
+		res, err := userService.SwitchOrganization(ctx, &service.Claims{SessionID: "session", UserID: 9, OrgID: 1}, 4)
+
+		assert.NoError(t, err)
+		claims, err := service.ParseJWT(res.Token)
+		assert.NoError(t, err)
+		assert.Equal(t, "session", claims.SessionID)
+		assert.Equal(t, uint(4), claims.OrgID)
+		assert.Equal(t, datastruct.Admin.String(), claims.Role)
+	})
+
+	t.Run("non member is rejected", func(t *testing.T) {
+		userRepository := new(mocks.UserRepositoryInterface)
+		organizationRepository := new(mocks.OrganizationRepositoryInterface)
+		userService := service.NewUserService(userRepository, new(mocks.TokenRepositoryInterface),
+			new(mocks.RoleRepositoryInterface), organizationRepository, new(mocks.GroupRepositoryInterface),
+			new(mocks.SessionRepositoryInterface), nil, nil, nil)
+
+		userRepository.Mock.On("FindById", mock.Anything, uint(9)).
+			Return(&datastruct.User{ID: 9, Role: datastruct.GeneralUser.String()}, nil)
+		organizationRepository.Mock.On("FindMember", ctx, uint(4), uint(9)).Return(nil, gorm.ErrRecordNotFound)
+
+		res, err := userService.SwitchOrganization(ctx, &service.Claims{UserID: 9, OrgID: 1}, 4)
+
+		assert.Nil(t, res)
+		assert.ErrorIs(t, err, service.ErrNotOrganizationMember)
+	})
+
+	t.Run("disabled account is rejected and audited", func(t *testing.T) {
+		userRepository := new(mocks.UserRepositoryInterface)
+		auditRecorder := new(servicemocks.AuditRecorder)
+		userService := service.NewUserService(userRepository, new(mocks.TokenRepositoryInterface),
+			new(mocks.RoleRepositoryInterface), new(mocks.OrganizationRepositoryInterface),
+			new(mocks.GroupRepositoryInterface), new(mocks.SessionRepositoryInterface), nil, auditRecorder, nil)
+
+		userRepository.Mock.On("FindById", mock.Anything, uint(9)).
+			Return(&datastruct.User{ID: 9, Role: datastruct.GeneralUser.String(), Disabled: true}, nil)
+		auditRecorder.On("Record", ctx, mock.MatchedBy(func(event *datastruct.AuditEvent) bool {
+			return event.Outcome == datastruct.AuditFailure && event.Details["reason"] == "disabled"
+		})).Return()
+
+		res, err := userService.SwitchOrganization(ctx, &service.Claims{UserID: 9, OrgID: 1}, 4)
+
+		assert.Nil(t, res)
+		assert.ErrorIs(t, err, service.ErrInvalidCredentials)
+		auditRecorder.AssertExpectations(t)
+	})
+}
+
+func TestUserService_Login_Lockout(t *testing.T) {
+	ctx := context.TODO()
+	email := "test@example.com"
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+
+	newUserService := func(
+		userRepository *mocks.UserRepositoryInterface,
+		mailer *mailmocks.MailInterface,
+	) *service.UserService {
+		return service.NewUserService(userRepository, new(mocks.TokenRepositoryInterface),
+			new(mocks.RoleRepositoryInterface), new(mocks.OrganizationRepositoryInterface),
+			new(mocks.GroupRepositoryInterface), new(mocks.SessionRepositoryInterface), mailer, nil, nil)
+	}
+
+	t.Run("locks for an escalating duration once the threshold is reached", func(t *testing.T) {
+		t.Setenv("LOGIN_LOCKOUT_THRESHOLD", "3")
+		t.Setenv("LOGIN_LOCKOUT_DURATION", "10m")
+		userRepository, mailer := new(mocks.UserRepositoryInterface), new(mailmocks.MailInterface)
+		user := &datastruct.User{ID: 9, Email: email, PasswordHash: string(hashedPassword), LockoutCount: 2}
+		userRepository.On("FindByEmail", ctx, email).Return(user, nil)
+		userRepository.On("RecordFailedLogin", ctx, uint(9)).Return(3, nil)
+		userRepository.On("LockUserById", ctx, uint(9), mock.MatchedBy(func(until time.Time) bool {
+			// The third consecutive lock lasts four times the first one
+			lockout := time.Until(until)
+			return lockout > 39*time.Minute && lockout <= 40*time.Minute
+		})).Return(nil)
+		mailer.On("Send", mock.Anything).Return(true, nil)
+
+		_, err := newUserService(userRepository, mailer).Login(ctx, dto.LoginRequest{Email: email, Password: "wrong"})
+
+		assert.ErrorIs(t, err, service.ErrInvalidCredentials)
+		userRepository.AssertExpectations(t)
+		assert.Equal(t, []string{email}, mailer.Calls[0].Arguments.Get(0).(*mail_server.SendEmailRequest).To)
+	})
+
+	t.Run("rejects the right password while locked", func(t *testing.T) {
+		userRepository := new(mocks.UserRepositoryInterface)
+		lockedUntil := time.Now().Add(time.Hour)
+		user := &datastruct.User{ID: 9, Email: email, PasswordHash: string(hashedPassword), LockedUntil: &lockedUntil}
+		userRepository.On("FindByEmail", ctx, email).Return(user, nil)
+
+		_, err := newUserService(userRepository, nil).Login(ctx, dto.LoginRequest{Email: email, Password: "password"})
+
+		assert.ErrorIs(t, err, service.ErrInvalidCredentials)
+		userRepository.AssertNotCalled(t, "RecordFailedLogin", mock.Anything, mock.Anything)
+	})
+
+	t.Run("does not reveal unknown emails", func(t *testing.T) {
+		userRepository := new(mocks.UserRepositoryInterface)
+		userRepository.On("FindByEmail", ctx, email).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := newUserService(userRepository, nil).Login(ctx, dto.LoginRequest{Email: email, Password: "password"})
+
+		assert.ErrorIs(t, err, service.ErrInvalidCredentials)
+	})
+
+	t.Run("does not reveal disabled accounts", func(t *testing.T) {
+		userRepository := new(mocks.UserRepositoryInterface)
+		user := &datastruct.User{ID: 9, Email: email, PasswordHash: string(hashedPassword), Disabled: true}
+		userRepository.On("FindByEmail", ctx, email).Return(user, nil)
+
+		_, err := newUserService(userRepository, nil).Login(ctx, dto.LoginRequest{Email: email, Password: "password"})
+
+		assert.ErrorIs(t, err, service.ErrInvalidCredentials)
+	})
+}
+
+func TestUserService_Login_PasswordExpired(t *testing.T) {
+	t.Setenv("JWT_SECRET", "secret_jwt")
+	t.Setenv("JWT_EXPIRY_TIME", "3600")
+	t.Setenv("PASSWORD_MAX_AGE_DAYS", "90")
+	ctx := context.TODO()
+	email := "test@example.com"
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+
+	userRepository := new(mocks.UserRepositoryInterface)
+	organizationRepository := new(mocks.OrganizationRepositoryInterface)
+	sessionRepository := new(mocks.SessionRepositoryInterface)
+	userService := service.NewUserService(userRepository, new(mocks.TokenRepositoryInterface),
+		new(mocks.RoleRepositoryInterface), organizationRepository, new(mocks.GroupRepositoryInterface),
+		sessionRepository, nil, nil, nil)
+
+	user := &datastruct.User{
+		ID:                9,
+		OrganizationId:    2,
+		Email:             email,
+		Role:              datastruct.GeneralUser.String(),
+		PasswordHash:      string(hashedPassword),
+		PasswordChangedAt: time.Now().AddDate(0, 0, -91),
+	}
+	userRepository.On("FindByEmail", ctx, email).Return(user, nil)
+	userRepository.On("UpdatePasswordById", ctx, uint(9), mock.Anything).Return(user, nil)
+	organizationRepository.On("FindMember", ctx, uint(2), uint(9)).Return(&datastruct.OrganizationMember{
+		OrganizationId: 2,
+		UserId:         9,
+		Role:           datastruct.Admin.String(),
+	}, nil)
+	sessionRepository.On("CreateSession", ctx, mock.Anything).Return(nil)
+
+	res, err := userService.Login(ctx, dto.LoginRequest{Email: email, Password: "password"})
+
+	assert.NoError(t, err)
+	assert.True(t, res.PasswordChangeRequired)
+
+	// The token grants nothing but changing the password
+	claims, err := service.ParseJWT(res.Token)
+	assert.NoError(t, err)
+	assert.True(t, claims.PasswordChangeRequired)
+	assert.Empty(t, claims.Permissions)
+}
+
+func TestUserService_Login_RiskyDevice(t *testing.T) {
+	t.Setenv("JWT_SECRET", "secret_jwt")
+	t.Setenv("JWT_EXPIRY_TIME", "3600")
+	ctx := clientinfo.WithInfo(context.TODO(), clientinfo.Info{
+		IP:        "203.0.113.7",
+		UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:128.0) Gecko/20100101 Firefox/128.0",
+	})
+	email := "test@example.com"
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+
+	setup := func(ctx context.Context) (
+		*service.UserService,
+		*mocks.UserRepositoryInterface,
+		*mocks.LoginDeviceRepositoryInterface,
+		*mailmocks.MailInterface,
+	) {
+		userRepository := new(mocks.UserRepositoryInterface)
+		roleRepository := new(mocks.RoleRepositoryInterface)
+		organizationRepository := new(mocks.OrganizationRepositoryInterface)
+		groupRepository := new(mocks.GroupRepositoryInterface)
+		sessionRepository := new(mocks.SessionRepositoryInterface)
+		loginDeviceRepository := new(mocks.LoginDeviceRepositoryInterface)
+		mailer := new(mailmocks.MailInterface)
+
+		user := &datastruct.User{
+			ID:                9,
+			OrganizationId:    2,
+			Email:             email,
+			Role:              datastruct.GeneralUser.String(),
+			PasswordHash:      string(hashedPassword),
+			PasswordChangedAt: time.Now(),
+		}
+		userRepository.On("FindByEmail", ctx, email).Return(user, nil)
+		userRepository.On("FindById", ctx, uint(9)).Return(user, nil)
+		userRepository.On("UpdatePasswordById", ctx, uint(9), mock.Anything).Return(user, nil)
+		organizationRepository.On("FindMember", ctx, uint(2), uint(9)).Return(&datastruct.OrganizationMember{
+			OrganizationId: 2,
+			UserId:         9,
+			Role:           datastruct.GeneralUser.String(),
+		}, nil)
+		roleRepository.On("FindRoleByName", ctx, datastruct.GeneralUser.String()).
+			Return(&datastruct.Role{Name: datastruct.GeneralUser.String()}, nil)
+		groupRepository.On("ListGroupsByUserId", ctx, uint(2), uint(9)).Return([]datastruct.Group{}, nil)
+		sessionRepository.On("CreateSession", ctx, mock.Anything).Return(nil)
+		loginDeviceRepository.On("ListLoginDevices", ctx, uint(9)).Return([]datastruct.LoginDevice{{
+			UserId:      9,
+			Fingerprint: "known",
+			Name:        "Chrome on macOS",
+			LastSeenAt:  time.Now().Add(-24 * time.Hour),
+		}}, nil)
+		loginDeviceRepository.On("SaveLoginDevice", ctx, mock.Anything).Return(nil)
+		mailer.On("Send", mock.Anything).Return(true, nil)
+
+		return service.NewUserService(userRepository, new(mocks.TokenRepositoryInterface), roleRepository,
+			organizationRepository, groupRepository, sessionRepository, mailer, nil,
+			loginDeviceRepository), userRepository, loginDeviceRepository, mailer
+	}
+
+	t.Run("alerts the user of a login from a new device", func(t *testing.T) {
+		userService, _, loginDeviceRepository, mailer := setup(ctx)
+
+		res, err := userService.Login(ctx, dto.LoginRequest{Email: email, Password: "password", DeviceID: "laptop"})
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, res.Token)
+		assert.False(t, res.MFARequired)
+		saved := loginDeviceRepository.Calls[1].Arguments.Get(1).(*datastruct.LoginDevice)
+		assert.Equal(t, "Firefox on Windows", saved.Name)
+		assert.Equal(t, "203.0.113.7", saved.IP)
+		alert := mailer.Calls[0].Arguments.Get(0).(*mail_server.SendEmailRequest)
+		assert.Equal(t, []string{email}, alert.To)
+		assert.Contains(t, alert.Html, "Firefox on Windows")
+	})
+
+	t.Run("escapes the client in the alert", func(t *testing.T) {
+		ctx := clientinfo.WithInfo(context.TODO(), clientinfo.Info{IP: `<img src="x">`, UserAgent: "curl/8.4.0"})
+		userService, _, _, mailer := setup(ctx)
+
+		_, err := userService.Login(ctx, dto.LoginRequest{Email: email, Password: "password", DeviceID: "laptop"})
+
+		assert.NoError(t, err)
+		alert := mailer.Calls[0].Arguments.Get(0).(*mail_server.SendEmailRequest)
+		assert.Contains(t, alert.Html, "(IP &lt;img src=&#34;x&#34;&gt;)")
+	})
+
+	t.Run("holds back risky logins until the emailed code is entered", func(t *testing.T) {
+		t.Setenv("LOGIN_RISK_MFA_THRESHOLD", "40")
+		userService, userRepository, loginDeviceRepository, mailer := setup(ctx)
+		user, _ := userRepository.FindByEmail(ctx, email)
+		user.FailedLoginAttempts = 1
+		userRepository.On("RecordFailedLogin", ctx, uint(9)).Return(2, nil)
+		userRepository.On("ResetFailedLogins", ctx, uint(9)).Return(nil)
+		var challenge *datastruct.LoginChallenge
+		loginDeviceRepository.On("DeleteExpiredLoginChallenges", ctx, mock.Anything).Return(int64(0), nil)
+		loginDeviceRepository.On("CreateLoginChallenge", ctx, mock.Anything).Run(func(args mock.Arguments) {
+			challenge = args.Get(1).(*datastruct.LoginChallenge)
+			challenge.ID = 4
+		}).Return(nil)
+
+		res, err := userService.Login(ctx, dto.LoginRequest{Email: email, Password: "password", DeviceID: "laptop"})
+
+		assert.NoError(t, err)
+		assert.True(t, res.MFARequired)
+		assert.Empty(t, res.Token)
+		assert.NotEqual(t, res.MFAToken, challenge.TokenHash)
+		loginDeviceRepository.AssertNotCalled(t, "SaveLoginDevice", mock.Anything, mock.Anything)
+		// The failed logins are kept until the code is entered
+		userRepository.AssertNotCalled(t, "ResetFailedLogins", mock.Anything, mock.Anything)
+		html := mailer.Calls[0].Arguments.Get(0).(*mail_server.SendEmailRequest).Html
+		code := strings.TrimPrefix(html, "<p> Your sign in code is ")[:6]
+
+		loginDeviceRepository.On("FindLoginChallengeByTokenHash", ctx, challenge.TokenHash).Return(challenge, nil)
+		loginDeviceRepository.On("RecordLoginChallengeAttempt", ctx, uint(4)).Return(1, nil).Once()
+		loginDeviceRepository.On("RecordLoginChallengeAttempt", ctx, uint(4)).Return(2, nil).Once()
+		loginDeviceRepository.On("DeleteLoginChallenge", ctx, uint(4)).Return(nil)
+
+		// The code only completes the login from the device it was sent for
+		_, err = userService.VerifyLoginChallenge(ctx, dto.VerifyLoginRequest{
+			Token:    res.MFAToken,
+			Code:     code,
+			DeviceID: "phone",
+		})
+		assert.ErrorIs(t, err, service.ErrLoginChallengeInvalid)
+		userRepository.AssertCalled(t, "RecordFailedLogin", ctx, uint(9))
+
+		verified, err := userService.VerifyLoginChallenge(ctx, dto.VerifyLoginRequest{
+			Token:    res.MFAToken,
+			Code:     code,
+			DeviceID: "laptop",
+		})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, verified.Token)
+		loginDeviceRepository.AssertCalled(t, "DeleteLoginChallenge", ctx, uint(4))
+		loginDeviceRepository.AssertCalled(t, "SaveLoginDevice", ctx, mock.Anything)
+		userRepository.AssertCalled(t, "ResetFailedLogins", ctx, uint(9))
+	})
+
+	t.Run("drops the challenge after too many codes", func(t *testing.T) {
+		userService, _, loginDeviceRepository, _ := setup(ctx)
+		challenge := &datastruct.LoginChallenge{ID: 4, UserId: 9, ExpiredAt: time.Now().Add(time.Minute)}
+		loginDeviceRepository.On("FindLoginChallengeByTokenHash", ctx, mock.Anything).Return(challenge, nil)
+		loginDeviceRepository.On("RecordLoginChallengeAttempt", ctx, uint(4)).Return(6, nil)
+		loginDeviceRepository.On("DeleteLoginChallenge", ctx, uint(4)).Return(nil)
+
+		_, err := userService.VerifyLoginChallenge(ctx, dto.VerifyLoginRequest{Token: "token", Code: "123456"})
+
+		assert.ErrorIs(t, err, service.ErrLoginChallengeInvalid)
+		loginDeviceRepository.AssertCalled(t, "DeleteLoginChallenge", ctx, uint(4))
+	})
+}
+
+func TestLoginRiskSettings(t *testing.T) {
+	t.Run("defaults to never asking for a code", func(t *testing.T) {
+		_, threshold, err := service.LoginRiskSettings()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, threshold)
+	})
+
+	for _, c := range []struct{ key, value string }{
+		{"LOGIN_RISK_MFA_THRESHOLD", "high"},
+		{"LOGIN_RISK_MFA_THRESHOLD", "-1"},
+		{"LOGIN_RISK_FAR_DISTANCE_KM", "far"},
+		{"LOGIN_RISK_FAR_DISTANCE_KM", "0"},
+		{"LOGIN_RISK_FAR_DISTANCE_KM", "NaN"},
+	} {
+		t.Run("rejects "+c.key+"="+c.value, func(t *testing.T) {
+			t.Setenv(c.key, c.value)
+
+			_, _, err := service.LoginRiskSettings()
+
+			assert.ErrorContains(t, err, c.key)
+		})
+	}
+}
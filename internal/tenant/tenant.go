@@ -0,0 +1,16 @@
+package tenant
+
+import "context"
+
+type contextKey struct{}
+
+// WithOrganization scopes the repositories to the given organization for the lifetime of ctx.
+func WithOrganization(ctx context.Context, organizationID uint) context.Context {
+	return context.WithValue(ctx, contextKey{}, organizationID)
+}
+
+// OrganizationFromContext returns the organization set by WithOrganization.
+func OrganizationFromContext(ctx context.Context) (uint, bool) {
+	organizationID, ok := ctx.Value(contextKey{}).(uint)
+	return organizationID, ok && organizationID != 0
+}
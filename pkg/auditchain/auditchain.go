@@ -0,0 +1,115 @@
+// Package auditchain makes an append-only log tamper-evident: every entry is hashed together with the hash
+// of the entry before it, so altering, removing or reordering an entry breaks every later link. Signed
+// checkpoints of the chain head additionally reveal a chain rewritten from the altered entry onwards.
+package auditchain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Hash returns the hash linking an entry to the chain: the SHA-256 of the hash of the previous entry, empty
+// for the first one, and the canonical encoding of the entry, in hex.
+func Hash(prevHash string, payload []byte) string {
+	digest := sha256.New()
+	digest.Write([]byte(prevHash))
+	digest.Write([]byte{0})
+	digest.Write(payload)
+	return hex.EncodeToString(digest.Sum(nil))
+}
+
+// Link is an entry of the chain as stored: its ID, the hashes recorded with it and its canonical encoding.
+type Link struct {
+	ID       uint64
+	PrevHash string
+	Hash     string
+	Payload  []byte
+}
+
+// Break is a place the chain does not hold.
+type Break struct {
+	ID     uint64 `json:"id"`
+	Reason string `json:"reason"`
+}
+
+// Report sums up the verification of a chain. Unchained counts the entries recorded before chaining was
+// enabled, which precede the first hashed entry and cannot be verified.
+type Report struct {
+	Entries     int     `json:"entries"`
+	Unchained   int     `json:"unchained"`
+	Checkpoints int     `json:"checkpoints"`
+	Head        string  `json:"head"`
+	Breaks      []Break `json:"breaks"`
+}
+
+// OK reports whether the chain held everywhere.
+func (r *Report) OK() bool {
+	return len(r.Breaks) == 0
+}
+
+// Verifier walks a chain in ID order, checking each link and the checkpoints taken of it. After a break it
+// carries on from the stored hash of the broken entry, so each tampered entry is reported once.
+type Verifier struct {
+	report      Report
+	chained     bool
+	checkpoints map[uint64][]Checkpoint
+}
+
+// NewVerifier returns a verifier matching the chain against checkpoints, whose signatures are checked
+// beforehand by the caller.
+func NewVerifier(checkpoints ...Checkpoint) *Verifier {
+	v := &Verifier{checkpoints: map[uint64][]Checkpoint{}, report: Report{Breaks: []Break{}}}
+	for _, checkpoint := range checkpoints {
+		v.checkpoints[checkpoint.LastID] = append(v.checkpoints[checkpoint.LastID], checkpoint)
+	}
+	return v
+}
+
+// Add checks the next link of the chain.
+func (v *Verifier) Add(link Link) {
+	v.report.Entries++
+	if link.Hash == "" && !v.chained {
+		v.report.Unchained++
+		return
+	}
+	v.chained = true
+
+	switch {
+	case link.PrevHash != v.report.Head:
+		v.addBreak(link.ID, "the previous hash does not match the entry before, which was removed or altered")
+	case Hash(link.PrevHash, link.Payload) != link.Hash:
+		v.addBreak(link.ID, "the entry does not match its hash")
+	}
+
+	for _, checkpoint := range v.checkpoints[link.ID] {
+		v.report.Checkpoints++
+		if checkpoint.Hash != link.Hash {
+			v.addBreak(link.ID, fmt.Sprintf("the chain differs from the checkpoint of %s",
+				checkpoint.CreatedAt.Format(time.RFC3339)))
+		}
+	}
+	delete(v.checkpoints, link.ID)
+	v.report.Head = link.Hash
+}
+
+// Finish returns the report, with a break for every checkpoint of an entry that no longer exists.
+func (v *Verifier) Finish() Report {
+	ids := make([]uint64, 0, len(v.checkpoints))
+	for id := range v.checkpoints {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		v.report.Checkpoints += len(v.checkpoints[id])
+		v.addBreak(id, "the entry of a checkpoint is missing")
+	}
+	v.checkpoints = map[uint64][]Checkpoint{}
+	return v.report
+}
+
+func (v *Verifier) addBreak(id uint64, reason string) {
+	v.report.Breaks = append(v.report.Breaks, Break{ID: id, Reason: reason})
+}
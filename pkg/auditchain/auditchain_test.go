@@ -0,0 +1,127 @@
+package auditchain_test
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fyfirman/auth-management-go/pkg/auditchain"
+	"github.com/stretchr/testify/assert"
+)
+
+// chain links entries with the given payloads, starting at ID 1.
+func chain(payloads ...string) []auditchain.Link {
+	var links []auditchain.Link
+	prevHash := ""
+	for i, payload := range payloads {
+		link := auditchain.Link{ID: uint64(i + 1), PrevHash: prevHash, Payload: []byte(payload)}
+		link.Hash = auditchain.Hash(prevHash, link.Payload)
+		links = append(links, link)
+		prevHash = link.Hash
+	}
+	return links
+}
+
+func verify(links []auditchain.Link, checkpoints ...auditchain.Checkpoint) auditchain.Report {
+	verifier := auditchain.NewVerifier(checkpoints...)
+	for _, link := range links {
+		verifier.Add(link)
+	}
+	return verifier.Finish()
+}
+
+func TestVerifier(t *testing.T) {
+	t.Run("intact chain", func(t *testing.T) {
+		links := append([]auditchain.Link{{ID: 1, Payload: []byte("legacy")}}, chain("a", "b", "c")...)
+		links[1].ID, links[2].ID, links[3].ID = 2, 3, 4
+
+		report := verify(links)
+
+		assert.True(t, report.OK(), report.Breaks)
+		assert.Equal(t, 4, report.Entries)
+		assert.Equal(t, 1, report.Unchained)
+		assert.Equal(t, links[3].Hash, report.Head)
+	})
+
+	t.Run("altered entry", func(t *testing.T) {
+		links := chain("a", "b", "c")
+		links[1].Payload = []byte("B")
+
+		report := verify(links)
+
+		assert.Equal(t, []auditchain.Break{{ID: 2, Reason: "the entry does not match its hash"}}, report.Breaks)
+	})
+
+	t.Run("removed entry", func(t *testing.T) {
+		links := chain("a", "b", "c")
+
+		report := verify([]auditchain.Link{links[0], links[2]})
+
+		assert.Len(t, report.Breaks, 1)
+		assert.Equal(t, uint64(3), report.Breaks[0].ID)
+	})
+
+	t.Run("hash removed from a chained entry", func(t *testing.T) {
+		links := chain("a", "b", "c")
+		links[1].Hash = ""
+
+		report := verify(links)
+
+		assert.Equal(t, []uint64{2, 3}, []uint64{report.Breaks[0].ID, report.Breaks[1].ID})
+	})
+}
+
+func TestVerifier_Checkpoints(t *testing.T) {
+	_, key, _ := ed25519.GenerateKey(nil)
+	links := chain("a", "b", "c")
+	now := time.Date(2026, 10, 20, 12, 0, 0, 0, time.UTC)
+	checkpoint := auditchain.NewCheckpoint(2, links[1].Hash, now, key)
+
+	t.Run("matching checkpoint", func(t *testing.T) {
+		report := verify(links, checkpoint)
+
+		assert.True(t, report.OK(), report.Breaks)
+		assert.Equal(t, 1, report.Checkpoints)
+	})
+
+	t.Run("chain rewritten after the checkpoint", func(t *testing.T) {
+		// Every link holds, only the checkpoint tells the entries were replaced
+		rewritten := chain("a", "B", "c")
+
+		report := verify(rewritten, checkpoint)
+
+		assert.Len(t, report.Breaks, 1)
+		assert.Equal(t, uint64(2), report.Breaks[0].ID)
+	})
+
+	t.Run("entry of the checkpoint removed", func(t *testing.T) {
+		report := verify(links[:1], checkpoint)
+
+		assert.Equal(t, []auditchain.Break{{ID: 2, Reason: "the entry of a checkpoint is missing"}}, report.Breaks)
+	})
+}
+
+func TestCheckpoint_Verify(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize)
+	key, err := auditchain.ParseSigningKey(base64.StdEncoding.EncodeToString(seed))
+	assert.NoError(t, err)
+	publicKey, err := auditchain.ParsePublicKey(base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)))
+	assert.NoError(t, err)
+
+	checkpoint := auditchain.NewCheckpoint(7, "abc", time.Now(), key)
+	assert.NoError(t, checkpoint.Verify(publicKey))
+
+	forged := checkpoint
+	forged.LastID = 8
+	assert.ErrorIs(t, forged.Verify(publicKey), auditchain.ErrInvalidSignature)
+
+	checkpoints, err := auditchain.ReadCheckpoints(strings.NewReader(
+		`{"last_id":7,"hash":"abc","created_at":"2026-10-20T12:00:00Z","signature":"c2ln"}` + "\n\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), checkpoints[0].LastID)
+
+	_, err = auditchain.ParseSigningKey("c2hvcnQ=")
+	assert.Error(t, err)
+}
@@ -0,0 +1,106 @@
+package auditchain
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSignature is returned for checkpoints not signed by the expected key.
+var ErrInvalidSignature = errors.New("invalid checkpoint signature")
+
+// Checkpoint attests the head of the chain at a point in time: the ID and hash of its last entry, signed
+// with Ed25519. Kept outside of the database, checkpoints reveal a chain recomputed after tampering.
+type Checkpoint struct {
+	LastID    uint64    `json:"last_id"`
+	Hash      string    `json:"hash"`
+	CreatedAt time.Time `json:"created_at"`
+	Signature string    `json:"signature"`
+}
+
+// NewCheckpoint signs the head of the chain, the entry lastID of hash hash, at now.
+func NewCheckpoint(lastID uint64, hash string, now time.Time, key ed25519.PrivateKey) Checkpoint {
+	checkpoint := Checkpoint{LastID: lastID, Hash: hash, CreatedAt: now.UTC().Truncate(time.Second)}
+	checkpoint.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, checkpoint.message()))
+	return checkpoint
+}
+
+// Verify checks the signature of the checkpoint with publicKey.
+func (c Checkpoint) Verify(publicKey ed25519.PublicKey) error {
+	signature, err := base64.StdEncoding.DecodeString(c.Signature)
+	if err != nil || !ed25519.Verify(publicKey, c.message(), signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// message is what checkpoints sign.
+func (c Checkpoint) message() []byte {
+	return []byte(strings.Join([]string{
+		"auditchain checkpoint",
+		strconv.FormatUint(c.LastID, 10),
+		c.Hash,
+		c.CreatedAt.UTC().Format(time.RFC3339),
+	}, "\n"))
+}
+
+// ReadCheckpoints reads checkpoints written one JSON object per line, skipping blank lines.
+func ReadCheckpoints(r io.Reader) ([]Checkpoint, error) {
+	var checkpoints []Checkpoint
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var checkpoint Checkpoint
+		if err := json.Unmarshal([]byte(text), &checkpoint); err != nil {
+			return nil, fmt.Errorf("checkpoint on line %d: %w", line, err)
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	return checkpoints, scanner.Err()
+}
+
+// ParseSigningKey reads an Ed25519 private key from the base64 of its 32-byte seed.
+func ParseSigningKey(text string) (ed25519.PrivateKey, error) {
+	seed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(text))
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("signing keys must be the base64 of a %d-byte Ed25519 seed", ed25519.SeedSize)
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+// ParsePublicKey reads an Ed25519 public key from its base64.
+func ParsePublicKey(text string) (ed25519.PublicKey, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(text))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("public keys must be the base64 of a %d-byte Ed25519 key", ed25519.PublicKeySize)
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// AppendCheckpoint adds checkpoint as a line of JSON to the file at path, creating it if needed.
+func AppendCheckpoint(path string, checkpoint Checkpoint) error {
+	line, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
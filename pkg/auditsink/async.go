@@ -0,0 +1,164 @@
+package auditsink
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrBufferFull is returned for events dropped because the destination cannot keep up.
+	ErrBufferFull = errors.New("audit sink buffer is full")
+	// ErrClosed is returned for events written after Close.
+	ErrClosed = errors.New("audit sink is closed")
+)
+
+// AsyncOptions tunes Async. Zero values take the defaults of DefaultAsyncOptions.
+type AsyncOptions struct {
+	// BufferSize is how many events wait for delivery at most.
+	BufferSize int
+	// BlockTimeout is how long Write waits for room in a full buffer before dropping the event. Negative
+	// values drop right away.
+	BlockTimeout time.Duration
+	// MaxRetries is how many times a failed delivery is retried, RetryDelay apart, doubling each time.
+	// Negative values never retry.
+	MaxRetries int
+	RetryDelay time.Duration
+	// Logf reports dropped events and failed deliveries, log.Printf by default.
+	Logf func(format string, args ...any)
+}
+
+func DefaultAsyncOptions() AsyncOptions {
+	return AsyncOptions{
+		BufferSize:   1024,
+		BlockTimeout: 50 * time.Millisecond,
+		MaxRetries:   3,
+		RetryDelay:   200 * time.Millisecond,
+		Logf:         log.Printf,
+	}
+}
+
+// Async delivers events to a sink from a background goroutine through a bounded buffer. When the sink
+// falls behind, writers wait up to BlockTimeout for room and the event is then dropped, so auditing slows
+// requests down by a bounded amount and never stops them.
+type Async struct {
+	sink    Sink
+	options AsyncOptions
+	queue   chan Event
+	done    chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+
+	dropped atomic.Uint64
+	failed  atomic.Uint64
+}
+
+func NewAsync(sink Sink, options AsyncOptions) *Async {
+	defaults := DefaultAsyncOptions()
+	if options.BufferSize <= 0 {
+		options.BufferSize = defaults.BufferSize
+	}
+	if options.BlockTimeout == 0 {
+		options.BlockTimeout = defaults.BlockTimeout
+	}
+	if options.MaxRetries == 0 {
+		options.MaxRetries = defaults.MaxRetries
+	}
+	if options.RetryDelay == 0 {
+		options.RetryDelay = defaults.RetryDelay
+	}
+	if options.Logf == nil {
+		options.Logf = defaults.Logf
+	}
+
+	a := &Async{
+		sink:    sink,
+		options: options,
+		queue:   make(chan Event, options.BufferSize),
+		done:    make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+// Write queues event for delivery, returning ErrBufferFull when it had to be dropped.
+func (a *Async) Write(event Event) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.closed {
+		return ErrClosed
+	}
+
+	select {
+	case a.queue <- event:
+		return nil
+	default:
+	}
+	if a.options.BlockTimeout > 0 {
+		timer := time.NewTimer(a.options.BlockTimeout)
+		defer timer.Stop()
+		select {
+		case a.queue <- event:
+			return nil
+		case <-timer.C:
+		}
+	}
+	a.dropped.Add(1)
+	return ErrBufferFull
+}
+
+// Close delivers the events still buffered, then closes the sink.
+func (a *Async) Close() error {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.queue)
+	}
+	a.mu.Unlock()
+
+	<-a.done
+	return a.sink.Close()
+}
+
+// Dropped returns how many events were dropped because the buffer was full.
+func (a *Async) Dropped() uint64 {
+	return a.dropped.Load()
+}
+
+// Failed returns how many events could not be delivered after every retry.
+func (a *Async) Failed() uint64 {
+	return a.failed.Load()
+}
+
+func (a *Async) run() {
+	defer close(a.done)
+
+	var reported uint64
+	for event := range a.queue {
+		a.deliver(event)
+		if dropped := a.dropped.Load(); dropped > reported {
+			a.options.Logf("Audit sink buffer full, dropped %d events", dropped-reported)
+			reported = dropped
+		}
+	}
+}
+
+func (a *Async) deliver(event Event) {
+	delay := a.options.RetryDelay
+	for attempt := 0; ; attempt++ {
+		err := a.sink.Write(event)
+		if err == nil {
+			return
+		}
+		if attempt >= max(a.options.MaxRetries, 0) {
+			a.failed.Add(1)
+			a.options.Logf("Failed to deliver the audit event %d: %v", event.ID, err)
+			return
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
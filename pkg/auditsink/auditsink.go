@@ -0,0 +1,81 @@
+// Package auditsink delivers audit events to external systems such as a SIEM: syslog collectors, log files
+// picked up by shippers, in JSON or CEF. Sinks are wrapped in Async so a slow or unreachable destination
+// never holds up the requests being audited.
+package auditsink
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Event is an audit event as delivered to sinks.
+type Event struct {
+	ID             uint64            `json:"id"`
+	Time           time.Time         `json:"time"`
+	OrganizationID *uint             `json:"organization_id,omitempty"`
+	Type           string            `json:"type"`
+	Outcome        string            `json:"outcome"`
+	ActorID        *uint             `json:"actor_id,omitempty"`
+	TargetID       *uint             `json:"target_id,omitempty"`
+	IP             string            `json:"ip,omitempty"`
+	UserAgent      string            `json:"user_agent,omitempty"`
+	Details        map[string]string `json:"details,omitempty"`
+	Hash           string            `json:"hash,omitempty"`
+}
+
+// failed reports whether the event records a failure, which sinks flag with a higher severity.
+func (e Event) failed() bool {
+	return e.Outcome == "failure"
+}
+
+// Sink delivers events to one destination. Write may block on the destination; wrap sinks in Async to
+// bound that.
+type Sink interface {
+	Write(event Event) error
+	Close() error
+}
+
+// Formatter renders an event as a single line, without the line break.
+type Formatter interface {
+	Format(event Event) ([]byte, error)
+}
+
+// JSON formats events as JSON objects, one per line in files.
+type JSON struct{}
+
+func (JSON) Format(event Event) ([]byte, error) {
+	return json.Marshal(event)
+}
+
+// ParseFormat returns the formatter named "json" or "cef".
+func ParseFormat(name string) (Formatter, error) {
+	switch name {
+	case "json":
+		return JSON{}, nil
+	case "cef":
+		return CEF{Vendor: "fyfirman", Product: "auth-management", Version: "1.0"}, nil
+	default:
+		return nil, fmt.Errorf("unknown audit format %q, expected json or cef", name)
+	}
+}
+
+// Multi writes every event to each of sinks, returning their errors joined.
+type Multi []Sink
+
+func (m Multi) Write(event Event) error {
+	var errs []error
+	for _, sink := range m {
+		errs = append(errs, sink.Write(event))
+	}
+	return errors.Join(errs...)
+}
+
+func (m Multi) Close() error {
+	var errs []error
+	for _, sink := range m {
+		errs = append(errs, sink.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,236 @@
+package auditsink_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fyfirman/auth-management-go/pkg/auditsink"
+	"github.com/stretchr/testify/assert"
+)
+
+func testEvent(id uint64) auditsink.Event {
+	actorID := uint(9)
+	return auditsink.Event{
+		ID:        id,
+		Time:      time.Date(2026, 10, 20, 12, 0, 0, 0, time.UTC),
+		Type:      "login",
+		Outcome:   "failure",
+		ActorID:   &actorID,
+		IP:        "203.0.113.7",
+		UserAgent: "curl/8.4.0",
+		Details:   map[string]string{"reason": "invalid_password"},
+	}
+}
+
+func TestCEF_Format(t *testing.T) {
+	event := testEvent(1)
+	event.UserAgent = "evil=agent\nwith|pipe"
+	cef := auditsink.CEF{Vendor: "fyfirman", Product: "auth|management", Version: "1.0"}
+
+	line, err := cef.Format(event)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `CEF:0|fyfirman|auth\|management|1.0|login|login|7|rt=1792497600000 externalId=1 act=login `+
+		`outcome=failure src=203.0.113.7 requestClientApplication=evil\=agent\nwith|pipe suid=9 `+
+		`cs2Label=details cs2={"reason":"invalid_password"}`, string(line))
+}
+
+func TestSyslog_UDP(t *testing.T) {
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+
+	sink, err := auditsink.NewSyslog("udp://"+listener.LocalAddr().String(), auditsink.JSON{}, "auth-management")
+	assert.NoError(t, err)
+	sink.Hostname = "auth-1"
+	defer sink.Close()
+
+	assert.NoError(t, sink.Write(testEvent(1)))
+
+	buffer := make([]byte, 2048)
+	listener.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := listener.ReadFrom(buffer)
+	assert.NoError(t, err)
+	message := string(buffer[:n])
+	// authpriv (10) * 8 + warning (4)
+	assert.True(t, strings.HasPrefix(message, "<84>1 2026-10-20T12:00:00.000000Z auth-1 auth-management "), message)
+	assert.Contains(t, message, ` login - {"id":1,`)
+}
+
+func TestSyslog_TCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+
+	received := make(chan string, 2)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			reader := bufio.NewReader(conn)
+			for {
+				// Octet counting: the length of the message, a space, then the message
+				prefix, err := reader.ReadString(' ')
+				if err != nil {
+					conn.Close()
+					break
+				}
+				length, _ := strconv.Atoi(strings.TrimSpace(prefix))
+				buffer := make([]byte, length)
+				if _, err := io.ReadFull(reader, buffer); err != nil {
+					conn.Close()
+					break
+				}
+				received <- string(buffer)
+			}
+		}
+	}()
+
+	sink, err := auditsink.NewSyslog("tcp://"+listener.Addr().String(), auditsink.CEF{}, "auth-management")
+	assert.NoError(t, err)
+	defer sink.Close()
+
+	assert.NoError(t, sink.Write(testEvent(1)))
+	assert.NoError(t, sink.Write(testEvent(2)))
+
+	for _, want := range []string{"externalId=1 ", "externalId=2 "} {
+		select {
+		case message := <-received:
+			assert.Contains(t, message, want)
+			assert.Contains(t, message, "|login|login|7|")
+		case <-time.After(time.Second):
+			t.Fatal("the collector received nothing")
+		}
+	}
+}
+
+func TestNewSyslog_Invalid(t *testing.T) {
+	for _, rawURL := range []string{"http://siem:514", "udp://siem", "tcp://%zz"} {
+		_, err := auditsink.NewSyslog(rawURL, auditsink.JSON{}, "auth-management")
+		assert.Error(t, err, rawURL)
+	}
+}
+
+func TestFile_Rotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.ndjson")
+	line, _ := auditsink.JSON{}.Format(testEvent(1))
+	// Two events fit in a file
+	sink := auditsink.NewFile(path, auditsink.JSON{}, int64(2*(len(line)+1)), 2)
+
+	for id := uint64(1); id <= 7; id++ {
+		assert.NoError(t, sink.Write(testEvent(id)))
+	}
+	assert.NoError(t, sink.Close())
+
+	readIDs := func(path string) []uint64 {
+		content, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		var ids []uint64
+		for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+			var event auditsink.Event
+			assert.NoError(t, json.Unmarshal([]byte(line), &event))
+			ids = append(ids, event.ID)
+		}
+		return ids
+	}
+	assert.Equal(t, []uint64{7}, readIDs(path))
+	assert.Equal(t, []uint64{5, 6}, readIDs(path+".1"))
+	assert.Equal(t, []uint64{3, 4}, readIDs(path+".2"))
+	_, err := os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err))
+}
+
+// blockingSink holds every write until released, then records the event. entered receives each write.
+type blockingSink struct {
+	mu      sync.Mutex
+	entered chan uint64
+	release chan struct{}
+	events  []uint64
+	fail    int
+}
+
+func newBlockingSink() *blockingSink {
+	return &blockingSink{entered: make(chan uint64, 16), release: make(chan struct{})}
+}
+
+func (s *blockingSink) Write(event auditsink.Event) error {
+	s.entered <- event.ID
+	<-s.release
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail > 0 {
+		s.fail--
+		return errors.New("collector unavailable")
+	}
+	s.events = append(s.events, event.ID)
+	return nil
+}
+
+func (s *blockingSink) Close() error {
+	return nil
+}
+
+func TestAsync(t *testing.T) {
+	quiet := func(string, ...any) {}
+
+	t.Run("drops events once the buffer is full", func(t *testing.T) {
+		sink := newBlockingSink()
+		async := auditsink.NewAsync(sink, auditsink.AsyncOptions{BufferSize: 2, BlockTimeout: -1, Logf: quiet})
+
+		// The first event is being delivered, the next two wait in the buffer
+		assert.NoError(t, async.Write(testEvent(1)))
+		<-sink.entered
+		assert.NoError(t, async.Write(testEvent(2)))
+		assert.NoError(t, async.Write(testEvent(3)))
+		assert.ErrorIs(t, async.Write(testEvent(4)), auditsink.ErrBufferFull)
+
+		close(sink.release)
+		assert.NoError(t, async.Close())
+		assert.Equal(t, []uint64{1, 2, 3}, sink.events)
+		assert.Equal(t, uint64(1), async.Dropped())
+		assert.ErrorIs(t, async.Write(testEvent(5)), auditsink.ErrClosed)
+	})
+
+	t.Run("waits for room before dropping", func(t *testing.T) {
+		sink := newBlockingSink()
+		async := auditsink.NewAsync(sink, auditsink.AsyncOptions{
+			BufferSize:   1,
+			BlockTimeout: time.Second,
+			Logf:         quiet,
+		})
+		assert.NoError(t, async.Write(testEvent(1)))
+		<-sink.entered
+		assert.NoError(t, async.Write(testEvent(2)))
+
+		go close(sink.release)
+		assert.NoError(t, async.Write(testEvent(3)))
+
+		assert.NoError(t, async.Close())
+		assert.Equal(t, []uint64{1, 2, 3}, sink.events)
+		assert.Zero(t, async.Dropped())
+	})
+
+	t.Run("retries failed deliveries", func(t *testing.T) {
+		sink := newBlockingSink()
+		sink.fail = 2
+		close(sink.release)
+		async := auditsink.NewAsync(sink, auditsink.AsyncOptions{MaxRetries: 2, RetryDelay: time.Millisecond, Logf: quiet})
+
+		assert.NoError(t, async.Write(testEvent(1)))
+		assert.NoError(t, async.Close())
+		assert.Equal(t, []uint64{1}, sink.events)
+		assert.Zero(t, async.Failed())
+	})
+}
@@ -0,0 +1,105 @@
+package auditsink
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// File appends events to a file, one per line: newline-delimited JSON with the JSON formatter. Once the
+// file would exceed MaxSize bytes it is renamed with the suffix .1, older files shifting to .2 and so on
+// up to MaxBackups, and a new file is started. A MaxSize of 0 never rotates.
+type File struct {
+	Path       string
+	Formatter  Formatter
+	MaxSize    int64
+	MaxBackups int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+func NewFile(path string, formatter Formatter, maxSize int64, maxBackups int) *File {
+	return &File{Path: path, Formatter: formatter, MaxSize: maxSize, MaxBackups: maxBackups}
+}
+
+func (f *File) Write(event Event) error {
+	line, err := f.Formatter.Format(event)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return err
+		}
+	}
+	if f.MaxSize > 0 && f.size > 0 && f.size+int64(len(line)) > f.MaxSize {
+		if err := f.rotate(); err != nil {
+			return err
+		}
+	}
+
+	written, err := f.file.Write(line)
+	f.size += int64(written)
+	return err
+}
+
+func (f *File) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+func (f *File) open() error {
+	file, err := os.OpenFile(f.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o640)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.file, f.size = file, info.Size()
+	return nil
+}
+
+// rotate shifts the backups, dropping the oldest, moves the current file to the first backup and opens a
+// new one.
+func (f *File) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	f.file = nil
+
+	if f.MaxBackups <= 0 {
+		if err := os.Remove(f.Path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return f.open()
+	}
+	for i := f.MaxBackups - 1; i >= 1; i-- {
+		err := os.Rename(backupPath(f.Path, i), backupPath(f.Path, i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(f.Path, backupPath(f.Path, 1)); err != nil {
+		return err
+	}
+	return f.open()
+}
+
+func backupPath(path string, index int) string {
+	return fmt.Sprintf("%s.%d", path, index)
+}
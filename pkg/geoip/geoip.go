@@ -0,0 +1,149 @@
+// Package geoip resolves IP addresses to locations from an offline database file, so logins are located
+// without sending client IPs to a third party.
+package geoip
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/netip"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// earthRadiusKm is the mean radius of the Earth.
+const earthRadiusKm = 6371.0
+
+// Location is where an IP address is registered. Country is an ISO 3166-1 alpha-2 code.
+type Location struct {
+	Country   string  `json:"country"`
+	Region    string  `json:"region,omitempty"`
+	City      string  `json:"city,omitempty"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// String names the location for people, such as "Lyon, Auvergne-Rhone-Alpes, FR".
+func (l Location) String() string {
+	var parts []string
+	for _, part := range []string{l.City, l.Region, l.Country} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Distance returns the great-circle distance between a and b in kilometers.
+func Distance(a, b Location) float64 {
+	toRadians := func(degrees float64) float64 { return degrees * math.Pi / 180 }
+	latA, latB := toRadians(a.Latitude), toRadians(b.Latitude)
+	deltaLat, deltaLon := latB-latA, toRadians(b.Longitude-a.Longitude)
+
+	h := math.Pow(math.Sin(deltaLat/2), 2) + math.Cos(latA)*math.Cos(latB)*math.Pow(math.Sin(deltaLon/2), 2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+type ipRange struct {
+	start    netip.Addr
+	end      netip.Addr
+	location uint32
+}
+
+// Database holds the address ranges of a database file in memory, sorted for binary search. Locations are
+// shared between the ranges of a same place.
+type Database struct {
+	ranges    []ipRange
+	locations []Location
+}
+
+// Open loads the database file at path, gzip compressed when its name ends in .gz.
+func Open(path string) (*Database, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, err
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+	return Read(reader)
+}
+
+// Read loads a database in the CSV layout of the DB-IP "IP to City Lite" database, IPv4 and IPv6 ranges
+// alike: start address, end address, continent, country, region, city, latitude, longitude.
+func Read(r io.Reader) (*Database, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 8
+	reader.ReuseRecord = true
+
+	db := &Database{}
+	locationIndex := map[Location]uint32{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		start, startErr := netip.ParseAddr(record[0])
+		end, endErr := netip.ParseAddr(record[1])
+		if startErr != nil || endErr != nil || start.Is4() != end.Is4() || end.Less(start) {
+			return nil, fmt.Errorf("line %d: invalid address range %s-%s", line, record[0], record[1])
+		}
+		latitude, latErr := strconv.ParseFloat(record[6], 64)
+		longitude, lonErr := strconv.ParseFloat(record[7], 64)
+		if latErr != nil || lonErr != nil {
+			return nil, fmt.Errorf("line %d: invalid coordinates", line)
+		}
+
+		location := Location{
+			Country:   record[3],
+			Region:    record[4],
+			City:      record[5],
+			Latitude:  latitude,
+			Longitude: longitude,
+		}
+		index, ok := locationIndex[location]
+		if !ok {
+			index = uint32(len(db.locations))
+			locationIndex[location] = index
+			db.locations = append(db.locations, location)
+		}
+		db.ranges = append(db.ranges, ipRange{start: start, end: end, location: index})
+	}
+
+	sort.Slice(db.ranges, func(i, j int) bool { return db.ranges[i].start.Less(db.ranges[j].start) })
+	return db, nil
+}
+
+// Lookup returns the location of ip, ok false for addresses outside of every range such as private ones.
+func (d *Database) Lookup(ip string) (Location, bool) {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return Location{}, false
+	}
+	addr = addr.Unmap()
+
+	// The last range starting at or before addr is the only one that can hold it
+	i := sort.Search(len(d.ranges), func(i int) bool { return addr.Less(d.ranges[i].start) }) - 1
+	if i < 0 || d.ranges[i].end.Less(addr) || d.ranges[i].start.Is4() != addr.Is4() {
+		return Location{}, false
+	}
+	return d.locations[d.ranges[i].location], true
+}
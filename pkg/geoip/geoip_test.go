@@ -0,0 +1,84 @@
+package geoip_test
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fyfirman/auth-management-go/pkg/geoip"
+	"github.com/stretchr/testify/assert"
+)
+
+const testDatabase = `1.0.0.0,1.0.0.255,OC,AU,Queensland,South Brisbane,-27.4748,153.017
+2.0.0.0,2.0.255.255,EU,FR,Ile-de-France,Paris,48.8534,2.3488
+2001:db8::,2001:db8::ffff,AS,JP,Tokyo,Tokyo,35.6895,139.692
+2.1.0.0,2.1.0.255,EU,FR,Auvergne-Rhone-Alpes,Lyon,45.7485,4.84669
+`
+
+func TestDatabase_Lookup(t *testing.T) {
+	db, err := geoip.Read(strings.NewReader(testDatabase))
+	assert.NoError(t, err)
+
+	cases := []struct {
+		ip   string
+		city string
+		ok   bool
+	}{
+		{"1.0.0.1", "South Brisbane", true},
+		{"2.0.128.7", "Paris", true},
+		{"2.1.0.255", "Lyon", true},
+		{"::ffff:2.0.0.1", "Paris", true},
+		{"2001:db8::42", "Tokyo", true},
+		{"2.0.255.255", "Paris", true},
+		{"2.1.1.0", "", false},
+		{"10.0.0.1", "", false},
+		{"0.0.0.1", "", false},
+		{"not an ip", "", false},
+	}
+	for _, c := range cases {
+		t.Run(c.ip, func(t *testing.T) {
+			location, ok := db.Lookup(c.ip)
+			assert.Equal(t, c.ok, ok)
+			assert.Equal(t, c.city, location.City)
+		})
+	}
+}
+
+func TestOpen_Gzip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dbip-city-lite.csv.gz")
+	file, err := os.Create(path)
+	assert.NoError(t, err)
+	writer := gzip.NewWriter(file)
+	writer.Write([]byte(testDatabase))
+	writer.Close()
+	file.Close()
+
+	db, err := geoip.Open(path)
+
+	assert.NoError(t, err)
+	location, ok := db.Lookup("2.1.0.1")
+	assert.True(t, ok)
+	assert.Equal(t, "Lyon, Auvergne-Rhone-Alpes, FR", location.String())
+}
+
+func TestRead_Invalid(t *testing.T) {
+	for _, text := range []string{
+		"2.0.0.255,2.0.0.0,EU,FR,,,48.85,2.35\n",
+		"2.0.0.0,2001:db8::,EU,FR,,,48.85,2.35\n",
+		"2.0.0.0,2.0.0.255,EU,FR,,,north,2.35\n",
+		"2.0.0.0,2.0.0.255,EU,FR\n",
+	} {
+		_, err := geoip.Read(strings.NewReader(text))
+		assert.Error(t, err, text)
+	}
+}
+
+func TestDistance(t *testing.T) {
+	paris := geoip.Location{Latitude: 48.8534, Longitude: 2.3488}
+	lyon := geoip.Location{Latitude: 45.7485, Longitude: 4.84669}
+
+	assert.InDelta(t, 392, geoip.Distance(paris, lyon), 5)
+	assert.Zero(t, geoip.Distance(paris, paris))
+}
@@ -0,0 +1,159 @@
+// Package loginrisk scores how unusual a successful login is compared to the earlier logins of the
+// account: from an unseen device, far from the places it was used from, faster than one can travel, or
+// after failed attempts.
+package loginrisk
+
+import (
+	"time"
+
+	"github.com/fyfirman/auth-management-go/pkg/geoip"
+)
+
+// Reasons a login is considered risky.
+const (
+	ReasonNewDevice        = "new_device"
+	ReasonNewCountry       = "new_country"
+	ReasonFarLocation      = "far_location"
+	ReasonImpossibleTravel = "impossible_travel"
+	ReasonRecentFailures   = "recent_failures"
+)
+
+// maxScore caps the score of a login.
+const maxScore = 100
+
+// Sighting is a device an account logged in from, where it was last seen from and when. Location is nil
+// when the IP could not be located.
+type Sighting struct {
+	Fingerprint string
+	Location    *geoip.Location
+	SeenAt      time.Time
+}
+
+// Attempt is the login being assessed. RecentFailures counts the failed logins since the last success.
+type Attempt struct {
+	Fingerprint    string
+	Location       *geoip.Location
+	At             time.Time
+	RecentFailures int
+}
+
+// Assessment is the score of a login out of 100 and the reasons adding up to it. DistanceKm is the
+// distance to the nearest place the account was used from, when both are located.
+type Assessment struct {
+	Score       int
+	Reasons     []string
+	NewDevice   bool
+	FarLocation bool
+	DistanceKm  float64
+}
+
+// Has reports whether reason contributed to the score.
+func (a Assessment) Has(reason string) bool {
+	for _, r := range a.Reasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// Scorer weighs the signals of a risky login. FarDistanceKm is the distance from every known place past
+// which a login is far, and MaxSpeedKmh the fastest a person travels between two logins.
+type Scorer struct {
+	NewDevice        int
+	NewCountry       int
+	FarLocation      int
+	ImpossibleTravel int
+	PerFailure       int
+	MaxFailures      int
+	FarDistanceKm    float64
+	MaxSpeedKmh      float64
+}
+
+func DefaultScorer() Scorer {
+	return Scorer{
+		NewDevice:        40,
+		NewCountry:       20,
+		FarLocation:      25,
+		ImpossibleTravel: 30,
+		PerFailure:       5,
+		MaxFailures:      20,
+		FarDistanceKm:    500,
+		MaxSpeedKmh:      1000,
+	}
+}
+
+// Assess scores attempt against the devices the account used before. The first login of an account has
+// nothing to compare to and only scores its failures.
+func (s Scorer) Assess(attempt Attempt, history []Sighting) Assessment {
+	var assessment Assessment
+	add := func(reason string, weight int) {
+		assessment.Reasons = append(assessment.Reasons, reason)
+		assessment.Score += weight
+	}
+
+	if attempt.RecentFailures > 0 {
+		add(ReasonRecentFailures, min(attempt.RecentFailures*s.PerFailure, s.MaxFailures))
+	}
+	if len(history) > 0 {
+		s.assessDevice(attempt, history, add)
+		assessment.NewDevice = assessment.Has(ReasonNewDevice)
+		assessment.DistanceKm, assessment.FarLocation = s.assessLocation(attempt, history, add)
+	}
+
+	assessment.Score = min(assessment.Score, maxScore)
+	return assessment
+}
+
+func (s Scorer) assessDevice(attempt Attempt, history []Sighting, add func(string, int)) {
+	for _, sighting := range history {
+		if sighting.Fingerprint == attempt.Fingerprint {
+			return
+		}
+	}
+	add(ReasonNewDevice, s.NewDevice)
+}
+
+// assessLocation compares the location of attempt to the located sightings, returning the distance to the
+// nearest one and whether it is far.
+func (s Scorer) assessLocation(attempt Attempt, history []Sighting, add func(string, int)) (float64, bool) {
+	if attempt.Location == nil {
+		return 0, false
+	}
+
+	var latest *Sighting
+	nearest := -1.0
+	knownCountry := false
+	for i, sighting := range history {
+		if sighting.Location == nil {
+			continue
+		}
+		distance := geoip.Distance(*attempt.Location, *sighting.Location)
+		if nearest < 0 || distance < nearest {
+			nearest = distance
+		}
+		knownCountry = knownCountry || sighting.Location.Country == attempt.Location.Country
+		if latest == nil || sighting.SeenAt.After(latest.SeenAt) {
+			latest = &history[i]
+		}
+	}
+	if latest == nil {
+		return 0, false
+	}
+
+	if !knownCountry {
+		add(ReasonNewCountry, s.NewCountry)
+	}
+	far := nearest > s.FarDistanceKm
+	if far {
+		add(ReasonFarLocation, s.FarLocation)
+	}
+
+	// Too far from the last login for the time in between
+	distance := geoip.Distance(*attempt.Location, *latest.Location)
+	hours := attempt.At.Sub(latest.SeenAt).Hours()
+	if distance > s.FarDistanceKm && (hours <= 0 || distance/hours > s.MaxSpeedKmh) {
+		add(ReasonImpossibleTravel, s.ImpossibleTravel)
+	}
+	return nearest, far
+}
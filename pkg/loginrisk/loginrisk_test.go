@@ -0,0 +1,90 @@
+package loginrisk_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fyfirman/auth-management-go/pkg/geoip"
+	"github.com/fyfirman/auth-management-go/pkg/loginrisk"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	paris  = &geoip.Location{Country: "FR", City: "Paris", Latitude: 48.8534, Longitude: 2.3488}
+	lyon   = &geoip.Location{Country: "FR", City: "Lyon", Latitude: 45.7485, Longitude: 4.84669}
+	tokyo  = &geoip.Location{Country: "JP", City: "Tokyo", Latitude: 35.6895, Longitude: 139.692}
+	berlin = &geoip.Location{Country: "DE", City: "Berlin", Latitude: 52.5244, Longitude: 13.4105}
+)
+
+func TestScorer_Assess(t *testing.T) {
+	now := time.Date(2026, 10, 20, 12, 0, 0, 0, time.UTC)
+	history := []loginrisk.Sighting{
+		{Fingerprint: "laptop", Location: paris, SeenAt: now.Add(-2 * time.Hour)},
+		{Fingerprint: "phone", SeenAt: now.Add(-24 * time.Hour)},
+	}
+
+	cases := []struct {
+		name    string
+		attempt loginrisk.Attempt
+		history []loginrisk.Sighting
+		score   int
+		reasons []string
+	}{
+		{
+			name:    "first login",
+			attempt: loginrisk.Attempt{Fingerprint: "laptop", Location: tokyo, At: now},
+			score:   0,
+		},
+		{
+			name:    "known device nearby",
+			attempt: loginrisk.Attempt{Fingerprint: "laptop", Location: lyon, At: now},
+			history: history,
+			score:   0,
+		},
+		{
+			name:    "new device nearby",
+			attempt: loginrisk.Attempt{Fingerprint: "tablet", Location: lyon, At: now},
+			history: history,
+			score:   40,
+			reasons: []string{loginrisk.ReasonNewDevice},
+		},
+		{
+			name:    "known device abroad, travelled in time",
+			attempt: loginrisk.Attempt{Fingerprint: "laptop", Location: berlin, At: now},
+			history: history,
+			score:   45,
+			reasons: []string{loginrisk.ReasonNewCountry, loginrisk.ReasonFarLocation},
+		},
+		{
+			name:    "new device across the world two hours later",
+			attempt: loginrisk.Attempt{Fingerprint: "tablet", Location: tokyo, At: now, RecentFailures: 8},
+			history: history,
+			score:   100,
+			reasons: []string{
+				loginrisk.ReasonRecentFailures,
+				loginrisk.ReasonNewDevice,
+				loginrisk.ReasonNewCountry,
+				loginrisk.ReasonFarLocation,
+				loginrisk.ReasonImpossibleTravel,
+			},
+		},
+		{
+			name:    "unlocated login",
+			attempt: loginrisk.Attempt{Fingerprint: "laptop", At: now, RecentFailures: 1},
+			history: history,
+			score:   5,
+			reasons: []string{loginrisk.ReasonRecentFailures},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assessment := loginrisk.DefaultScorer().Assess(c.attempt, c.history)
+
+			assert.Equal(t, c.score, assessment.Score)
+			assert.Equal(t, c.reasons, assessment.Reasons)
+			assert.Equal(t, assessment.Has(loginrisk.ReasonNewDevice), assessment.NewDevice)
+			assert.Equal(t, assessment.Has(loginrisk.ReasonFarLocation), assessment.FarLocation)
+		})
+	}
+}
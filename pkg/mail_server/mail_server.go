@@ -14,7 +14,7 @@ type SendEmailRequest struct {
 }
 
 type MailInterface interface {
-	Send(request *SendEmailRequest)
+	Send(request *SendEmailRequest) (bool, error)
 }
 
 type Mail struct {
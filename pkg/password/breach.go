@@ -0,0 +1,237 @@
+package password
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BreachChecker reports how many times a password appears in known data breaches, 0 when it does not.
+type BreachChecker interface {
+	Breached(ctx context.Context, password string) (int, error)
+}
+
+// prefixLength is the number of hexadecimal characters of the SHA-1 hash looked up as a range, as in the
+// Pwned Passwords range API.
+const prefixLength = 5
+
+// hashPassword returns the uppercase hexadecimal SHA-1 hash of password split into its range prefix and
+// suffix.
+func hashPassword(password string) (string, string) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	return hash[:prefixLength], hash[prefixLength:]
+}
+
+// parseLine splits a "<hash>:<count>" line, returning the hash in uppercase.
+func parseLine(text string) (string, int, error) {
+	hash, rawCount, ok := strings.Cut(text, ":")
+	count, err := strconv.Atoi(rawCount)
+	if !ok || err != nil {
+		return "", 0, errors.New("expected <hash>:<count>")
+	}
+	return strings.ToUpper(hash), count, nil
+}
+
+// parseRange reads "<hash>:<count>" lines into counts keyed by hash suffix. Lines hold either the 35
+// character suffix of a range or the full 40 character hash; full hashes are only kept when they start
+// with prefix.
+func parseRange(r io.Reader, prefix string) (map[string]int, error) {
+	counts := map[string]int{}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		hash, count, err := parseLine(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		switch {
+		case len(hash) == 40 && strings.HasPrefix(hash, prefix):
+			counts[hash[prefixLength:]] = count
+		case len(hash) == 40-prefixLength:
+			counts[hash] = count
+		case len(hash) != 40:
+			return nil, fmt.Errorf("line %d: %q is not a SHA-1 hash", line, hash)
+		}
+	}
+	return counts, scanner.Err()
+}
+
+// RangeFile looks passwords up in a local copy of the Pwned Passwords corpus, either a single file of
+// "<SHA-1>:<count>" lines sorted by hash, as in the "ordered by hash" download, searched in place, or a
+// directory of range files named after their 5 character prefix ("21BD1.txt") holding "<suffix>:<count>"
+// lines, read on demand.
+type RangeFile struct {
+	dir  string
+	file *os.File
+	size int64
+}
+
+// OpenRangeFile opens the corpus at path. A single file is kept open and its first line checked, so a wrong
+// path or format is reported here rather than on the first lookup.
+func OpenRangeFile(path string) (*RangeFile, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return &RangeFile{dir: path}, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	f := &RangeFile{file: file, size: info.Size()}
+	hash, _, err := f.lineAfter(0)
+	if err == nil && hash == "" {
+		err = errors.New("no hashes")
+	}
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return f, nil
+}
+
+// Close releases the single file of the corpus.
+func (f *RangeFile) Close() error {
+	if f.file == nil {
+		return nil
+	}
+	return f.file.Close()
+}
+
+func (f *RangeFile) Breached(_ context.Context, password string) (int, error) {
+	prefix, suffix := hashPassword(password)
+	if f.file != nil {
+		return f.search(prefix + suffix)
+	}
+
+	file, err := os.Open(filepath.Join(f.dir, prefix+".txt"))
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	counts, err := parseRange(file, prefix)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", file.Name(), err)
+	}
+	return counts[suffix], nil
+}
+
+// RangeClient queries a Pwned Passwords compatible range API. Only the first 5 characters of the SHA-1
+// hash leave the service (k-anonymity), and responses are padded so their size reveals nothing either.
+type RangeClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewRangeClient queries baseURL followed by the hash prefix, for example
+// "https://api.pwnedpasswords.com/range/".
+func NewRangeClient(baseURL string) *RangeClient {
+	return &RangeClient{baseURL: baseURL, httpClient: &http.Client{Timeout: 5 * time.Second}}
+}
+
+func (c *RangeClient) Breached(ctx context.Context, password string) (int, error) {
+	prefix, suffix := hashPassword(password)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+prefix, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Add-Padding", "true")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("range API answered %s", resp.Status)
+	}
+
+	counts, err := parseRange(resp.Body, prefix)
+	if err != nil {
+		return 0, err
+	}
+	// Padding entries have a count of 0
+	return counts[suffix], nil
+}
+
+// search binary searches the sorted file for hash. It looks for the first offset whose next line holds a
+// hash at or after hash, reading one line per step.
+func (f *RangeFile) search(hash string) (int, error) {
+	low, high := int64(0), f.size
+	for low < high {
+		middle := low + (high-low)/2
+		found, _, err := f.lineAfter(middle)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", f.file.Name(), err)
+		}
+		if found != "" && found < hash {
+			low = middle + 1
+		} else {
+			high = middle
+		}
+	}
+
+	found, count, err := f.lineAfter(low)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", f.file.Name(), err)
+	}
+	if found != hash {
+		return 0, nil
+	}
+	return count, nil
+}
+
+// lineAfter parses the first non-empty line starting at or after offset. The hash is empty at the end of
+// the file.
+func (f *RangeFile) lineAfter(offset int64) (string, int, error) {
+	start := max(offset-1, 0)
+	reader := bufio.NewReader(io.NewSectionReader(f.file, start, f.size-start))
+	if offset > 0 {
+		// The byte before offset tells whether offset starts a line.
+		if _, err := reader.ReadString('\n'); err == io.EOF {
+			return "", 0, nil
+		} else if err != nil {
+			return "", 0, err
+		}
+	}
+
+	for {
+		text, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", 0, err
+		}
+		if text = strings.TrimSpace(text); text != "" {
+			hash, count, parseErr := parseLine(text)
+			if parseErr == nil && len(hash) != 40 {
+				parseErr = fmt.Errorf("%q is not a SHA-1 hash", hash)
+			}
+			return hash, count, parseErr
+		}
+		if err == io.EOF {
+			return "", 0, nil
+		}
+	}
+}
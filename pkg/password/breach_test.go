@@ -0,0 +1,127 @@
+package password_test
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/fyfirman/auth-management-go/pkg/password"
+	"github.com/stretchr/testify/assert"
+)
+
+// SHA-1 of "password" is 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8
+const breachedSuffix = "1E4C9B93F3F0682250B6CF8331B7EE68FD8"
+
+func TestRangeFile(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("loads a file of full hashes", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "pwned-passwords.txt")
+		content := "000000005AD76BD555C1D6D771DE417A4B87E4B4:4\n5baa61e4c9b93f3f0682250b6cf8331b7ee68fd8:9545824\n"
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+		checker, err := password.OpenRangeFile(path)
+		assert.NoError(t, err)
+
+		count, err := checker.Breached(ctx, "password")
+		assert.NoError(t, err)
+		assert.Equal(t, 9545824, count)
+
+		count, err = checker.Breached(ctx, "c0rrect-horse-battery")
+		assert.NoError(t, err)
+		assert.Zero(t, count)
+	})
+
+	t.Run("searches a large sorted file", func(t *testing.T) {
+		var lines []string
+		for i := 0; i < 1000; i++ {
+			sum := sha1.Sum([]byte("password" + strconv.Itoa(i)))
+			lines = append(lines, strings.ToUpper(hex.EncodeToString(sum[:]))+":"+strconv.Itoa(i+1))
+		}
+		sort.Strings(lines)
+		path := filepath.Join(t.TempDir(), "pwned-passwords.txt")
+		assert.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\r\n")), 0o600))
+
+		checker, err := password.OpenRangeFile(path)
+		assert.NoError(t, err)
+		defer checker.Close()
+
+		for i := 0; i < 1000; i++ {
+			count, err := checker.Breached(ctx, "password"+strconv.Itoa(i))
+			assert.NoError(t, err)
+			assert.Equal(t, i+1, count)
+		}
+		count, err := checker.Breached(ctx, "password")
+		assert.NoError(t, err)
+		assert.Zero(t, count)
+	})
+
+	t.Run("reads range files from a directory", func(t *testing.T) {
+		dir := t.TempDir()
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "5BAA6.txt"), []byte(breachedSuffix+":3\r\n"), 0o600))
+
+		checker, err := password.OpenRangeFile(dir)
+		assert.NoError(t, err)
+
+		count, err := checker.Breached(ctx, "password")
+		assert.NoError(t, err)
+		assert.Equal(t, 3, count)
+
+		count, err = checker.Breached(ctx, "c0rrect-horse-battery")
+		assert.NoError(t, err)
+		assert.Zero(t, count)
+	})
+
+	t.Run("rejects malformed files", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "pwned-passwords.txt")
+		assert.NoError(t, os.WriteFile(path, []byte("not a hash\n"), 0o600))
+
+		_, err := password.OpenRangeFile(path)
+		assert.Error(t, err)
+
+		assert.NoError(t, os.WriteFile(path, nil, 0o600))
+		_, err = password.OpenRangeFile(path)
+		assert.Error(t, err)
+	})
+}
+
+func TestRangeClient(t *testing.T) {
+	var requested, padding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested, padding = r.URL.Path, r.Header.Get("Add-Padding")
+		w.Write([]byte("0018A45C4D1DEF81644B54AB7F969B88D65:0\r\n" + breachedSuffix + ":42\r\n"))
+	}))
+	defer server.Close()
+
+	count, err := password.NewRangeClient(server.URL+"/range/").Breached(context.TODO(), "password")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 42, count)
+	// Only the prefix of the hash is sent
+	assert.Equal(t, "/range/5BAA6", requested)
+	assert.Equal(t, "true", padding)
+}
+
+type breachList map[string]int
+
+func (b breachList) Breached(_ context.Context, password string) (int, error) {
+	return b[password], nil
+}
+
+func TestPolicy_Check_Breaches(t *testing.T) {
+	policy := password.DefaultPolicy()
+	policy.Breaches = breachList{"Tr0ub4dor&3": 1}
+
+	violations, err := policy.Check(context.TODO(), "Tr0ub4dor&3")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []password.Violation{{Rule: "breached", Message: "appears in a known data breach"}}, violations)
+}
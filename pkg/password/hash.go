@@ -0,0 +1,214 @@
+package password
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownHashFormat is returned for encoded hashes no registered verifier recognizes.
+var ErrUnknownHashFormat = errors.New("unknown password hash format")
+
+// Verifier checks passwords against encoded hashes of one format.
+type Verifier interface {
+	// Identifies reports whether encoded is a hash of this format.
+	Identifies(encoded string) bool
+	Verify(password string, encoded string) (bool, error)
+	// Check validates encoded without a password, as hashes imported from other systems are: it must parse
+	// completely, carry a digest and stay within the cost limits, so it cannot match every password or
+	// make each login burn CPU and memory.
+	Check(encoded string) error
+}
+
+// Hasher produces encoded hashes that carry their algorithm and parameters, so they can be verified
+// after the configuration changed.
+type Hasher interface {
+	Verifier
+	Hash(password string) (string, error)
+	// NeedsRehash reports whether encoded was produced with other parameters than the hasher's.
+	NeedsRehash(encoded string) bool
+}
+
+// Hashers hashes new passwords with a preferred hasher and verifies hashes of every registered format.
+type Hashers struct {
+	preferred Hasher
+	verifiers []Verifier
+	// pepper is the current pepper, nil when passwords are not peppered, and peppers all the known ones by
+	// version.
+	pepper  *Pepper
+	peppers map[string]Pepper
+}
+
+// NewHashers hashes with preferred and also verifies the hashes of others, typically the hashers
+// configured before.
+func NewHashers(preferred Hasher, others ...Verifier) *Hashers {
+	return &Hashers{preferred: preferred, verifiers: append([]Verifier{preferred}, others...)}
+}
+
+// WithPeppers returns hashers peppering new passwords with the first of peppers. The others still verify
+// the hashes they peppered, which are then replaced like those of outdated hashers, so peppers rotate as
+// users log in.
+func (h *Hashers) WithPeppers(peppers ...Pepper) *Hashers {
+	peppered := &Hashers{preferred: h.preferred, verifiers: h.verifiers, peppers: map[string]Pepper{}}
+	for _, pepper := range peppers {
+		peppered.peppers[pepper.Version] = pepper
+	}
+	if len(peppers) > 0 {
+		peppered.pepper = &peppers[0]
+	}
+	return peppered
+}
+
+// Identifies reports whether encoded is a hash of one of the registered formats.
+func (h *Hashers) Identifies(encoded string) bool {
+	if _, inner, ok := splitPeppered(encoded); ok {
+		encoded = inner
+	}
+	for _, verifier := range h.verifiers {
+		if verifier.Identifies(encoded) {
+			return true
+		}
+	}
+	return false
+}
+
+// Check runs the Check of the verifier identifying encoded, peppered or not.
+func (h *Hashers) Check(encoded string) error {
+	if version, inner, ok := splitPeppered(encoded); ok {
+		if _, known := h.peppers[version]; !known {
+			return ErrUnknownPepper
+		}
+		encoded = inner
+	}
+	for _, verifier := range h.verifiers {
+		if verifier.Identifies(encoded) {
+			return verifier.Check(encoded)
+		}
+	}
+	return ErrUnknownHashFormat
+}
+
+func (h *Hashers) Hash(password string) (string, error) {
+	if h.pepper == nil {
+		return h.preferred.Hash(password)
+	}
+	encoded, err := h.preferred.Hash(h.pepper.apply(password))
+	if err != nil {
+		return "", err
+	}
+	return pepperedPrefix + h.pepper.Version + encoded, nil
+}
+
+// Verify reports whether password matches encoded, and whether encoded should be replaced by a hash of
+// the preferred hasher because it uses another format, outdated parameters or not the current pepper.
+func (h *Hashers) Verify(password string, encoded string) (match bool, rehash bool, err error) {
+	var stalePepper bool
+	if version, inner, ok := splitPeppered(encoded); ok {
+		pepper, known := h.peppers[version]
+		if !known {
+			return false, false, ErrUnknownPepper
+		}
+		password, encoded = pepper.apply(password), inner
+		stalePepper = h.pepper == nil || h.pepper.Version != version
+	} else {
+		stalePepper = h.pepper != nil
+	}
+
+	for _, verifier := range h.verifiers {
+		if !verifier.Identifies(encoded) {
+			continue
+		}
+		match, err := verifier.Verify(password, encoded)
+		if err != nil || !match {
+			return false, false, err
+		}
+		outdated := verifier != Verifier(h.preferred) || h.preferred.NeedsRehash(encoded)
+		return true, outdated || stalePepper, nil
+	}
+	return false, false, ErrUnknownHashFormat
+}
+
+// phc is a hash in the PHC string format: $<id>[$v=<version>][$<param>=<value>(,...)]$<salt>$<hash>, with
+// the salt and hash in unpadded standard base64.
+type phc struct {
+	id      string
+	version int
+	params  map[string]string
+	salt    []byte
+	hash    []byte
+}
+
+func parsePHC(encoded string) (*phc, error) {
+	fields := strings.Split(encoded, "$")
+	if len(fields) < 4 || fields[0] != "" {
+		return nil, ErrUnknownHashFormat
+	}
+
+	parsed := &phc{id: fields[1], params: map[string]string{}}
+	rest := fields[2 : len(fields)-2]
+	if len(rest) > 0 && strings.HasPrefix(rest[0], "v=") {
+		version, err := strconv.Atoi(strings.TrimPrefix(rest[0], "v="))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s hash version", parsed.id)
+		}
+		parsed.version = version
+		rest = rest[1:]
+	}
+	if len(rest) > 1 {
+		return nil, fmt.Errorf("invalid %s hash", parsed.id)
+	}
+	if len(rest) == 1 {
+		for _, param := range strings.Split(rest[0], ",") {
+			name, value, ok := strings.Cut(param, "=")
+			if !ok {
+				return nil, fmt.Errorf("invalid %s hash parameter %q", parsed.id, param)
+			}
+			parsed.params[name] = value
+		}
+	}
+
+	var err error
+	if parsed.salt, err = base64.RawStdEncoding.DecodeString(fields[len(fields)-2]); err != nil {
+		return nil, fmt.Errorf("invalid %s hash salt", parsed.id)
+	}
+	if parsed.hash, err = base64.RawStdEncoding.DecodeString(fields[len(fields)-1]); err != nil {
+		return nil, fmt.Errorf("invalid %s hash", parsed.id)
+	}
+	return parsed, nil
+}
+
+// checkLengths rejects a salt shorter than minSaltLength or a digest shorter than minKeyLength.
+func (p *phc) checkLengths() error {
+	if len(p.salt) < minSaltLength {
+		return fmt.Errorf("invalid %s hash salt", p.id)
+	}
+	if len(p.hash) < minKeyLength {
+		return fmt.Errorf("invalid %s hash", p.id)
+	}
+	return nil
+}
+
+// intParam reads an integer parameter of the hash.
+func (p *phc) intParam(name string) (int, error) {
+	value, err := strconv.Atoi(p.params[name])
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s hash parameter %s", p.id, name)
+	}
+	return value, nil
+}
+
+func encodePHC(id string, version int, params string, salt []byte, hash []byte) string {
+	var b strings.Builder
+	b.WriteString("$" + id)
+	if version != 0 {
+		b.WriteString("$v=" + strconv.Itoa(version))
+	}
+	if params != "" {
+		b.WriteString("$" + params)
+	}
+	b.WriteString("$" + base64.RawStdEncoding.EncodeToString(salt))
+	b.WriteString("$" + base64.RawStdEncoding.EncodeToString(hash))
+	return b.String()
+}
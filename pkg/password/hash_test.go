@@ -0,0 +1,177 @@
+package password_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fyfirman/auth-management-go/pkg/password"
+	"github.com/stretchr/testify/assert"
+)
+
+// Cheap parameters keep the tests fast.
+var (
+	testBcrypt   = password.Bcrypt{Cost: 4}
+	testArgon2id = password.Argon2id{Memory: 64, Iterations: 1, Parallelism: 1}
+	testScrypt   = password.Scrypt{LogN: 4, BlockSize: 8, Parallelism: 1}
+)
+
+func TestHashers_RoundTrip(t *testing.T) {
+	cases := []struct {
+		name   string
+		hasher password.Hasher
+		prefix string
+	}{
+		{"bcrypt", testBcrypt, "$2a$04$"},
+		{"argon2id", testArgon2id, "$argon2id$v=19$m=64,t=1,p=1$"},
+		{"scrypt", testScrypt, "$scrypt$ln=4,r=8,p=1$"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			encoded, err := c.hasher.Hash("c0rrect-horse")
+			assert.NoError(t, err)
+			assert.True(t, strings.HasPrefix(encoded, c.prefix), encoded)
+			assert.True(t, c.hasher.Identifies(encoded))
+			assert.False(t, c.hasher.NeedsRehash(encoded))
+
+			match, err := c.hasher.Verify("c0rrect-horse", encoded)
+			assert.NoError(t, err)
+			assert.True(t, match)
+
+			match, err = c.hasher.Verify("wrong-horse", encoded)
+			assert.NoError(t, err)
+			assert.False(t, match)
+
+			// Salts are random, so the same password never hashes the same
+			again, err := c.hasher.Hash("c0rrect-horse")
+			assert.NoError(t, err)
+			assert.NotEqual(t, encoded, again)
+		})
+	}
+}
+
+func TestHashers_NeedsRehash(t *testing.T) {
+	encoded, err := testArgon2id.Hash("c0rrect-horse")
+	assert.NoError(t, err)
+
+	stronger := testArgon2id
+	stronger.Iterations = 2
+	assert.True(t, stronger.NeedsRehash(encoded))
+
+	// The parameters of the hash are used to verify it, not the hasher's
+	match, err := stronger.Verify("c0rrect-horse", encoded)
+	assert.NoError(t, err)
+	assert.True(t, match)
+
+	bcryptHash, err := testBcrypt.Hash("c0rrect-horse")
+	assert.NoError(t, err)
+	assert.True(t, password.Bcrypt{Cost: 5}.NeedsRehash(bcryptHash))
+}
+
+func TestHashers_Verify(t *testing.T) {
+	hashers := password.NewHashers(testArgon2id, testBcrypt, testScrypt)
+
+	current, err := hashers.Hash("c0rrect-horse")
+	assert.NoError(t, err)
+	legacy, err := testBcrypt.Hash("c0rrect-horse")
+	assert.NoError(t, err)
+	outdated, err := password.Argon2id{Memory: 32, Iterations: 1, Parallelism: 1}.Hash("c0rrect-horse")
+	assert.NoError(t, err)
+
+	cases := []struct {
+		name     string
+		encoded  string
+		password string
+		match    bool
+		rehash   bool
+	}{
+		{"preferred hasher", current, "c0rrect-horse", true, false},
+		{"other algorithm", legacy, "c0rrect-horse", true, true},
+		{"outdated parameters", outdated, "c0rrect-horse", true, true},
+		{"wrong password", legacy, "wrong-horse", false, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			match, rehash, err := hashers.Verify(c.password, c.encoded)
+			assert.NoError(t, err)
+			assert.Equal(t, c.match, match)
+			assert.Equal(t, c.rehash, rehash)
+		})
+	}
+
+	t.Run("unknown format", func(t *testing.T) {
+		match, _, err := hashers.Verify("c0rrect-horse", "$md5$abc")
+		assert.ErrorIs(t, err, password.ErrUnknownHashFormat)
+		assert.False(t, match)
+	})
+
+	t.Run("malformed hash", func(t *testing.T) {
+		match, _, err := hashers.Verify("c0rrect-horse", "$argon2id$v=19$m=64$c2FsdA$aGFzaA")
+		assert.Error(t, err)
+		assert.False(t, match)
+	})
+
+	// Without a digest any password would match
+	for _, encoded := range []string{
+		"$argon2id$v=19$m=32,t=1,p=1$c29tZXNhbHRzb21lc2FsdA$",
+		"$argon2id$v=19$m=32,t=1,p=1$c29tZXNhbHRzb21lc2FsdA$aGFzaA",
+		"$argon2id$v=19$m=32,t=1,p=1$$c29tZWhhc2hzb21laGFzaHNvbWVoYXNo",
+		"$scrypt$ln=4,r=8,p=1$c29tZXNhbHRzb21lc2FsdA$",
+	} {
+		t.Run("short digest or salt "+encoded, func(t *testing.T) {
+			match, _, err := hashers.Verify("anything", encoded)
+			assert.Error(t, err)
+			assert.False(t, match)
+		})
+	}
+}
+
+func TestHashers_Check(t *testing.T) {
+	hashers := password.NewHashers(testArgon2id, testBcrypt, testScrypt, password.PBKDF2SHA256{},
+		password.SaltedSHA{})
+	bcryptHash, err := testBcrypt.Hash("c0rrect-horse")
+	assert.NoError(t, err)
+	argon2Hash, err := testArgon2id.Hash("c0rrect-horse")
+	assert.NoError(t, err)
+	scryptHash, err := testScrypt.Hash("c0rrect-horse")
+	assert.NoError(t, err)
+	salt := "c29tZXNhbHRzb21lc2FsdA"
+	digest := "c29tZWhhc2hzb21laGFzaHNvbWVoYXNo"
+
+	cases := []struct {
+		name    string
+		encoded string
+		valid   bool
+	}{
+		{"bcrypt", bcryptHash, true},
+		{"argon2id", argon2Hash, true},
+		{"scrypt", scryptHash, true},
+		{"pbkdf2_sha256", "pbkdf2_sha256$1000$seasalt$CZukQOiDYgxA1Tk3myA9e6UnfHP2zk40Mh+WbaX0A8o=", true},
+		{"salted sha1", "$salted-sha1$salt=suffix$c2FsdA$" + "W6ph5Mm5Pz8GgiULbPgzG37mj9g", true},
+		{"bcrypt cost", strings.Replace(bcryptHash, "$04$", "$31$", 1), false},
+		{"truncated bcrypt", bcryptHash[:40], false},
+		{"argon2id memory", "$argon2id$v=19$m=4194304,t=1,p=1$" + salt + "$" + digest, false},
+		{"argon2id iterations", "$argon2id$v=19$m=64,t=1000,p=1$" + salt + "$" + digest, false},
+		{"argon2id threads", "$argon2id$v=19$m=64,t=1,p=255$" + salt + "$" + digest, false},
+		{"scrypt N", "$scrypt$ln=30,r=8,p=1$" + salt + "$" + digest, false},
+		{"scrypt block size", "$scrypt$ln=4,r=1024,p=1$" + salt + "$" + digest, false},
+		{"pbkdf2 iterations", "pbkdf2_sha256$100000000$seasalt$CZukQOiDYgxA1Tk3myA9e6UnfHP2zk40Mh+WbaX0A8o=", false},
+		{"pbkdf2 empty digest", "pbkdf2_sha256$1000$seasalt$", false},
+		{"pbkdf2 empty salt", "pbkdf2_sha256$1000$$CZukQOiDYgxA1Tk3myA9e6UnfHP2zk40Mh+WbaX0A8o=", false},
+		{"salted sha1 empty digest", "$salted-sha1$salt=suffix$c2FsdA$", false},
+		{"salted sha1 salt position", "$salted-sha1$salt=middle$c2FsdA$W6ph5Mm5Pz8GgiULbPgzG37mj9g", false},
+		{"unknown format", "5f4dcc3b5aa765d61d8327deb882cf99", false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := hashers.Check(c.encoded)
+			if c.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
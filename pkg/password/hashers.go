@@ -0,0 +1,247 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	saltLength = 16
+	keyLength  = 32
+
+	// minSaltLength and minKeyLength are the shortest salt and digest accepted in a stored hash. With an
+	// empty or tiny digest nearly any password would match.
+	minSaltLength = 8
+	minKeyLength  = 16
+)
+
+// Cost limits of Check. They are well above the defaults of this package and of the systems hashes are
+// imported from.
+const (
+	maxBcryptCost       = 16
+	maxArgon2Memory     = 1 << 20 // KiB
+	maxArgon2Iterations = 16
+	maxArgon2Threads    = 16
+	maxScryptLogN       = 20
+	maxScryptBlockSize  = 16
+	maxScryptThreads    = 16
+	maxPBKDF2Iterations = 5_000_000
+)
+
+// errCostTooHigh is returned by Check for hashes beyond the cost limits.
+var errCostTooHigh = errors.New("password hash cost exceeds the import limits")
+
+func newSalt() ([]byte, error) {
+	salt := make([]byte, saltLength)
+	_, err := rand.Read(salt)
+	return salt, err
+}
+
+// Bcrypt hashes in the modular crypt format of bcrypt ("$2a$10$..."), which predates PHC but is just as
+// self-describing. Passwords beyond MaxBytes are rejected rather than truncated.
+type Bcrypt struct {
+	Cost int
+}
+
+func (h Bcrypt) Identifies(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") ||
+		strings.HasPrefix(encoded, "$2y$")
+}
+
+func (h Bcrypt) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+	return string(hash), err
+}
+
+func (h Bcrypt) Verify(password string, encoded string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (h Bcrypt) Check(encoded string) error {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	if err != nil {
+		return err
+	}
+	// The 22 character salt and 31 character digest follow the "$2a$10$" prefix.
+	if len(encoded) != 60 {
+		return errors.New("invalid bcrypt hash")
+	}
+	if cost > maxBcryptCost {
+		return errCostTooHigh
+	}
+	return nil
+}
+
+func (h Bcrypt) NeedsRehash(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err != nil || cost != h.Cost
+}
+
+// Argon2id hashes with argon2id, encoded as $argon2id$v=19$m=<KiB>,t=<iterations>,p=<parallelism>$salt$hash.
+type Argon2id struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+}
+
+func (h Argon2id) Identifies(encoded string) bool {
+	return strings.HasPrefix(encoded, "$argon2id$")
+}
+
+func (h Argon2id) Hash(password string) (string, error) {
+	salt, err := newSalt()
+	if err != nil {
+		return "", err
+	}
+	hash := argon2.IDKey([]byte(password), salt, h.Iterations, h.Memory, h.Parallelism, keyLength)
+	params := fmt.Sprintf("m=%d,t=%d,p=%d", h.Memory, h.Iterations, h.Parallelism)
+	return encodePHC("argon2id", argon2.Version, params, salt, hash), nil
+}
+
+func (h Argon2id) Verify(password string, encoded string) (bool, error) {
+	stored, params, err := h.parse(encoded)
+	if err != nil {
+		return false, err
+	}
+	hash := argon2.IDKey([]byte(password), stored.salt, params.Iterations, params.Memory, params.Parallelism,
+		uint32(len(stored.hash)))
+	return subtle.ConstantTimeCompare(hash, stored.hash) == 1, nil
+}
+
+func (h Argon2id) Check(encoded string) error {
+	_, params, err := h.parse(encoded)
+	if err != nil {
+		return err
+	}
+	if params.Memory > maxArgon2Memory || params.Iterations > maxArgon2Iterations ||
+		params.Parallelism > maxArgon2Threads {
+		return errCostTooHigh
+	}
+	return nil
+}
+
+func (h Argon2id) NeedsRehash(encoded string) bool {
+	_, params, err := h.parse(encoded)
+	return err != nil || params != h
+}
+
+func (h Argon2id) parse(encoded string) (*phc, Argon2id, error) {
+	stored, err := parsePHC(encoded)
+	if err != nil {
+		return nil, Argon2id{}, err
+	}
+	if stored.version != argon2.Version {
+		return nil, Argon2id{}, fmt.Errorf("unsupported argon2id version %d", stored.version)
+	}
+
+	memory, err := stored.intParam("m")
+	if err != nil {
+		return nil, Argon2id{}, err
+	}
+	iterations, err := stored.intParam("t")
+	if err != nil {
+		return nil, Argon2id{}, err
+	}
+	parallelism, err := stored.intParam("p")
+	if err != nil {
+		return nil, Argon2id{}, err
+	}
+	if memory <= 0 || iterations <= 0 || parallelism <= 0 || parallelism > 255 {
+		return nil, Argon2id{}, errors.New("invalid argon2id hash parameters")
+	}
+	if err := stored.checkLengths(); err != nil {
+		return nil, Argon2id{}, err
+	}
+	return stored, Argon2id{Memory: uint32(memory), Iterations: uint32(iterations), Parallelism: uint8(parallelism)}, nil
+}
+
+// Scrypt hashes with scrypt, encoded as $scrypt$ln=<log2 N>,r=<block size>,p=<parallelism>$salt$hash.
+type Scrypt struct {
+	LogN        int
+	BlockSize   int
+	Parallelism int
+}
+
+func (h Scrypt) Identifies(encoded string) bool {
+	return strings.HasPrefix(encoded, "$scrypt$")
+}
+
+func (h Scrypt) Hash(password string) (string, error) {
+	salt, err := newSalt()
+	if err != nil {
+		return "", err
+	}
+	hash, err := scrypt.Key([]byte(password), salt, 1<<h.LogN, h.BlockSize, h.Parallelism, keyLength)
+	if err != nil {
+		return "", err
+	}
+	params := fmt.Sprintf("ln=%d,r=%d,p=%d", h.LogN, h.BlockSize, h.Parallelism)
+	return encodePHC("scrypt", 0, params, salt, hash), nil
+}
+
+func (h Scrypt) Verify(password string, encoded string) (bool, error) {
+	stored, params, err := h.parse(encoded)
+	if err != nil {
+		return false, err
+	}
+	hash, err := scrypt.Key([]byte(password), stored.salt, 1<<params.LogN, params.BlockSize, params.Parallelism,
+		len(stored.hash))
+	if err != nil {
+		return false, err
+	}
+	return subtle.ConstantTimeCompare(hash, stored.hash) == 1, nil
+}
+
+func (h Scrypt) Check(encoded string) error {
+	_, params, err := h.parse(encoded)
+	if err != nil {
+		return err
+	}
+	if params.LogN > maxScryptLogN || params.BlockSize > maxScryptBlockSize || params.Parallelism > maxScryptThreads {
+		return errCostTooHigh
+	}
+	return nil
+}
+
+func (h Scrypt) NeedsRehash(encoded string) bool {
+	_, params, err := h.parse(encoded)
+	return err != nil || params != h
+}
+
+func (h Scrypt) parse(encoded string) (*phc, Scrypt, error) {
+	stored, err := parsePHC(encoded)
+	if err != nil {
+		return nil, Scrypt{}, err
+	}
+
+	logN, err := stored.intParam("ln")
+	if err != nil {
+		return nil, Scrypt{}, err
+	}
+	blockSize, err := stored.intParam("r")
+	if err != nil {
+		return nil, Scrypt{}, err
+	}
+	parallelism, err := stored.intParam("p")
+	if err != nil {
+		return nil, Scrypt{}, err
+	}
+	if logN <= 0 || logN > 30 || blockSize <= 0 || parallelism <= 0 {
+		return nil, Scrypt{}, errors.New("invalid scrypt hash parameters")
+	}
+	if err := stored.checkLengths(); err != nil {
+		return nil, Scrypt{}, err
+	}
+	return stored, Scrypt{LogN: logN, BlockSize: blockSize, Parallelism: parallelism}, nil
+}
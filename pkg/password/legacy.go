@@ -0,0 +1,247 @@
+package password
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// The verifiers below only check hashes imported from other systems. They never hash new passwords:
+// Hashers replaces their hashes with one of the preferred hasher on the next successful login.
+
+// PBKDF2SHA256 verifies Django's default hashes, pbkdf2_sha256$<iterations>$<salt>$<base64 hash>.
+type PBKDF2SHA256 struct{}
+
+func (PBKDF2SHA256) Identifies(encoded string) bool {
+	return strings.HasPrefix(encoded, "pbkdf2_sha256$")
+}
+
+func (h PBKDF2SHA256) Verify(password string, encoded string) (bool, error) {
+	iterations, salt, stored, err := h.parse(encoded)
+	if err != nil {
+		return false, err
+	}
+	hash := pbkdf2.Key([]byte(password), salt, iterations, len(stored), sha256.New)
+	return subtle.ConstantTimeCompare(hash, stored) == 1, nil
+}
+
+func (h PBKDF2SHA256) Check(encoded string) error {
+	iterations, _, _, err := h.parse(encoded)
+	if err != nil {
+		return err
+	}
+	if iterations > maxPBKDF2Iterations {
+		return errCostTooHigh
+	}
+	return nil
+}
+
+func (PBKDF2SHA256) parse(encoded string) (int, []byte, []byte, error) {
+	fields := strings.Split(encoded, "$")
+	if len(fields) != 4 {
+		return 0, nil, nil, errors.New("invalid pbkdf2_sha256 hash")
+	}
+	iterations, err := strconv.Atoi(fields[1])
+	if err != nil || iterations <= 0 {
+		return 0, nil, nil, errors.New("invalid pbkdf2_sha256 hash iterations")
+	}
+	if fields[2] == "" {
+		return 0, nil, nil, errors.New("invalid pbkdf2_sha256 hash salt")
+	}
+	stored, err := base64.StdEncoding.DecodeString(fields[3])
+	if err != nil || len(stored) < minKeyLength {
+		return 0, nil, nil, errors.New("invalid pbkdf2_sha256 hash")
+	}
+	return iterations, []byte(fields[2]), stored, nil
+}
+
+// saltedSHADigests are the digests SaltedSHA supports, by PHC identifier.
+var saltedSHADigests = map[string]func() hash.Hash{
+	"salted-sha1":   sha1.New,
+	"salted-sha256": sha256.New,
+	"salted-sha512": sha512.New,
+}
+
+// SaltedSHA verifies a single round of SHA-1, SHA-256 or SHA-512 over the password and a salt, encoded
+// as $salted-sha256$salt=<prefix|suffix>$<salt>$<hash>. The salt parameter tells whether the salt comes
+// before or after the password.
+type SaltedSHA struct{}
+
+func (SaltedSHA) Identifies(encoded string) bool {
+	fields := strings.SplitN(encoded, "$", 3)
+	return len(fields) == 3 && fields[0] == "" && saltedSHADigests[fields[1]] != nil
+}
+
+func (s SaltedSHA) Verify(password string, encoded string) (bool, error) {
+	stored, digest, err := s.parse(encoded)
+	if err != nil {
+		return false, err
+	}
+
+	h := digest()
+	if stored.params["salt"] == "prefix" {
+		h.Write(stored.salt)
+		h.Write([]byte(password))
+	} else {
+		h.Write([]byte(password))
+		h.Write(stored.salt)
+	}
+	return subtle.ConstantTimeCompare(h.Sum(nil), stored.hash) == 1, nil
+}
+
+func (s SaltedSHA) Check(encoded string) error {
+	_, _, err := s.parse(encoded)
+	return err
+}
+
+func (SaltedSHA) parse(encoded string) (*phc, func() hash.Hash, error) {
+	stored, err := parsePHC(encoded)
+	if err != nil {
+		return nil, nil, err
+	}
+	digest, ok := saltedSHADigests[stored.id]
+	if !ok {
+		return nil, nil, ErrUnknownHashFormat
+	}
+	if position := stored.params["salt"]; position != "prefix" && position != "suffix" {
+		return nil, nil, fmt.Errorf("invalid %s hash parameter salt", stored.id)
+	}
+	if len(stored.hash) != digest().Size() {
+		return nil, nil, fmt.Errorf("invalid %s hash", stored.id)
+	}
+	return stored, digest, nil
+}
+
+// FirebaseScrypt verifies the modified scrypt of Firebase Authentication, encoded as
+// $firebase-scrypt$<salt>$<hash>. The parameters are shared by a whole Firebase project and shown in its
+// console rather than stored with each hash.
+type FirebaseScrypt struct {
+	SignerKey     []byte
+	SaltSeparator []byte
+	Rounds        int
+	MemCost       int
+}
+
+func (h FirebaseScrypt) Identifies(encoded string) bool {
+	return strings.HasPrefix(encoded, "$firebase-scrypt$")
+}
+
+// Check only validates the salt and digest: the cost is set in the configuration, not the hash.
+func (h FirebaseScrypt) Check(encoded string) error {
+	stored, err := parsePHC(encoded)
+	if err != nil {
+		return err
+	}
+	if len(stored.salt) == 0 || len(stored.hash) < minKeyLength {
+		return fmt.Errorf("invalid %s hash", stored.id)
+	}
+	return nil
+}
+
+func (h FirebaseScrypt) Verify(password string, encoded string) (bool, error) {
+	stored, err := parsePHC(encoded)
+	if err != nil {
+		return false, err
+	}
+
+	salt := append(append([]byte{}, stored.salt...), h.SaltSeparator...)
+	key, err := scrypt.Key([]byte(password), salt, 1<<h.MemCost, h.Rounds, 1, keyLength)
+	if err != nil {
+		return false, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return false, err
+	}
+
+	// The password is checked by encrypting the project's signer key with the derived key.
+	hash := make([]byte, len(h.SignerKey))
+	cipher.NewCTR(block, make([]byte, aes.BlockSize)).XORKeyStream(hash, h.SignerKey)
+	return subtle.ConstantTimeCompare(hash, stored.hash) == 1, nil
+}
+
+// Formats of foreign hashes accepted by ForeignHash.
+const (
+	FormatBcrypt         = "bcrypt"
+	FormatArgon2id       = "argon2id"
+	FormatScrypt         = "scrypt"
+	FormatPBKDF2SHA256   = "pbkdf2_sha256"
+	FormatFirebaseScrypt = "firebase_scrypt"
+	FormatSHA1           = "sha1"
+	FormatSHA256         = "sha256"
+	FormatSHA512         = "sha512"
+)
+
+// ForeignHash is a password hash exported from another system, to be stored in the encoding its verifier
+// understands.
+type ForeignHash struct {
+	// Format is one of the Format constants. Empty means the hash is already encoded, as bcrypt, PHC and
+	// Django hashes are.
+	Format string
+	Hash   string
+	// Salt is the base64 salt of Firebase exports, or the salt of SHA hashes as is.
+	Salt string
+	// SaltPosition tells whether the salt of SHA hashes comes before ("prefix", the default) or after
+	// ("suffix") the password.
+	SaltPosition string
+}
+
+// Encode returns the hash in the encoding of its verifier. SHA hashes may be hexadecimal or base64.
+func (f ForeignHash) Encode() (string, error) {
+	switch f.Format {
+	case "", FormatBcrypt, FormatArgon2id, FormatScrypt, FormatPBKDF2SHA256:
+		return f.Hash, nil
+	case FormatFirebaseScrypt:
+		salt, err := base64.StdEncoding.DecodeString(f.Salt)
+		if err != nil {
+			return "", errors.New("invalid firebase_scrypt salt")
+		}
+		hash, err := base64.StdEncoding.DecodeString(f.Hash)
+		if err != nil {
+			return "", errors.New("invalid firebase_scrypt hash")
+		}
+		return encodePHC("firebase-scrypt", 0, "", salt, hash), nil
+	case FormatSHA1, FormatSHA256, FormatSHA512:
+		id := "salted-" + f.Format
+		hash, err := decodeDigest(f.Hash, saltedSHADigests[id]().Size())
+		if err != nil {
+			return "", fmt.Errorf("invalid %s hash", f.Format)
+		}
+		position := f.SaltPosition
+		if position == "" {
+			position = "prefix"
+		}
+		if position != "prefix" && position != "suffix" {
+			return "", fmt.Errorf("invalid salt position %q", position)
+		}
+		return encodePHC(id, 0, "salt="+position, []byte(f.Salt), hash), nil
+	default:
+		return "", fmt.Errorf("unsupported hash format %q", f.Format)
+	}
+}
+
+// decodeDigest decodes a digest of size bytes written in hexadecimal or base64.
+func decodeDigest(encoded string, size int) ([]byte, error) {
+	if len(encoded) == hex.EncodedLen(size) {
+		return hex.DecodeString(encoded)
+	}
+	for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding} {
+		if digest, err := encoding.DecodeString(encoded); err == nil && len(digest) == size {
+			return digest, nil
+		}
+	}
+	return nil, errors.New("invalid digest")
+}
@@ -0,0 +1,102 @@
+package password_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/fyfirman/auth-management-go/pkg/password"
+	"github.com/stretchr/testify/assert"
+)
+
+// firebaseScrypt uses the sample project parameters of Firebase's scrypt documentation.
+func firebaseScrypt() password.FirebaseScrypt {
+	signerKey, _ := base64.StdEncoding.DecodeString(
+		"jxspr8Ki0RYycVU8zykbdLGjFQ3McFUH0uiiTvC8pVMXAn210wjLNmdZJzxUECKbm0QsEmYUSDzZvpjeJ9WmXA==")
+	saltSeparator, _ := base64.StdEncoding.DecodeString("Bw==")
+	return password.FirebaseScrypt{SignerKey: signerKey, SaltSeparator: saltSeparator, Rounds: 8, MemCost: 14}
+}
+
+func TestForeignHash_Verify(t *testing.T) {
+	hashers := password.NewHashers(testArgon2id, password.PBKDF2SHA256{}, password.SaltedSHA{}, firebaseScrypt())
+
+	cases := []struct {
+		name     string
+		foreign  password.ForeignHash
+		password string
+	}{
+		{
+			name:     "django pbkdf2_sha256",
+			foreign:  password.ForeignHash{Hash: "pbkdf2_sha256$1000$seasalt$CZukQOiDYgxA1Tk3myA9e6UnfHP2zk40Mh+WbaX0A8o="},
+			password: "c0rrect-horse",
+		},
+		{
+			name: "firebase scrypt",
+			foreign: password.ForeignHash{
+				Format: password.FormatFirebaseScrypt,
+				Hash:   "lSrfV15cpx95/sZS2W9c9Kp6i/LVgQNDNC/qzrCnh1SAyZvqmZqAjTdn3aoItz+VHjoZilo78198JAdRuid5lQ==",
+				Salt:   "42xEC+ixf3L2lw==",
+			},
+			password: "user1password",
+		},
+		{
+			name: "sha256 with the salt first",
+			foreign: password.ForeignHash{
+				Format: password.FormatSHA256,
+				Hash:   "fff02de4c5421ca08a16af8923dbcc7a512e63854c329f45a19aad6a5701a304",
+				Salt:   "pepper",
+			},
+			password: "c0rrect-horse",
+		},
+		{
+			name: "sha1 with the salt last",
+			foreign: password.ForeignHash{
+				Format:       password.FormatSHA1,
+				Hash:         "c10fc06fb87646747dafdb19c439686b332d16d5",
+				Salt:         "pepper",
+				SaltPosition: "suffix",
+			},
+			password: "c0rrect-horse",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			encoded, err := c.foreign.Encode()
+			assert.NoError(t, err)
+			assert.True(t, hashers.Identifies(encoded))
+
+			// Foreign hashes always get replaced by one of the preferred hasher
+			match, rehash, err := hashers.Verify(c.password, encoded)
+			assert.NoError(t, err)
+			assert.True(t, match)
+			assert.True(t, rehash)
+
+			match, _, err = hashers.Verify("wrong-horse", encoded)
+			assert.NoError(t, err)
+			assert.False(t, match)
+		})
+	}
+}
+
+func TestForeignHash_Encode_Invalid(t *testing.T) {
+	cases := []struct {
+		name    string
+		foreign password.ForeignHash
+	}{
+		{"unsupported format", password.ForeignHash{Format: "md5", Hash: "5f4dcc3b5aa765d61d8327deb882cf99"}},
+		{"sha256 of the wrong size", password.ForeignHash{Format: password.FormatSHA256, Hash: "abcd"}},
+		{"unknown salt position", password.ForeignHash{
+			Format:       password.FormatSHA1,
+			Hash:         "c10fc06fb87646747dafdb19c439686b332d16d5",
+			SaltPosition: "middle",
+		}},
+		{"firebase hash not in base64", password.ForeignHash{Format: password.FormatFirebaseScrypt, Hash: "%%"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := c.foreign.Encode()
+			assert.Error(t, err)
+		})
+	}
+}
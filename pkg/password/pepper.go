@@ -0,0 +1,77 @@
+package password
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// pepperedPrefix starts the hashes of peppered passwords: $peppered$k=<version> followed by the hash of the
+// peppered password.
+const pepperedPrefix = "$peppered$k="
+
+// minPepperLength is the shortest pepper key accepted, in bytes.
+const minPepperLength = 16
+
+// ErrUnknownPepper is returned for hashes peppered with a key that is no longer configured.
+var ErrUnknownPepper = errors.New("unknown password pepper version")
+
+// Pepper is a secret key mixed into passwords before hashing, so a leaked database alone does not allow
+// guessing them. Version names the key in the hashes it peppered.
+type Pepper struct {
+	Version string
+	Key     []byte
+}
+
+// apply returns the HMAC-SHA256 of password under the pepper key, in base64 so bcrypt hashes it in full.
+func (p Pepper) apply(password string) string {
+	mac := hmac.New(sha256.New, p.Key)
+	mac.Write([]byte(password))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// ParsePeppers reads "<version>:<base64 key>" entries separated by commas or newlines, the first being the
+// current pepper. Blank lines and lines starting with # are skipped.
+func ParsePeppers(text string) ([]Pepper, error) {
+	var peppers []Pepper
+	versions := map[string]bool{}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, entry := range strings.Split(line, ",") {
+			version, encodedKey, ok := strings.Cut(strings.TrimSpace(entry), ":")
+			if !ok || version == "" || strings.Contains(version, "$") {
+				return nil, fmt.Errorf("expected <version>:<base64 key>, got %q", entry)
+			}
+			if versions[version] {
+				return nil, fmt.Errorf("pepper version %q is listed twice", version)
+			}
+			key, err := base64.StdEncoding.DecodeString(encodedKey)
+			if err != nil {
+				return nil, fmt.Errorf("pepper %s: invalid base64 key", version)
+			}
+			if len(key) < minPepperLength {
+				return nil, fmt.Errorf("pepper %s: keys must be at least %d bytes", version, minPepperLength)
+			}
+			versions[version] = true
+			peppers = append(peppers, Pepper{Version: version, Key: key})
+		}
+	}
+	return peppers, nil
+}
+
+// splitPeppered returns the pepper version and the hash of the peppered password of encoded, ok false for
+// hashes that were not peppered.
+func splitPeppered(encoded string) (version string, inner string, ok bool) {
+	rest, ok := strings.CutPrefix(encoded, pepperedPrefix)
+	if !ok {
+		return "", "", false
+	}
+	version, inner, ok = strings.Cut(rest, "$")
+	return version, "$" + inner, ok
+}
@@ -0,0 +1,90 @@
+package password_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fyfirman/auth-management-go/pkg/password"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePeppers(t *testing.T) {
+	peppers, err := password.ParsePeppers(`
+# current
+2:bmV3LXBlcHBlci1rZXktMzItYnl0ZXMtbG9uZyEhIQ==
+1:b2xkLXBlcHBlci1rZXktMTZi,0:YW5jaWVudC1wZXBwZXItMTZi
+`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2", "1", "0"}, []string{peppers[0].Version, peppers[1].Version, peppers[2].Version})
+	assert.Equal(t, "old-pepper-key-16b", string(peppers[1].Key))
+
+	cases := []struct {
+		name string
+		text string
+	}{
+		{"missing version", "b2xkLXBlcHBlci1rZXktMTZi"},
+		{"invalid base64", "1:not base64"},
+		{"short key", "1:c2hvcnQ="},
+		{"duplicate version", "1:b2xkLXBlcHBlci1rZXktMTZi,1:YW5jaWVudC1wZXBwZXItMTZi"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := password.ParsePeppers(c.text)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestHashers_Peppers(t *testing.T) {
+	oldPepper := password.Pepper{Version: "1", Key: []byte("old-pepper-key-16b")}
+	newPepper := password.Pepper{Version: "2", Key: []byte("new-pepper-key-32-bytes-long!!!")}
+	plain := password.NewHashers(testArgon2id)
+	before := plain.WithPeppers(oldPepper)
+	after := plain.WithPeppers(newPepper, oldPepper)
+
+	unpeppered, err := plain.Hash("c0rrect-horse")
+	assert.NoError(t, err)
+	peppered, err := before.Hash("c0rrect-horse")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(peppered, "$peppered$k=1$argon2id$"), peppered)
+	assert.True(t, after.Identifies(peppered))
+
+	cases := []struct {
+		name    string
+		hashers *password.Hashers
+		encoded string
+		rehash  bool
+	}{
+		{"current pepper", before, peppered, false},
+		{"rotated pepper", after, peppered, true},
+		{"pepper introduced", before, unpeppered, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			match, rehash, err := c.hashers.Verify("c0rrect-horse", c.encoded)
+			assert.NoError(t, err)
+			assert.True(t, match)
+			assert.Equal(t, c.rehash, rehash)
+
+			match, _, err = c.hashers.Verify("wrong-horse", c.encoded)
+			assert.NoError(t, err)
+			assert.False(t, match)
+		})
+	}
+
+	t.Run("forgotten pepper", func(t *testing.T) {
+		match, _, err := plain.Verify("c0rrect-horse", peppered)
+		assert.ErrorIs(t, err, password.ErrUnknownPepper)
+		assert.False(t, match)
+	})
+
+	t.Run("the pepper is part of the hash", func(t *testing.T) {
+		// The same hash verified with another key under the same version does not match
+		stolen := plain.WithPeppers(password.Pepper{Version: "1", Key: []byte("guessed-pepper-key")})
+		match, _, err := stolen.Verify("c0rrect-horse", peppered)
+		assert.NoError(t, err)
+		assert.False(t, match)
+	})
+}
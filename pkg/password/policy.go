@@ -0,0 +1,195 @@
+// Package password checks passwords against a configurable strength policy and known data breaches.
+package password
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// MaxBytes is the longest password bcrypt hashes in full. Longer passwords would be silently truncated.
+const MaxBytes = 72
+
+// Policy describes what makes a password acceptable. Zero values disable a rule, except MaxBytes which
+// never exceeds the MaxBytes constant.
+type Policy struct {
+	MinLength int
+	MaxBytes  int
+	// MinCharacterClasses is how many of lowercase letters, uppercase letters, digits and symbols a
+	// password must mix.
+	MinCharacterClasses int
+	// MinEntropyBits is the estimated strength required, see Entropy.
+	MinEntropyBits float64
+	// RejectSimilar refuses passwords containing the username or email of the account, or contained in them.
+	RejectSimilar bool
+	// Breaches, when set, refuses passwords found in data breaches.
+	Breaches BreachChecker
+}
+
+// DefaultPolicy follows NIST SP 800-63B: length matters more than composition rules.
+func DefaultPolicy() Policy {
+	return Policy{
+		MinLength:      8,
+		MaxBytes:       MaxBytes,
+		MinEntropyBits: 35,
+		RejectSimilar:  true,
+	}
+}
+
+// Violation is a rule a password breaks. Message completes a sentence starting with the field name.
+type Violation struct {
+	Rule    string
+	Message string
+}
+
+// Check returns the rules password breaks. identifiers are the username, email and other values of the
+// account the password must not resemble. The error reports a failed breach lookup; the violations of the
+// other rules are still returned with it.
+func (p Policy) Check(ctx context.Context, password string, identifiers ...string) ([]Violation, error) {
+	var violations []Violation
+
+	if length := len([]rune(password)); length < p.MinLength {
+		violations = append(violations, Violation{
+			Rule:    "min_length",
+			Message: fmt.Sprintf("must be at least %d characters long", p.MinLength),
+		})
+	}
+	maxBytes := p.MaxBytes
+	if maxBytes <= 0 || maxBytes > MaxBytes {
+		maxBytes = MaxBytes
+	}
+	if len(password) > maxBytes {
+		violations = append(violations, Violation{
+			Rule:    "max_bytes",
+			Message: fmt.Sprintf("cannot be longer than %d bytes", maxBytes),
+		})
+	}
+	if p.MinCharacterClasses > 0 && characterClasses(password) < p.MinCharacterClasses {
+		violations = append(violations, Violation{
+			Rule: "character_classes",
+			Message: fmt.Sprintf("must mix at least %d of lowercase letters, uppercase letters, digits and symbols",
+				p.MinCharacterClasses),
+		})
+	}
+	if p.RejectSimilar && similar(password, identifiers) {
+		violations = append(violations, Violation{
+			Rule:    "similarity",
+			Message: "must not contain the username or email",
+		})
+	}
+	if p.MinEntropyBits > 0 && Entropy(password) < p.MinEntropyBits {
+		violations = append(violations, Violation{
+			Rule:    "entropy",
+			Message: "is too easy to guess",
+		})
+	}
+	if p.Breaches != nil {
+		count, err := p.Breaches.Breached(ctx, password)
+		if err != nil {
+			return violations, err
+		}
+		if count > 0 {
+			violations = append(violations, Violation{
+				Rule:    "breached",
+				Message: "appears in a known data breach",
+			})
+		}
+	}
+
+	return violations, nil
+}
+
+const (
+	classLower = 1 << iota
+	classUpper
+	classDigit
+	classSymbol
+)
+
+// classPoolSizes is how many characters each class contributes to the alphabet of a password.
+var classPoolSizes = map[int]float64{classLower: 26, classUpper: 26, classDigit: 10, classSymbol: 33}
+
+func characterClass(r rune) int {
+	switch {
+	case unicode.IsLower(r):
+		return classLower
+	case unicode.IsUpper(r):
+		return classUpper
+	case unicode.IsDigit(r):
+		return classDigit
+	default:
+		return classSymbol
+	}
+}
+
+func characterClasses(password string) int {
+	classes := 0
+	for _, r := range password {
+		classes |= characterClass(r)
+	}
+
+	count := 0
+	for class := range classPoolSizes {
+		if classes&class != 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// Entropy estimates the strength of password in bits from the size of the alphabet its character classes
+// span. A character repeating the previous one or continuing a sequence such as "abc" or "321" only counts
+// for one bit.
+func Entropy(password string) float64 {
+	runes := []rune(password)
+	classes := 0
+	for _, r := range runes {
+		classes |= characterClass(r)
+	}
+
+	pool := 0.0
+	for class, size := range classPoolSizes {
+		if classes&class != 0 {
+			pool += size
+		}
+	}
+	bitsPerCharacter := math.Log2(math.Max(pool, 2))
+
+	bits := 0.0
+	for i, r := range runes {
+		if i > 0 && math.Abs(float64(unicode.ToLower(r)-unicode.ToLower(runes[i-1]))) <= 1 {
+			bits++
+			continue
+		}
+		bits += bitsPerCharacter
+	}
+	return bits
+}
+
+// similar reports whether the password contains one of the identifiers, or the local part of an email,
+// or is contained in one of them. Identifiers shorter than 3 characters are ignored.
+func similar(password string, identifiers []string) bool {
+	password = strings.ToLower(password)
+	if password == "" {
+		return false
+	}
+
+	for _, identifier := range identifiers {
+		identifier = strings.ToLower(identifier)
+		candidates := []string{identifier}
+		if local, _, ok := strings.Cut(identifier, "@"); ok {
+			candidates = append(candidates, local)
+		}
+		for _, candidate := range candidates {
+			if len(candidate) < 3 {
+				continue
+			}
+			if strings.Contains(password, candidate) || strings.Contains(candidate, password) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package password_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/fyfirman/auth-management-go/pkg/password"
+	"github.com/stretchr/testify/assert"
+)
+
+func rules(violations []password.Violation) []string {
+	names := []string{}
+	for _, violation := range violations {
+		names = append(names, violation.Rule)
+	}
+	return names
+}
+
+func TestPolicy_Check(t *testing.T) {
+	policy := password.DefaultPolicy()
+	policy.MinCharacterClasses = 2
+
+	cases := []struct {
+		name     string
+		password string
+		expected []string
+	}{
+		{"strong", "c0rrect-horse-battery", []string{}},
+		{"too short", "x7#Lq", []string{"min_length", "entropy"}},
+		{"beyond the bcrypt limit", strings.Repeat("c0rrect-horse ", 6), []string{"max_bytes"}},
+		{"single character class", "correcthorsebattery", []string{"character_classes"}},
+		{"contains the username", "jdoe-4-ever!", []string{"similarity"}},
+		{"contains the email local part", "Janedoe#1987", []string{"similarity"}},
+		{"sequences", "abcd1234", []string{"entropy"}},
+		{"repetitions", "aaaaaaaaaaa1", []string{"entropy"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			violations, err := policy.Check(context.TODO(), c.password, "jdoe", "janedoe@example.com")
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, rules(violations))
+		})
+	}
+}
+
+func TestPolicy_Check_MaxBytesCountsBytes(t *testing.T) {
+	// 25 characters of 3 bytes each exceed 72 bytes
+	violations, _ := password.DefaultPolicy().Check(context.TODO(), strings.Repeat("日本語", 9)[:75])
+	assert.Contains(t, rules(violations), "max_bytes")
+}
+
+func TestEntropy(t *testing.T) {
+	assert.Less(t, password.Entropy("12345678"), password.Entropy("93714682"))
+	assert.Less(t, password.Entropy("passwordpassword"), password.Entropy("Tr0ub4dor&3xyzq!"))
+}
@@ -0,0 +1,467 @@
+package policy
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expression is a compiled condition. The language supports:
+//
+//	literals     'text' "text" 42 1.5 true false null [a, b]
+//	attributes   subject.org_id resource.owner.id action
+//	comparison   == != < <= > >= in
+//	logic        && || ! ( )
+type Expression interface {
+	Eval(input map[string]interface{}) (interface{}, error)
+}
+
+// Compile parses a condition. An empty condition always evaluates to true.
+func Compile(source string) (Expression, error) {
+	if strings.TrimSpace(source) == "" {
+		return literal{value: true}, nil
+	}
+
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", p.peek().text, p.peek().pos)
+	}
+	return expr, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+var operators = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "(", ")", "[", "]", ",", "."}
+
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	runes := []rune(source)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '\'' || r == '"':
+			end := i + 1
+			var text strings.Builder
+			for end < len(runes) && runes[end] != r {
+				if runes[end] == '\\' && end+1 < len(runes) {
+					end++
+				}
+				text.WriteRune(runes[end])
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %d", i)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text.String(), pos: i})
+			i = end + 1
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			end := i + 1
+			for end < len(runes) && (unicode.IsDigit(runes[end]) || runes[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[i:end]), pos: i})
+			i = end
+		case unicode.IsLetter(r) || r == '_':
+			end := i + 1
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || runes[end] == '_') {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: string(runes[i:end]), pos: i})
+			i = end
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(string(runes[i:]), op) {
+					tokens = append(tokens, token{kind: tokenOperator, text: op, pos: i})
+					i += len([]rune(op))
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected character %q at position %d", r, i)
+			}
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) accept(kind tokenKind, text string) bool {
+	if t := p.peek(); t.kind == kind && t.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(text string) error {
+	if !p.accept(tokenOperator, text) {
+		return fmt.Errorf("expected %q at position %d", text, p.peek().pos)
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (Expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept(tokenOperator, "||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logical{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expression, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept(tokenOperator, "&&") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = logical{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (Expression, error) {
+	if p.accept(tokenOperator, "!") {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return not{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (Expression, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+	switch {
+	case t.kind == tokenOperator && (t.text == "==" || t.text == "!=" || t.text == "<" ||
+		t.text == "<=" || t.text == ">" || t.text == ">="):
+	case t.kind == tokenIdent && t.text == "in":
+	default:
+		return left, nil
+	}
+	p.next()
+
+	right, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	return comparison{op: t.text, left: left, right: right}, nil
+}
+
+func (p *parser) parsePrimary() (Expression, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenString:
+		return literal{value: t.text}, nil
+	case tokenNumber:
+		number, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", t.text, t.pos)
+		}
+		return literal{value: number}, nil
+	case tokenIdent:
+		switch t.text {
+		case "true":
+			return literal{value: true}, nil
+		case "false":
+			return literal{value: false}, nil
+		case "null":
+			return literal{value: nil}, nil
+		}
+		path := []string{t.text}
+		for p.accept(tokenOperator, ".") {
+			segment := p.next()
+			if segment.kind != tokenIdent {
+				return nil, fmt.Errorf("expected attribute name at position %d", segment.pos)
+			}
+			path = append(path, segment.text)
+		}
+		return attribute{path: path}, nil
+	case tokenOperator:
+		switch t.text {
+		case "(":
+			expr, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			return expr, p.expect(")")
+		case "[":
+			var items []Expression
+			for !p.accept(tokenOperator, "]") {
+				if len(items) > 0 {
+					if err := p.expect(","); err != nil {
+						return nil, err
+					}
+				}
+				item, err := p.parsePrimary()
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, item)
+			}
+			return list{items: items}, nil
+		}
+	}
+	if t.kind == tokenEOF {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	return nil, fmt.Errorf("unexpected %q at position %d", t.text, t.pos)
+}
+
+type literal struct {
+	value interface{}
+}
+
+func (l literal) Eval(map[string]interface{}) (interface{}, error) {
+	return l.value, nil
+}
+
+type list struct {
+	items []Expression
+}
+
+func (l list) Eval(input map[string]interface{}) (interface{}, error) {
+	values := make([]interface{}, 0, len(l.items))
+	for _, item := range l.items {
+		value, err := item.Eval(input)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// missing is the value of attributes absent from the input.
+type missing struct{}
+
+// attribute resolves a dotted path in the input. Missing attributes evaluate to missing{}.
+type attribute struct {
+	path []string
+}
+
+func (a attribute) Eval(input map[string]interface{}) (interface{}, error) {
+	var current interface{} = input
+	for _, segment := range a.path {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return missing{}, nil
+		}
+		current, ok = object[segment]
+		if !ok {
+			return missing{}, nil
+		}
+	}
+	return current, nil
+}
+
+type not struct {
+	operand Expression
+}
+
+func (n not) Eval(input map[string]interface{}) (interface{}, error) {
+	value, err := evalBool(n.operand, input)
+	if err != nil {
+		return nil, err
+	}
+	return !value, nil
+}
+
+type logical struct {
+	op          string
+	left, right Expression
+}
+
+func (l logical) Eval(input map[string]interface{}) (interface{}, error) {
+	left, err := evalBool(l.left, input)
+	if err != nil {
+		return nil, err
+	}
+	if l.op == "&&" && !left {
+		return false, nil
+	}
+	if l.op == "||" && left {
+		return true, nil
+	}
+	return evalBool(l.right, input)
+}
+
+type comparison struct {
+	op          string
+	left, right Expression
+}
+
+func (c comparison) Eval(input map[string]interface{}) (interface{}, error) {
+	left, err := c.left.Eval(input)
+	if err != nil {
+		return nil, err
+	}
+	right, err := c.right.Eval(input)
+	if err != nil {
+		return nil, err
+	}
+
+	// Comparisons involving a missing attribute are false, so two absent attributes are not equal. Only
+	// comparing one to null holds, to test that an attribute is absent.
+	_, leftMissing := left.(missing)
+	_, rightMissing := right.(missing)
+	if leftMissing || rightMissing {
+		return c.op == "==" && (left == nil || right == nil), nil
+	}
+
+	switch c.op {
+	case "==":
+		return equal(left, right), nil
+	case "!=":
+		return !equal(left, right), nil
+	case "in":
+		items, ok := toList(right)
+		if !ok {
+			return nil, fmt.Errorf("right side of 'in' must be a list")
+		}
+		for _, item := range items {
+			if equal(left, item) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	if leftNumber, ok := toNumber(left); ok {
+		if rightNumber, ok := toNumber(right); ok {
+			return compareOrdered(c.op, leftNumber, rightNumber), nil
+		}
+	}
+	leftString, leftOk := left.(string)
+	rightString, rightOk := right.(string)
+	if leftOk && rightOk {
+		return compareOrdered(c.op, leftString, rightString), nil
+	}
+	return false, nil
+}
+
+func compareOrdered[T float64 | string](op string, left, right T) bool {
+	switch op {
+	case "<":
+		return left < right
+	case "<=":
+		return left <= right
+	case ">":
+		return left > right
+	default:
+		return left >= right
+	}
+}
+
+func evalBool(expr Expression, input map[string]interface{}) (bool, error) {
+	value, err := expr.Eval(input)
+	if err != nil {
+		return false, err
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("expected a boolean, got %T", value)
+	}
+	return b, nil
+}
+
+func equal(left, right interface{}) bool {
+	if _, ok := left.(missing); ok {
+		return false
+	}
+	if _, ok := right.(missing); ok {
+		return false
+	}
+	if leftNumber, ok := toNumber(left); ok {
+		rightNumber, ok := toNumber(right)
+		return ok && leftNumber == rightNumber
+	}
+	return reflect.DeepEqual(left, right)
+}
+
+func toNumber(value interface{}) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+func toList(value interface{}) ([]interface{}, bool) {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice {
+		return nil, false
+	}
+	items := make([]interface{}, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		items = append(items, v.Index(i).Interface())
+	}
+	return items, true
+}
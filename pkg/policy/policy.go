@@ -0,0 +1,142 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+type Effect string
+
+const (
+	Allow Effect = "allow"
+	Deny  Effect = "deny"
+)
+
+// Policy grants or denies actions on resource types when its condition holds.
+// Actions and resources accept "*" and prefix wildcards such as "users:*".
+type Policy struct {
+	ID          string   `json:"id"`
+	Description string   `json:"description"`
+	Effect      Effect   `json:"effect"`
+	Actions     []string `json:"actions"`
+	Resources   []string `json:"resources"`
+	Condition   string   `json:"condition"`
+}
+
+// Input is the request being authorized. Subject, Resource and Context are exposed to conditions
+// as the subject, resource and context attributes, and Action as action.
+type Input struct {
+	Subject  map[string]interface{}
+	Action   string
+	Resource map[string]interface{}
+	Context  map[string]interface{}
+}
+
+type Decision struct {
+	Allowed  bool
+	PolicyID string
+	Reason   string
+}
+
+type compiledPolicy struct {
+	Policy
+	condition Expression
+}
+
+type Engine struct {
+	policies []compiledPolicy
+}
+
+func NewEngine(policies []Policy) (*Engine, error) {
+	engine := &Engine{}
+	for _, p := range policies {
+		if p.ID == "" {
+			return nil, fmt.Errorf("policy without id")
+		}
+		if p.Effect != Allow && p.Effect != Deny {
+			return nil, fmt.Errorf("policy %s: unknown effect %q", p.ID, p.Effect)
+		}
+		condition, err := Compile(p.Condition)
+		if err != nil {
+			return nil, fmt.Errorf("policy %s: %w", p.ID, err)
+		}
+		engine.policies = append(engine.policies, compiledPolicy{Policy: p, condition: condition})
+	}
+	return engine, nil
+}
+
+// LoadFile reads a JSON array of policies.
+func LoadFile(path string) ([]Policy, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var policies []Policy
+	if err := json.Unmarshal(content, &policies); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return policies, nil
+}
+
+// Evaluate denies by default. A matching deny policy wins over any matching allow policy. A condition that
+// fails to evaluate keeps an allow policy from matching and makes a deny policy deny, so broken rules
+// fail closed.
+func (e *Engine) Evaluate(input Input) Decision {
+	resourceType, _ := input.Resource["type"].(string)
+	attributes := map[string]interface{}{
+		"subject":  orEmpty(input.Subject),
+		"action":   input.Action,
+		"resource": orEmpty(input.Resource),
+		"context":  orEmpty(input.Context),
+	}
+
+	var allowedBy *compiledPolicy
+	for i := range e.policies {
+		p := &e.policies[i]
+		if !matches(p.Actions, input.Action) || !matches(p.Resources, resourceType) {
+			continue
+		}
+		result, err := evalBool(p.condition, attributes)
+		if err != nil && p.Effect == Deny {
+			return Decision{Allowed: false, PolicyID: p.ID, Reason: "denied by policy " + p.ID + ": " + err.Error()}
+		}
+		if err != nil || !result {
+			continue
+		}
+		if p.Effect == Deny {
+			return Decision{Allowed: false, PolicyID: p.ID, Reason: "denied by policy " + p.ID}
+		}
+		if allowedBy == nil {
+			allowedBy = p
+		}
+	}
+
+	if allowedBy == nil {
+		return Decision{Allowed: false, Reason: "no policy allows this action"}
+	}
+	return Decision{Allowed: true, PolicyID: allowedBy.ID, Reason: "allowed by policy " + allowedBy.ID}
+}
+
+func matches(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if pattern == "*" || pattern == value {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func orEmpty(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return map[string]interface{}{}
+	}
+	return m
+}
@@ -0,0 +1,185 @@
+package policy_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fyfirman/auth-management-go/pkg/policy"
+)
+
+func TestCompile(t *testing.T) {
+	input := map[string]interface{}{
+		"subject": map[string]interface{}{
+			"id":     float64(7),
+			"role":   "admin",
+			"org_id": uint(3),
+			"groups": []string{"support", "ops"},
+		},
+		"resource": map[string]interface{}{
+			"type":     "user",
+			"org_id":   float64(3),
+			"owner_id": float64(9),
+		},
+		"action": "users:write",
+	}
+
+	cases := []struct {
+		condition string
+		expected  bool
+	}{
+		{"", true},
+		{"subject.role == 'admin'", true},
+		{`subject.role != "admin"`, false},
+		{"subject.org_id == resource.org_id", true},
+		{"subject.id == resource.owner_id || subject.role == 'superadmin'", false},
+		{"subject.id < resource.owner_id && !(action == 'users:delete')", true},
+		{"subject.role in ['admin', 'superadmin']", true},
+		{"'ops' in subject.groups", true},
+		{"resource.missing == null", true},
+		{"resource.missing != null", false},
+		{"resource.missing.deeper == 1", false},
+		{"subject.missing == resource.missing", false},
+		{"subject.missing != 'admin'", false},
+		{"subject.missing in [resource.missing]", false},
+		{"subject.id >= 7 && subject.id <= 7.0", true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.condition, func(t *testing.T) {
+			expr, err := policy.Compile(c.condition)
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+			result, err := expr.Eval(input)
+			if err != nil {
+				t.Fatalf("Eval() error = %v", err)
+			}
+			if result != c.expected {
+				t.Errorf("Eval() = %v, expected %v", result, c.expected)
+			}
+		})
+	}
+}
+
+func TestCompile_SyntaxErrors(t *testing.T) {
+	for _, condition := range []string{
+		"subject.role ==",
+		"(subject.role == 'admin'",
+		"subject.role == 'admin",
+		"subject.role = 'admin'",
+		"subject. == 1",
+		"subject.role == 'admin' extra",
+	} {
+		if _, err := policy.Compile(condition); err == nil {
+			t.Errorf("expected an error for %q", condition)
+		}
+	}
+}
+
+func TestEngine_Evaluate(t *testing.T) {
+	engine, err := policy.NewEngine([]policy.Policy{
+		{
+			ID:        "admins-manage-own-org",
+			Effect:    policy.Allow,
+			Actions:   []string{"users:*"},
+			Resources: []string{"user"},
+			Condition: "subject.role == 'admin' && subject.org_id == resource.org_id",
+		},
+		{
+			ID:        "superadmins-manage-everything",
+			Effect:    policy.Allow,
+			Actions:   []string{"*"},
+			Condition: "subject.role == 'superadmin'",
+		},
+		{
+			ID:        "broken-purge-rule",
+			Effect:    policy.Deny,
+			Actions:   []string{"users:purge"},
+			Resources: []string{"user"},
+			Condition: "resource.org_id",
+		},
+		{
+			ID:        "nobody-deletes-superadmins",
+			Effect:    policy.Deny,
+			Actions:   []string{"users:delete"},
+			Resources: []string{"user"},
+			Condition: "resource.role == 'superadmin'",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	admin := map[string]interface{}{"role": "admin", "org_id": float64(1)}
+	superadmin := map[string]interface{}{"role": "superadmin"}
+
+	cases := []struct {
+		name     string
+		input    policy.Input
+		allowed  bool
+		policyID string
+	}{
+		{
+			name: "admin in same org",
+			input: policy.Input{Subject: admin, Action: "users:write",
+				Resource: map[string]interface{}{"type": "user", "org_id": float64(1)}},
+			allowed:  true,
+			policyID: "admins-manage-own-org",
+		},
+		{
+			name: "admin in another org",
+			input: policy.Input{Subject: admin, Action: "users:write",
+				Resource: map[string]interface{}{"type": "user", "org_id": float64(2)}},
+			allowed: false,
+		},
+		{
+			name: "deny overrides allow",
+			input: policy.Input{Subject: superadmin, Action: "users:delete",
+				Resource: map[string]interface{}{"type": "user", "role": "superadmin"}},
+			allowed:  false,
+			policyID: "nobody-deletes-superadmins",
+		},
+		{
+			name: "deny failing to evaluate",
+			input: policy.Input{Subject: superadmin, Action: "users:purge",
+				Resource: map[string]interface{}{"type": "user", "org_id": float64(1)}},
+			allowed:  false,
+			policyID: "broken-purge-rule",
+		},
+		{
+			name:    "default deny",
+			input:   policy.Input{Subject: map[string]interface{}{"role": "general-user"}, Action: "users:read"},
+			allowed: false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			decision := engine.Evaluate(c.input)
+			if decision.Allowed != c.allowed || decision.PolicyID != c.policyID {
+				t.Errorf("Evaluate() = %+v, expected allowed=%v policy=%q", decision, c.allowed, c.policyID)
+			}
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policies.json")
+	content := `[{"id": "p1", "effect": "allow", "actions": ["users:read"], "condition": "subject.role == 'admin'"}]`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	policies, err := policy.LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(policies) != 1 || policies[0].ID != "p1" || policies[0].Effect != policy.Allow {
+		t.Errorf("unexpected policies %+v", policies)
+	}
+
+	if _, err := policy.NewEngine([]policy.Policy{{ID: "bad", Effect: "maybe"}}); err == nil {
+		t.Error("expected an error for an unknown effect")
+	}
+}
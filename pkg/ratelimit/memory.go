@@ -0,0 +1,54 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepEvery is how many takes a MemoryStore serves between removals of its full buckets.
+const sweepEvery = 1024
+
+type memoryBucket struct {
+	Bucket
+	limit Limit
+}
+
+// MemoryStore keeps the buckets in process memory. It suits a single instance; deployments running several
+// instances need a shared store so the limits hold across them.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+	takes   int
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*memoryBucket{}}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.takes++
+	if s.takes%sweepEvery == 0 {
+		s.sweep(now)
+	}
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &memoryBucket{Bucket: NewBucket(limit, now)}
+		s.buckets[key] = bucket
+	}
+	bucket.limit = limit
+	return bucket.Take(limit, now), nil
+}
+
+// sweep drops the buckets that refilled completely, which behave like new ones.
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, bucket := range s.buckets {
+		if bucket.Full(bucket.limit, now) {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// Package ratelimit implements token bucket rate limiting over pluggable bucket storage.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit allows Burst requests at once, refilled at a steady pace of Burst requests every Per.
+type Limit struct {
+	Burst int
+	Per   time.Duration
+}
+
+// ParseLimit reads a limit written as "<burst>/<duration>", for example "10/1m".
+func ParseLimit(value string) (Limit, error) {
+	burst, per, ok := strings.Cut(value, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("rate limit %q must be written as <burst>/<duration>", value)
+	}
+
+	limit := Limit{}
+	var err error
+	if limit.Burst, err = strconv.Atoi(burst); err != nil || limit.Burst <= 0 {
+		return Limit{}, fmt.Errorf("rate limit %q must have a positive burst", value)
+	}
+	if limit.Per, err = time.ParseDuration(per); err != nil || limit.Per <= 0 {
+		return Limit{}, fmt.Errorf("rate limit %q must have a positive duration", value)
+	}
+	return limit, nil
+}
+
+// rate is the number of tokens added per second.
+func (l Limit) rate() float64 {
+	return float64(l.Burst) / l.Per.Seconds()
+}
+
+// Bucket is the state of a token bucket as persisted by a Store.
+type Bucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// Result is the outcome of taking a token. RetryAfter is how long until the next token when the request
+// is not allowed.
+type Result struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+// NewBucket returns the full bucket a key starts with.
+func NewBucket(limit Limit, now time.Time) Bucket {
+	return Bucket{Tokens: float64(limit.Burst), UpdatedAt: now}
+}
+
+// Take refills the bucket for the time elapsed since it was last updated and takes a token if one is left.
+func (b *Bucket) Take(limit Limit, now time.Time) Result {
+	if elapsed := now.Sub(b.UpdatedAt); elapsed > 0 {
+		b.Tokens = math.Min(float64(limit.Burst), b.Tokens+elapsed.Seconds()*limit.rate())
+		b.UpdatedAt = now
+	}
+
+	if b.Tokens < 1 {
+		// Rounded to the millisecond so float errors do not push the wait past a whole second.
+		wait := time.Duration((1 - b.Tokens) / limit.rate() * float64(time.Second)).Round(time.Millisecond)
+		return Result{RetryAfter: max(wait, time.Millisecond)}
+	}
+	b.Tokens--
+	return Result{Allowed: true, Remaining: int(b.Tokens)}
+}
+
+// Full reports whether the bucket has refilled completely at now, so a store can forget it.
+func (b *Bucket) Full(limit Limit, now time.Time) bool {
+	return b.Tokens+now.Sub(b.UpdatedAt).Seconds()*limit.rate() >= float64(limit.Burst)
+}
+
+// Store takes tokens from the bucket of key. Implementations must make the read-modify-write of a bucket
+// atomic, so concurrent requests on several instances share the same budget.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
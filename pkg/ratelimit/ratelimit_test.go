@@ -0,0 +1,61 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fyfirman/auth-management-go/pkg/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLimit(t *testing.T) {
+	limit, err := ratelimit.ParseLimit("10/1m")
+	assert.NoError(t, err)
+	assert.Equal(t, ratelimit.Limit{Burst: 10, Per: time.Minute}, limit)
+
+	for _, value := range []string{"10", "0/1m", "10/0s", "ten/1m", "10/forever"} {
+		_, err := ratelimit.ParseLimit(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestBucket_Take(t *testing.T) {
+	limit := ratelimit.Limit{Burst: 2, Per: time.Minute}
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	bucket := ratelimit.NewBucket(limit, now)
+
+	assert.Equal(t, ratelimit.Result{Allowed: true, Remaining: 1}, bucket.Take(limit, now))
+	assert.Equal(t, ratelimit.Result{Allowed: true, Remaining: 0}, bucket.Take(limit, now))
+
+	// A token comes back every 30 seconds
+	denied := bucket.Take(limit, now.Add(10*time.Second))
+	assert.False(t, denied.Allowed)
+	assert.Equal(t, 20*time.Second, denied.RetryAfter)
+
+	assert.True(t, bucket.Take(limit, now.Add(30*time.Second)).Allowed)
+	assert.False(t, bucket.Take(limit, now.Add(30*time.Second)).Allowed)
+
+	// Refilling stops at the burst
+	assert.True(t, bucket.Full(limit, now.Add(time.Hour)))
+	bucket.Take(limit, now.Add(time.Hour))
+	assert.Equal(t, 1.0, bucket.Tokens)
+}
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.TODO()
+	store := ratelimit.NewMemoryStore()
+	limit := ratelimit.Limit{Burst: 1, Per: time.Minute}
+	now := time.Now()
+
+	first, err := store.Take(ctx, "login:ip:203.0.113.7", limit, now)
+	assert.NoError(t, err)
+	assert.True(t, first.Allowed)
+
+	second, _ := store.Take(ctx, "login:ip:203.0.113.7", limit, now)
+	assert.False(t, second.Allowed)
+	assert.Equal(t, time.Minute, second.RetryAfter)
+
+	other, _ := store.Take(ctx, "login:ip:203.0.113.8", limit, now)
+	assert.True(t, other.Allowed)
+}
@@ -1,13 +1,42 @@
 package pkg
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 )
 
+// FieldError is a validation failure found outside the validator, such as a password breaking the password
+// policy. Message starts with the field name like the messages of validator errors.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// FieldErrors is an error listing FieldError values, rendered by PrepareValidationErrors.
+type FieldErrors []FieldError
+
+func (e FieldErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, fieldError := range e {
+		messages = append(messages, fieldError.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// PrepareValidationErrors lists the field and message of each failure of validator.ValidationErrors or
+// FieldErrors.
 func PrepareValidationErrors(err error) []map[string]string {
 	var errors []map[string]string
+	if fieldErrors, ok := err.(FieldErrors); ok {
+		for _, fieldError := range fieldErrors {
+			errors = append(errors, map[string]string{"field": fieldError.Field, "message": fieldError.Message})
+		}
+		return errors
+	}
 	for _, err := range err.(validator.ValidationErrors) {
 		var element = make(map[string]string)
 		element["field"] = err.Field()
@@ -31,7 +60,18 @@ func fieldErrorMessage(e validator.FieldError) string {
 		return fmt.Sprintf("%s must be equal to %s", e.Field(), e.Param())
 	case "alphanum":
 		return fmt.Sprintf("%s must contain alphanumeric characters only", e.Field())
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", e.Field(), e.Param())
 	default:
 		return fmt.Sprintf("%s is not valid", e.Field())
 	}
 }
+
+// WriteJSON encodes data as the JSON response body with the given status code.
+func WriteJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
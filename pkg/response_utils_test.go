@@ -1,6 +1,8 @@
 package pkg_test
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"testing"
 
@@ -88,6 +90,15 @@ func TestPrepareValidationErrors(t *testing.T) {
 	}
 }
 
+func TestPrepareValidationErrors_FieldErrors(t *testing.T) {
+	err := FieldErrors{{Field: "Password", Message: "Password is too easy to guess"}}
+
+	expected := []map[string]string{{"field": "Password", "message": "Password is too easy to guess"}}
+	if validationErrors := PrepareValidationErrors(err); !reflect.DeepEqual(validationErrors, expected) {
+		t.Errorf("Expected %v, got %v", expected, validationErrors)
+	}
+}
+
 func TestFieldErrorMessage(t *testing.T) {
 	cases := []struct {
 		tag      string
@@ -98,6 +109,7 @@ func TestFieldErrorMessage(t *testing.T) {
 		{"required", "Name", "", "Name is required"},
 		{"email", "Email", "", "Email is not a valid email address"},
 		{"min", "Password", "8", "Password must be at least 8 characters long"},
+		{"oneof", "Order", "asc desc", "Order must be one of: asc desc"},
 	}
 
 	for _, c := range cases {
@@ -113,3 +125,20 @@ func TestFieldErrorMessage(t *testing.T) {
 		}
 	}
 }
+
+func TestWriteJSON(t *testing.T) {
+	recorder := httptest.NewRecorder()
+
+	WriteJSON(recorder, http.StatusCreated, map[string]string{"message": "created"})
+
+	if recorder.Code != http.StatusCreated {
+		t.Errorf("Expected status %d, got %d", http.StatusCreated, recorder.Code)
+	}
+	if contentType := recorder.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("Expected application/json content type, got %s", contentType)
+	}
+	isSame, err := CompareJSONMaps(recorder.Body.String(), `{"message":"created"}`)
+	if !isSame || err != nil {
+		t.Errorf("Unexpected body %s", recorder.Body.String())
+	}
+}
@@ -0,0 +1,432 @@
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Filter is a parsed SCIM filter (RFC 7644 section 3.4.2.2). It supports:
+//
+//	comparison   eq ne co sw ew gt ge lt le pr
+//	logic        and or not ( )
+//	value paths  emails[type eq "work" and value co "@example.com"]
+//
+// Attribute names are case insensitive and may carry a schema URN prefix.
+type Filter interface {
+	// Match evaluates the filter against a resource decoded from JSON.
+	Match(resource map[string]interface{}) bool
+}
+
+// ParseFilter parses a filter expression. Errors wrap ErrInvalidFilter.
+func ParseFilter(source string) (Filter, error) {
+	tokens, err := tokenizeFilter(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &filterParser{tokens: tokens}
+	filter, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokenEOF {
+		return nil, invalidFilter("unexpected %q at position %d", p.peek().text, p.peek().pos)
+	}
+	return filter, nil
+}
+
+// Comparison compares an attribute with a value. Attribute is lower cased and holds the sub-attribute,
+// if any, after a dot.
+type Comparison struct {
+	Attribute string
+	Operator  string
+	Value     interface{}
+}
+
+// Logical combines two filters with "and" or "or".
+type Logical struct {
+	Operator string
+	Left     Filter
+	Right    Filter
+}
+
+type Not struct {
+	Filter Filter
+}
+
+// ValuePath applies Filter to the elements of a multi-valued attribute.
+type ValuePath struct {
+	Attribute string
+	Filter    Filter
+}
+
+func (c *Comparison) Match(resource map[string]interface{}) bool {
+	name, sub, _ := strings.Cut(c.Attribute, ".")
+	value, ok := lookup(resource, name)
+	if ok && sub != "" {
+		value, ok = subAttribute(value, sub)
+	}
+	if !ok {
+		// Missing attributes behave as null.
+		return compare(c.Operator, nil, c.Value)
+	}
+
+	if values, isList := value.([]interface{}); isList {
+		// A multi-valued attribute matches when any of its values does; complex values compare their "value".
+		for _, element := range values {
+			if object, isObject := element.(map[string]interface{}); isObject {
+				element, _ = lookup(object, "value")
+			}
+			if compare(c.Operator, element, c.Value) {
+				return true
+			}
+		}
+		return false
+	}
+	return compare(c.Operator, value, c.Value)
+}
+
+func (l *Logical) Match(resource map[string]interface{}) bool {
+	if l.Operator == "and" {
+		return l.Left.Match(resource) && l.Right.Match(resource)
+	}
+	return l.Left.Match(resource) || l.Right.Match(resource)
+}
+
+func (n *Not) Match(resource map[string]interface{}) bool {
+	return !n.Filter.Match(resource)
+}
+
+func (v *ValuePath) Match(resource map[string]interface{}) bool {
+	value, ok := lookup(resource, v.Attribute)
+	if !ok {
+		return false
+	}
+	values, isList := value.([]interface{})
+	if !isList {
+		values = []interface{}{value}
+	}
+	for _, element := range values {
+		if object, isObject := element.(map[string]interface{}); isObject && v.Filter.Match(object) {
+			return true
+		}
+	}
+	return false
+}
+
+func subAttribute(value interface{}, name string) (interface{}, bool) {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		return lookup(typed, name)
+	case []interface{}:
+		var values []interface{}
+		for _, element := range typed {
+			if object, ok := element.(map[string]interface{}); ok {
+				if sub, ok := lookup(object, name); ok {
+					values = append(values, sub)
+				}
+			}
+		}
+		return values, len(values) > 0
+	default:
+		return nil, false
+	}
+}
+
+// compare applies the SCIM operators. Strings compare case insensitively, which matches the caseExact
+// setting of the attributes this server exposes.
+func compare(operator string, actual interface{}, expected interface{}) bool {
+	if operator == "pr" {
+		return actual != nil && actual != ""
+	}
+
+	actualString, actualIsString := actual.(string)
+	expectedString, expectedIsString := expected.(string)
+	if actualIsString && expectedIsString {
+		actualString, expectedString = strings.ToLower(actualString), strings.ToLower(expectedString)
+		switch operator {
+		case "eq":
+			return actualString == expectedString
+		case "ne":
+			return actualString != expectedString
+		case "co":
+			return strings.Contains(actualString, expectedString)
+		case "sw":
+			return strings.HasPrefix(actualString, expectedString)
+		case "ew":
+			return strings.HasSuffix(actualString, expectedString)
+		case "gt":
+			return actualString > expectedString
+		case "ge":
+			return actualString >= expectedString
+		case "lt":
+			return actualString < expectedString
+		case "le":
+			return actualString <= expectedString
+		}
+		return false
+	}
+
+	actualNumber, actualIsNumber := actual.(float64)
+	expectedNumber, expectedIsNumber := expected.(float64)
+	if actualIsNumber && expectedIsNumber {
+		switch operator {
+		case "eq":
+			return actualNumber == expectedNumber
+		case "ne":
+			return actualNumber != expectedNumber
+		case "gt":
+			return actualNumber > expectedNumber
+		case "ge":
+			return actualNumber >= expectedNumber
+		case "lt":
+			return actualNumber < expectedNumber
+		case "le":
+			return actualNumber <= expectedNumber
+		}
+		return false
+	}
+
+	switch operator {
+	case "eq":
+		return actual == expected
+	case "ne":
+		return actual != expected
+	}
+	return false
+}
+
+// lookup reads an attribute by its case insensitive name.
+func lookup(resource map[string]interface{}, name string) (interface{}, bool) {
+	if key, ok := findKey(resource, name); ok {
+		return resource[key], true
+	}
+	return nil, false
+}
+
+func findKey(resource map[string]interface{}, name string) (string, bool) {
+	if _, ok := resource[name]; ok {
+		return name, true
+	}
+	for key := range resource {
+		if strings.EqualFold(key, name) {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// attributeName lower cases an attribute path and strips its schema URN, so
+// "urn:ietf:params:scim:schemas:core:2.0:User:userName" becomes "username".
+func attributeName(path string) string {
+	if i := strings.LastIndex(path, ":"); i >= 0 {
+		path = path[i+1:]
+	}
+	return strings.ToLower(path)
+}
+
+var comparisonOperators = map[string]bool{
+	"eq": true, "ne": true, "co": true, "sw": true, "ew": true, "gt": true, "ge": true, "lt": true, "le": true,
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func tokenizeFilter(source string) ([]token, error) {
+	var tokens []token
+	runes := []rune(source)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				if runes[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(runes) {
+				return nil, invalidFilter("unterminated string at position %d", i)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: string(runes[i : end+1]), pos: i})
+			i = end + 1
+		case r == '(' || r == ')' || r == '[' || r == ']':
+			tokens = append(tokens, token{kind: tokenPunct, text: string(r), pos: i})
+			i++
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune(`()[]"`, runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: string(runes[start:i]), pos: start})
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+type filterParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *filterParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *filterParser) keyword(word string) bool {
+	t := p.peek()
+	if t.kind == tokenWord && strings.EqualFold(t.text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) expect(punct string) error {
+	t := p.next()
+	if t.kind != tokenPunct || t.text != punct {
+		return invalidFilter("expected %q at position %d", punct, t.pos)
+	}
+	return nil
+}
+
+func (p *filterParser) parseOr() (Filter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Logical{Operator: "or", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (Filter, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &Logical{Operator: "and", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseNot() (Filter, error) {
+	if p.keyword("not") {
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		filter, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return &Not{Filter: filter}, nil
+	}
+	return p.parseAtom()
+}
+
+func (p *filterParser) parseAtom() (Filter, error) {
+	t := p.next()
+	if t.kind == tokenPunct && t.text == "(" {
+		filter, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return filter, nil
+	}
+	if t.kind != tokenWord {
+		return nil, invalidFilter("expected an attribute at position %d", t.pos)
+	}
+
+	attribute := attributeName(t.text)
+	if next := p.peek(); next.kind == tokenPunct && next.text == "[" {
+		p.next()
+		filter, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		return &ValuePath{Attribute: attribute, Filter: filter}, nil
+	}
+
+	operatorToken := p.next()
+	operator := strings.ToLower(operatorToken.text)
+	if operatorToken.kind == tokenWord && operator == "pr" {
+		return &Comparison{Attribute: attribute, Operator: operator}, nil
+	}
+	if operatorToken.kind != tokenWord || !comparisonOperators[operator] {
+		return nil, invalidFilter("expected an operator at position %d", operatorToken.pos)
+	}
+
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	return &Comparison{Attribute: attribute, Operator: operator, Value: value}, nil
+}
+
+// parseValue reads a JSON literal: a string, a number, true, false or null.
+func (p *filterParser) parseValue() (interface{}, error) {
+	t := p.next()
+	if t.kind != tokenString && t.kind != tokenWord {
+		return nil, invalidFilter("expected a value at position %d", t.pos)
+	}
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(t.text), &value); err != nil {
+		return nil, invalidFilter("invalid value %s at position %d", t.text, t.pos)
+	}
+	if _, isList := value.([]interface{}); isList {
+		return nil, invalidFilter("invalid value %s at position %d", t.text, t.pos)
+	}
+	if _, isObject := value.(map[string]interface{}); isObject {
+		return nil, invalidFilter("invalid value %s at position %d", t.text, t.pos)
+	}
+	return value, nil
+}
+
+func invalidFilter(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidFilter, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,263 @@
+package scim
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+type PatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// Path is a PATCH target such as "userName", "name.givenName" or `emails[type eq "work"].value`.
+// Attribute and SubAttribute are lower cased.
+type Path struct {
+	Attribute    string
+	Filter       Filter
+	SubAttribute string
+}
+
+// ParsePath parses a PATCH path. Errors wrap ErrInvalidPath or ErrInvalidFilter.
+func ParsePath(source string) (*Path, error) {
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return nil, fmt.Errorf("%w: empty path", ErrInvalidPath)
+	}
+
+	open := strings.Index(source, "[")
+	if open < 0 {
+		attribute, sub, _ := strings.Cut(attributeName(source), ".")
+		return &Path{Attribute: attribute, SubAttribute: sub}, nil
+	}
+
+	closing := strings.LastIndex(source, "]")
+	if closing < open {
+		return nil, fmt.Errorf("%w: unbalanced brackets in %q", ErrInvalidPath, source)
+	}
+	filter, err := ParseFilter(source[open+1 : closing])
+	if err != nil {
+		return nil, err
+	}
+
+	path := &Path{Attribute: attributeName(source[:open]), Filter: filter}
+	if rest := source[closing+1:]; rest != "" {
+		if !strings.HasPrefix(rest, ".") || len(rest) == 1 {
+			return nil, fmt.Errorf("%w: unexpected %q in %q", ErrInvalidPath, rest, source)
+		}
+		path.SubAttribute = strings.ToLower(rest[1:])
+	}
+	return path, nil
+}
+
+// Apply runs the operations, in order, against a resource decoded from JSON. Attribute names are matched
+// case insensitively. Removing through a filter that matches nothing is a no-op, so identity providers can
+// safely retry removals.
+func (r *PatchRequest) Apply(resource map[string]interface{}) error {
+	for _, operation := range r.Operations {
+		if err := operation.apply(resource); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o PatchOperation) apply(resource map[string]interface{}) error {
+	op := strings.ToLower(o.Op)
+	if op != "add" && op != "replace" && op != "remove" {
+		return fmt.Errorf("%w: unsupported operation %q", ErrInvalidValue, o.Op)
+	}
+
+	if o.Path == "" {
+		if op == "remove" {
+			return fmt.Errorf("%w: remove requires a path", ErrNoTarget)
+		}
+		values, ok := o.Value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%w: %s without a path requires an object", ErrInvalidValue, op)
+		}
+		for name, value := range values {
+			if _, isObject := value.(map[string]interface{}); isObject && strings.Contains(name, ":") {
+				// Schema extensions are not supported and are ignored.
+				continue
+			}
+			path, err := ParsePath(name)
+			if err != nil {
+				return err
+			}
+			if err := applyPath(resource, op, path, value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	path, err := ParsePath(o.Path)
+	if err != nil {
+		return err
+	}
+	return applyPath(resource, op, path, o.Value)
+}
+
+func applyPath(resource map[string]interface{}, op string, path *Path, value interface{}) error {
+	key, exists := findKey(resource, path.Attribute)
+	if !exists {
+		key = path.Attribute
+	}
+	current := resource[key]
+
+	if path.Filter != nil {
+		return applyFiltered(resource, key, op, path, value)
+	}
+
+	if path.SubAttribute != "" {
+		switch target := current.(type) {
+		case map[string]interface{}:
+			setAttribute(target, op, path.SubAttribute, value)
+		case []interface{}:
+			for _, element := range target {
+				if object, ok := element.(map[string]interface{}); ok {
+					setAttribute(object, op, path.SubAttribute, value)
+				}
+			}
+		case nil:
+			if op != "remove" {
+				resource[key] = map[string]interface{}{path.SubAttribute: value}
+			}
+		default:
+			return fmt.Errorf("%w: %q has no sub-attributes", ErrInvalidPath, path.Attribute)
+		}
+		return nil
+	}
+
+	switch op {
+	case "remove":
+		if list, isList := current.([]interface{}); isList && value != nil {
+			resource[key] = removeValues(list, value)
+		} else {
+			delete(resource, key)
+		}
+	case "add":
+		if list, isList := current.([]interface{}); isList {
+			if values, ok := value.([]interface{}); ok {
+				resource[key] = append(list, values...)
+			} else {
+				resource[key] = append(list, value)
+			}
+			return nil
+		}
+		mergeOrSet(resource, key, value)
+	case "replace":
+		mergeOrSet(resource, key, value)
+	}
+	return nil
+}
+
+func applyFiltered(resource map[string]interface{}, key string, op string, path *Path, value interface{}) error {
+	list, isList := resource[key].([]interface{})
+	if !isList {
+		if op == "remove" {
+			return nil
+		}
+		return fmt.Errorf("%w: %q is not a multi-valued attribute", ErrNoTarget, path.Attribute)
+	}
+
+	matched := false
+	kept := make([]interface{}, 0, len(list))
+	for _, element := range list {
+		object, isObject := element.(map[string]interface{})
+		if !isObject || !path.Filter.Match(object) {
+			kept = append(kept, element)
+			continue
+		}
+		matched = true
+
+		switch {
+		case op == "remove" && path.SubAttribute == "":
+			continue
+		case path.SubAttribute != "":
+			setAttribute(object, op, path.SubAttribute, value)
+		default:
+			values, ok := value.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("%w: %s on %q requires an object", ErrInvalidValue, op, path.Attribute)
+			}
+			for name, sub := range values {
+				setAttribute(object, op, name, sub)
+			}
+		}
+		kept = append(kept, object)
+	}
+
+	if !matched && op != "remove" {
+		return fmt.Errorf("%w: no %q value matches the filter", ErrNoTarget, path.Attribute)
+	}
+	resource[key] = kept
+	return nil
+}
+
+func setAttribute(object map[string]interface{}, op string, name string, value interface{}) {
+	key, exists := findKey(object, name)
+	if !exists {
+		key = name
+	}
+	if op == "remove" {
+		delete(object, key)
+		return
+	}
+	object[key] = value
+}
+
+// mergeOrSet merges objects into an existing complex attribute and replaces anything else.
+func mergeOrSet(resource map[string]interface{}, key string, value interface{}) {
+	existing, isObject := resource[key].(map[string]interface{})
+	values, valueIsObject := value.(map[string]interface{})
+	if !isObject || !valueIsObject {
+		resource[key] = value
+		return
+	}
+	for name, sub := range values {
+		setAttribute(existing, "replace", name, sub)
+	}
+}
+
+// removeValues drops the elements of list equal to value, or to one of its elements when value is a list.
+// Complex values are compared on their "value" sub-attribute.
+func removeValues(list []interface{}, value interface{}) []interface{} {
+	targets, isList := value.([]interface{})
+	if !isList {
+		targets = []interface{}{value}
+	}
+
+	kept := make([]interface{}, 0, len(list))
+	for _, element := range list {
+		removed := false
+		for _, target := range targets {
+			if sameValue(element, target) {
+				removed = true
+				break
+			}
+		}
+		if !removed {
+			kept = append(kept, element)
+		}
+	}
+	return kept
+}
+
+func sameValue(a interface{}, b interface{}) bool {
+	if object, ok := a.(map[string]interface{}); ok {
+		a, _ = lookup(object, "value")
+	}
+	if object, ok := b.(map[string]interface{}); ok {
+		b, _ = lookup(object, "value")
+	}
+	return reflect.DeepEqual(a, b)
+}
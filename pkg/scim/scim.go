@@ -0,0 +1,91 @@
+// Package scim implements the protocol pieces of SCIM 2.0 (RFC 7643, RFC 7644) that are independent of
+// how resources are stored: filters, PATCH operations, list responses and errors.
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+const (
+	SchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+	SchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+
+	ContentType = "application/scim+json"
+)
+
+var (
+	ErrInvalidFilter = errors.New("invalid filter")
+	ErrInvalidPath   = errors.New("invalid path")
+	ErrInvalidValue  = errors.New("invalid value")
+	ErrNoTarget      = errors.New("no target")
+)
+
+// ErrorType returns the scimType of an error wrapping one of the package errors, or "" otherwise.
+func ErrorType(err error) string {
+	switch {
+	case errors.Is(err, ErrInvalidFilter):
+		return "invalidFilter"
+	case errors.Is(err, ErrInvalidPath):
+		return "invalidPath"
+	case errors.Is(err, ErrInvalidValue):
+		return "invalidValue"
+	case errors.Is(err, ErrNoTarget):
+		return "noTarget"
+	default:
+		return ""
+	}
+}
+
+type Error struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+type ListResponse struct {
+	Schemas      []string    `json:"schemas"`
+	TotalResults int64       `json:"totalResults"`
+	StartIndex   int         `json:"startIndex"`
+	ItemsPerPage int         `json:"itemsPerPage"`
+	Resources    interface{} `json:"Resources"`
+}
+
+type Meta struct {
+	ResourceType string `json:"resourceType"`
+	Created      string `json:"created,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+	Location     string `json:"location,omitempty"`
+}
+
+// Boolean decodes booleans sent as JSON strings ("True", "false"), which some identity providers do.
+type Boolean bool
+
+func (b *Boolean) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case bool:
+		*b = Boolean(v)
+	case string:
+		switch strings.ToLower(v) {
+		case "true":
+			*b = true
+		case "false":
+			*b = false
+		default:
+			return ErrInvalidValue
+		}
+	default:
+		return ErrInvalidValue
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package scim_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/fyfirman/auth-management-go/pkg/scim"
+)
+
+func decode(t *testing.T, source string) map[string]interface{} {
+	t.Helper()
+	var resource map[string]interface{}
+	if err := json.Unmarshal([]byte(source), &resource); err != nil {
+		t.Fatal(err)
+	}
+	return resource
+}
+
+func TestParseFilter_Match(t *testing.T) {
+	user := decode(t, `{
+		"id": "12",
+		"userName": "Alice",
+		"active": true,
+		"emails": [{"value": "alice@example.com", "type": "work", "primary": true}],
+		"meta": {"created": "2024-04-08T10:00:00Z"}
+	}`)
+
+	cases := []struct {
+		filter   string
+		expected bool
+	}{
+		{`userName eq "alice"`, true},
+		{`urn:ietf:params:scim:schemas:core:2.0:User:userName eq "ALICE"`, true},
+		{`userName ne "alice"`, false},
+		{`userName sw "al" and active eq true`, true},
+		{`userName ew "x" or emails co "@example.com"`, true},
+		{`not (active eq true)`, false},
+		{`emails[type eq "work" and value ew "example.com"]`, true},
+		{`emails[type eq "home"]`, false},
+		{`emails.value eq "alice@example.com"`, true},
+		{`meta.created gt "2024-01-01T00:00:00Z"`, true},
+		{`externalId pr`, false},
+		{`externalId eq null`, true},
+		{`(userName eq "bob" or userName eq "alice") and id eq "12"`, true},
+	}
+
+	for _, c := range cases {
+		filter, err := scim.ParseFilter(c.filter)
+		if err != nil {
+			t.Errorf("ParseFilter(%q) returned error: %v", c.filter, err)
+			continue
+		}
+		if got := filter.Match(user); got != c.expected {
+			t.Errorf("%q matched %v, expected %v", c.filter, got, c.expected)
+		}
+	}
+}
+
+func TestParseFilter_Invalid(t *testing.T) {
+	for _, source := range []string{
+		``,
+		`userName`,
+		`userName xx "a"`,
+		`userName eq`,
+		`userName eq "unterminated`,
+		`(userName eq "a"`,
+		`emails[type eq "work"`,
+		`userName eq "a" extra`,
+		`userName eq [1]`,
+	} {
+		if _, err := scim.ParseFilter(source); !errors.Is(err, scim.ErrInvalidFilter) {
+			t.Errorf("ParseFilter(%q) returned %v, expected ErrInvalidFilter", source, err)
+		}
+	}
+}
+
+func TestToSQL(t *testing.T) {
+	columns := map[string]scim.Column{
+		"id":           {Name: "users.id", Type: scim.ColumnNumber},
+		"username":     {Name: "users.username"},
+		"externalid":   {Name: "users.external_id", CaseExact: true},
+		"emails.value": {Name: "users.email"},
+		"active":       {Name: "(NOT users.disabled)", Type: scim.ColumnBool},
+	}
+
+	cases := []struct {
+		filter   string
+		sql      string
+		argCount int
+	}{
+		{`userName eq "Alice"`, "LOWER(users.username) = LOWER(?)", 1},
+		{`externalId eq "abc" and active eq false`, "(users.external_id = ? AND (NOT users.disabled) = ?)", 2},
+		{`emails[value co "50%"]`, "users.email ILIKE ?", 1},
+		{`id eq "12" or not (externalId pr)`,
+			"(users.id = ? OR NOT ((users.external_id IS NOT NULL AND users.external_id <> '')))", 1},
+	}
+
+	for _, c := range cases {
+		filter, err := scim.ParseFilter(c.filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sql, args, err := scim.ToSQL(filter, columns)
+		if err != nil {
+			t.Errorf("ToSQL(%q) returned error: %v", c.filter, err)
+			continue
+		}
+		if sql != c.sql || len(args) != c.argCount {
+			t.Errorf("ToSQL(%q) = %q %v, expected %q with %d args", c.filter, sql, args, c.sql, c.argCount)
+		}
+	}
+
+	filter, _ := scim.ParseFilter(`emails[value co "50%"]`)
+	_, args, _ := scim.ToSQL(filter, columns)
+	if args[0] != `%50\%%` {
+		t.Errorf("expected LIKE pattern to be escaped, got %v", args[0])
+	}
+
+	for _, source := range []string{`title eq "x"`, `active co "t"`, `id eq "abc"`, `active gt true`} {
+		filter, _ := scim.ParseFilter(source)
+		if _, _, err := scim.ToSQL(filter, columns); !errors.Is(err, scim.ErrInvalidFilter) {
+			t.Errorf("ToSQL(%q) returned %v, expected ErrInvalidFilter", source, err)
+		}
+	}
+}
+
+func TestPatchRequest_Apply(t *testing.T) {
+	resource := decode(t, `{
+		"userName": "alice",
+		"active": true,
+		"emails": [{"value": "alice@example.com", "type": "work", "primary": true}],
+		"members": [{"value": "1"}, {"value": "2"}, {"value": "3"}]
+	}`)
+
+	var req scim.PatchRequest
+	err := json.Unmarshal([]byte(`{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+		"Operations": [
+			{"op": "Replace", "path": "active", "value": "False"},
+			{"op": "replace", "value": {"userName": "alice2", "name.givenName": "Alice"}},
+			{"op": "replace", "path": "emails[type eq \"work\"].value", "value": "alice2@example.com"},
+			{"op": "remove", "path": "members[value eq \"2\"]"},
+			{"op": "remove", "path": "members", "value": [{"value": "3"}]},
+			{"op": "add", "path": "members", "value": [{"value": "4"}]},
+			{"op": "remove", "path": "members[value eq \"99\"]"}
+		]
+	}`), &req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := req.Apply(resource); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := decode(t, `{
+		"userName": "alice2",
+		"active": "False",
+		"name": {"givenname": "Alice"},
+		"emails": [{"value": "alice2@example.com", "type": "work", "primary": true}],
+		"members": [{"value": "1"}, {"value": "4"}]
+	}`)
+	got, _ := json.Marshal(resource)
+	want, _ := json.Marshal(expected)
+	if string(got) != string(want) {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
+
+func TestPatchRequest_Apply_Errors(t *testing.T) {
+	cases := []struct {
+		operation scim.PatchOperation
+		expected  error
+	}{
+		{scim.PatchOperation{Op: "move", Path: "userName"}, scim.ErrInvalidValue},
+		{scim.PatchOperation{Op: "remove"}, scim.ErrNoTarget},
+		{scim.PatchOperation{Op: "replace", Value: "x"}, scim.ErrInvalidValue},
+		{scim.PatchOperation{Op: "replace", Path: `emails[type eq "home"].value`, Value: "x"}, scim.ErrNoTarget},
+		{scim.PatchOperation{Op: "replace", Path: `emails[type eq "work"`, Value: "x"}, scim.ErrInvalidPath},
+		{scim.PatchOperation{Op: "replace", Path: `emails[type eq]`, Value: "x"}, scim.ErrInvalidFilter},
+	}
+
+	for _, c := range cases {
+		resource := decode(t, `{"emails": [{"value": "alice@example.com", "type": "work"}]}`)
+		req := scim.PatchRequest{Operations: []scim.PatchOperation{c.operation}}
+		if err := req.Apply(resource); !errors.Is(err, c.expected) {
+			t.Errorf("%+v returned %v, expected %v", c.operation, err, c.expected)
+		}
+	}
+}
+
+func TestBoolean_UnmarshalJSON(t *testing.T) {
+	var values []scim.Boolean
+	if err := json.Unmarshal([]byte(`[true, "False", "TRUE"]`), &values); err != nil {
+		t.Fatal(err)
+	}
+	if values[0] != true || values[1] != false || values[2] != true {
+		t.Errorf("unexpected values %v", values)
+	}
+
+	var value scim.Boolean
+	if err := json.Unmarshal([]byte(`"yes"`), &value); err == nil {
+		t.Error("expected an error for a non boolean string")
+	}
+}
@@ -0,0 +1,155 @@
+package scim
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ColumnType int
+
+const (
+	ColumnText ColumnType = iota
+	ColumnNumber
+	ColumnBool
+	ColumnTime
+)
+
+// Column maps a filter attribute onto a SQL expression.
+type Column struct {
+	Name string
+	Type ColumnType
+	// CaseExact turns off the case insensitive comparison of text columns.
+	CaseExact bool
+}
+
+// ToSQL translates the filter into a SQL condition with "?" placeholders. Attributes are looked up in columns
+// by their lower cased name, with value path filters such as emails[value co "x"] looked up as "emails.value".
+// Attributes missing from columns are rejected with ErrInvalidFilter.
+func ToSQL(filter Filter, columns map[string]Column) (string, []interface{}, error) {
+	return toSQL(filter, columns, "")
+}
+
+func toSQL(filter Filter, columns map[string]Column, prefix string) (string, []interface{}, error) {
+	switch f := filter.(type) {
+	case *Logical:
+		left, leftArgs, err := toSQL(f.Left, columns, prefix)
+		if err != nil {
+			return "", nil, err
+		}
+		right, rightArgs, err := toSQL(f.Right, columns, prefix)
+		if err != nil {
+			return "", nil, err
+		}
+		return "(" + left + " " + strings.ToUpper(f.Operator) + " " + right + ")", append(leftArgs, rightArgs...), nil
+	case *Not:
+		condition, args, err := toSQL(f.Filter, columns, prefix)
+		if err != nil {
+			return "", nil, err
+		}
+		return "NOT (" + condition + ")", args, nil
+	case *ValuePath:
+		if prefix != "" {
+			return "", nil, invalidFilter("nested value paths are not supported")
+		}
+		return toSQL(f.Filter, columns, f.Attribute+".")
+	case *Comparison:
+		return comparisonSQL(f, columns, prefix)
+	default:
+		return "", nil, invalidFilter("unsupported filter")
+	}
+}
+
+func comparisonSQL(c *Comparison, columns map[string]Column, prefix string) (string, []interface{}, error) {
+	column, ok := columns[prefix+c.Attribute]
+	if !ok {
+		return "", nil, invalidFilter("filtering on %q is not supported", prefix+c.Attribute)
+	}
+	name := column.Name
+
+	if c.Operator == "pr" {
+		if column.Type == ColumnText {
+			return "(" + name + " IS NOT NULL AND " + name + " <> '')", nil, nil
+		}
+		return name + " IS NOT NULL", nil, nil
+	}
+	if c.Value == nil {
+		switch c.Operator {
+		case "eq":
+			return name + " IS NULL", nil, nil
+		case "ne":
+			return name + " IS NOT NULL", nil, nil
+		}
+		return "", nil, invalidFilter("%q cannot be compared with null", c.Operator)
+	}
+
+	value, err := columnValue(column, c.Value)
+	if err != nil {
+		return "", nil, err
+	}
+
+	switch c.Operator {
+	case "co", "sw", "ew":
+		text, isText := value.(string)
+		if column.Type != ColumnText || !isText {
+			return "", nil, invalidFilter("%q requires a string attribute", c.Operator)
+		}
+		pattern := escapeLike(text)
+		switch c.Operator {
+		case "co":
+			pattern = "%" + pattern + "%"
+		case "sw":
+			pattern = pattern + "%"
+		case "ew":
+			pattern = "%" + pattern
+		}
+		if column.CaseExact {
+			return name + " LIKE ?", []interface{}{pattern}, nil
+		}
+		return name + " ILIKE ?", []interface{}{pattern}, nil
+	}
+
+	operator := map[string]string{"eq": "=", "ne": "<>", "gt": ">", "ge": ">=", "lt": "<", "le": "<="}[c.Operator]
+	if column.Type == ColumnBool && operator != "=" && operator != "<>" {
+		return "", nil, invalidFilter("%q cannot be applied to a boolean", c.Operator)
+	}
+	if column.Type == ColumnText && !column.CaseExact {
+		return "LOWER(" + name + ") " + operator + " LOWER(?)", []interface{}{value}, nil
+	}
+	return name + " " + operator + " ?", []interface{}{value}, nil
+}
+
+// columnValue converts a filter value to the type of the column. SCIM ids are strings, so numeric
+// columns also accept numbers written as strings.
+func columnValue(column Column, value interface{}) (interface{}, error) {
+	switch column.Type {
+	case ColumnNumber:
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case string:
+			if number, err := strconv.ParseFloat(v, 64); err == nil {
+				return number, nil
+			}
+		}
+	case ColumnBool:
+		if v, ok := value.(bool); ok {
+			return v, nil
+		}
+	case ColumnTime:
+		if v, ok := value.(string); ok {
+			if t, err := time.Parse(time.RFC3339, v); err == nil {
+				return t, nil
+			}
+		}
+	default:
+		if v, ok := value.(string); ok {
+			return v, nil
+		}
+	}
+	return nil, invalidFilter("invalid value %v", value)
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}